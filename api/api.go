@@ -0,0 +1,6 @@
+// Package api defines the resource model shared by the store and all
+// generators. The message types themselves live in the pb subpackage; this
+// package adds the kind registry generated from the Resource spec oneof.
+package api
+
+//go:generate go run github.com/microrun/microrun/tools/gen-registry -out registry.gen.go
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/microrun/microrun/api/pb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"sigs.k8s.io/yaml"
+)
+
+// MarshalJSON renders a resource as indented JSON. The spec oneof appears
+// under a key named after its kind (e.g. "networkInterface"), so the
+// output is readable and editable by hand.
+func MarshalJSON(res *pb.Resource) ([]byte, error) {
+	opts := protojson.MarshalOptions{Multiline: true, Indent: "  "}
+	return opts.Marshal(res)
+}
+
+// UnmarshalJSON parses a resource from JSON produced by MarshalJSON.
+// Unknown fields are rejected so typos in hand-edited resources surface as
+// errors instead of silently disappearing.
+func UnmarshalJSON(data []byte) (*pb.Resource, error) {
+	res := &pb.Resource{}
+	if err := protojson.Unmarshal(data, res); err != nil {
+		return nil, fmt.Errorf("unmarshaling resource: %w", err)
+	}
+	return res, nil
+}
+
+// MarshalYAML renders a resource as YAML, using the same field names as
+// the JSON form.
+func MarshalYAML(res *pb.Resource) ([]byte, error) {
+	jsonBytes, err := protojson.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.JSONToYAML(jsonBytes)
+}
+
+// UnmarshalYAML parses a resource from YAML produced by MarshalYAML.
+func UnmarshalYAML(data []byte) (*pb.Resource, error) {
+	jsonBytes, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling resource: %w", err)
+	}
+	return UnmarshalJSON(jsonBytes)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/microrun/microrun/api/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+func testResources() []*pb.Resource {
+	return []*pb.Resource{
+		{
+			Metadata: &pb.ResourceMetadata{Name: "eth0", Kind: KindNetworkInterface, Generation: 3},
+			Spec: &pb.Resource_NetworkInterface{NetworkInterface: &pb.NetworkInterface{
+				InterfaceName: "eth0",
+				MacAddress:    "aa:bb:cc:dd:ee:ff",
+				IpAddresses:   []string{"192.0.2.1/24", "2001:db8::1/64"},
+			}},
+		},
+		{
+			Metadata: &pb.ResourceMetadata{Name: "eth0-dhcp", Kind: KindDHCPClient, Owner: "dhcp"},
+			Spec: &pb.Resource_DhcpClient{DhcpClient: &pb.DHCPClient{
+				InterfaceRef: "eth0",
+				Enabled:      true,
+			}},
+		},
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for _, res := range testResources() {
+		data, err := MarshalJSON(res)
+		if err != nil {
+			t.Fatalf("MarshalJSON(%s): %v", res.GetMetadata().GetName(), err)
+		}
+		got, err := UnmarshalJSON(data)
+		if err != nil {
+			t.Fatalf("UnmarshalJSON(%s): %v", res.GetMetadata().GetName(), err)
+		}
+		if !proto.Equal(res, got) {
+			t.Errorf("round trip of %s changed the resource:\n%s",
+				res.GetMetadata().GetName(), data)
+		}
+	}
+}
+
+func TestJSONSpecKey(t *testing.T) {
+	data, err := MarshalJSON(testResources()[0])
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	if !strings.Contains(string(data), `"networkInterface"`) {
+		t.Errorf("spec oneof not serialized under its kind key:\n%s", data)
+	}
+}
+
+func TestJSONRejectsUnknownFields(t *testing.T) {
+	if _, err := UnmarshalJSON([]byte(`{"metadata": {"nmae": "typo"}}`)); err == nil {
+		t.Error("UnmarshalJSON accepted an unknown field")
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	for _, res := range testResources() {
+		data, err := MarshalYAML(res)
+		if err != nil {
+			t.Fatalf("MarshalYAML(%s): %v", res.GetMetadata().GetName(), err)
+		}
+		got, err := UnmarshalYAML(data)
+		if err != nil {
+			t.Fatalf("UnmarshalYAML(%s): %v", res.GetMetadata().GetName(), err)
+		}
+		if !proto.Equal(res, got) {
+			t.Errorf("round trip of %s changed the resource:\n%s",
+				res.GetMetadata().GetName(), data)
+		}
+	}
+}
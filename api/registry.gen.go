@@ -0,0 +1,166 @@
+// Code generated by gen-registry. DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/microrun/microrun/api/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// Kind names for every resource type in the spec oneof of pb.Resource.
+const (
+	KindNetworkInterface   = "NetworkInterface"
+	KindDHCPClient         = "DHCPClient"
+	KindRoute              = "Route"
+	KindDHCPServer         = "DHCPServer"
+	KindBridge             = "Bridge"
+	KindVLAN               = "VLAN"
+	KindGeneratorStatus    = "GeneratorStatus"
+	KindNeighbor           = "Neighbor"
+	KindWireGuardInterface = "WireGuardInterface"
+)
+
+// KindFor returns the kind string for a spec message type.
+func KindFor(spec proto.Message) (string, error) {
+	switch spec.(type) {
+	case *pb.NetworkInterface:
+		return KindNetworkInterface, nil
+	case *pb.DHCPClient:
+		return KindDHCPClient, nil
+	case *pb.Route:
+		return KindRoute, nil
+	case *pb.DHCPServer:
+		return KindDHCPServer, nil
+	case *pb.Bridge:
+		return KindBridge, nil
+	case *pb.VLAN:
+		return KindVLAN, nil
+	case *pb.GeneratorStatus:
+		return KindGeneratorStatus, nil
+	case *pb.Neighbor:
+		return KindNeighbor, nil
+	case *pb.WireGuardInterface:
+		return KindWireGuardInterface, nil
+	}
+	return "", fmt.Errorf("no kind registered for spec type %T", spec)
+}
+
+// ExtractSpec returns the populated spec message of r.
+func ExtractSpec(r *pb.Resource) (proto.Message, error) {
+	switch s := r.GetSpec().(type) {
+	case *pb.Resource_NetworkInterface:
+		return s.NetworkInterface, nil
+	case *pb.Resource_DhcpClient:
+		return s.DhcpClient, nil
+	case *pb.Resource_Route:
+		return s.Route, nil
+	case *pb.Resource_DhcpServer:
+		return s.DhcpServer, nil
+	case *pb.Resource_Bridge:
+		return s.Bridge, nil
+	case *pb.Resource_Vlan:
+		return s.Vlan, nil
+	case *pb.Resource_GeneratorStatus:
+		return s.GeneratorStatus, nil
+	case *pb.Resource_Neighbor:
+		return s.Neighbor, nil
+	case *pb.Resource_WireGuardInterface:
+		return s.WireGuardInterface, nil
+	}
+	return nil, fmt.Errorf("resource %q has no spec set", r.GetMetadata().GetName())
+}
+
+// AllKinds returns every registered kind, in the order the spec oneof
+// declares them.
+func AllKinds() []string {
+	return []string{
+		KindNetworkInterface,
+		KindDHCPClient,
+		KindRoute,
+		KindDHCPServer,
+		KindBridge,
+		KindVLAN,
+		KindGeneratorStatus,
+		KindNeighbor,
+		KindWireGuardInterface,
+	}
+}
+
+// NewResourceForKind returns an empty resource of the given kind with
+// metadata.kind and a zero spec of the matching type set.
+func NewResourceForKind(kind string) (*pb.Resource, error) {
+	r := &pb.Resource{Metadata: &pb.ResourceMetadata{Kind: kind}}
+	switch kind {
+	case KindNetworkInterface:
+		r.Spec = &pb.Resource_NetworkInterface{NetworkInterface: &pb.NetworkInterface{}}
+	case KindDHCPClient:
+		r.Spec = &pb.Resource_DhcpClient{DhcpClient: &pb.DHCPClient{}}
+	case KindRoute:
+		r.Spec = &pb.Resource_Route{Route: &pb.Route{}}
+	case KindDHCPServer:
+		r.Spec = &pb.Resource_DhcpServer{DhcpServer: &pb.DHCPServer{}}
+	case KindBridge:
+		r.Spec = &pb.Resource_Bridge{Bridge: &pb.Bridge{}}
+	case KindVLAN:
+		r.Spec = &pb.Resource_Vlan{Vlan: &pb.VLAN{}}
+	case KindGeneratorStatus:
+		r.Spec = &pb.Resource_GeneratorStatus{GeneratorStatus: &pb.GeneratorStatus{}}
+	case KindNeighbor:
+		r.Spec = &pb.Resource_Neighbor{Neighbor: &pb.Neighbor{}}
+	case KindWireGuardInterface:
+		r.Spec = &pb.Resource_WireGuardInterface{WireGuardInterface: &pb.WireGuardInterface{}}
+	default:
+		return nil, fmt.Errorf("no kind registered for %q", kind)
+	}
+	return r, nil
+}
+
+// SetSpec stores spec into the spec oneof of r.
+func SetSpec(r *pb.Resource, spec proto.Message) error {
+	switch s := spec.(type) {
+	case *pb.NetworkInterface:
+		r.Spec = &pb.Resource_NetworkInterface{NetworkInterface: s}
+	case *pb.DHCPClient:
+		r.Spec = &pb.Resource_DhcpClient{DhcpClient: s}
+	case *pb.Route:
+		r.Spec = &pb.Resource_Route{Route: s}
+	case *pb.DHCPServer:
+		r.Spec = &pb.Resource_DhcpServer{DhcpServer: s}
+	case *pb.Bridge:
+		r.Spec = &pb.Resource_Bridge{Bridge: s}
+	case *pb.VLAN:
+		r.Spec = &pb.Resource_Vlan{Vlan: s}
+	case *pb.GeneratorStatus:
+		r.Spec = &pb.Resource_GeneratorStatus{GeneratorStatus: s}
+	case *pb.Neighbor:
+		r.Spec = &pb.Resource_Neighbor{Neighbor: s}
+	case *pb.WireGuardInterface:
+		r.Spec = &pb.Resource_WireGuardInterface{WireGuardInterface: s}
+	default:
+		return fmt.Errorf("no kind registered for spec type %T", spec)
+	}
+	return nil
+}
+
+// ExtractStatus returns the populated status message of r, or nil when
+// the resource has no status set.
+func ExtractStatus(r *pb.Resource) proto.Message {
+	switch s := r.GetStatus().(type) {
+	case *pb.Resource_NetworkInterfaceStatus:
+		return s.NetworkInterfaceStatus
+	}
+	return nil
+}
+
+// SetStatus stores status into the status oneof of r.
+func SetStatus(r *pb.Resource, status proto.Message) error {
+	switch s := status.(type) {
+	case *pb.NetworkInterfaceStatus:
+		r.Status = &pb.Resource_NetworkInterfaceStatus{NetworkInterfaceStatus: s}
+	default:
+		return fmt.Errorf("no status type registered for %T", status)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestAllKindsListsEveryKind(t *testing.T) {
+	kinds := AllKinds()
+	want := map[string]bool{KindNetworkInterface: true, KindDHCPClient: true}
+	for _, kind := range kinds {
+		delete(want, kind)
+	}
+	for kind := range want {
+		t.Errorf("AllKinds is missing %s", kind)
+	}
+	seen := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		if seen[kind] {
+			t.Errorf("AllKinds lists %s twice", kind)
+		}
+		seen[kind] = true
+	}
+}
+
+func TestNewResourceForKind(t *testing.T) {
+	for _, kind := range AllKinds() {
+		res, err := NewResourceForKind(kind)
+		if err != nil {
+			t.Fatalf("NewResourceForKind(%s): %v", kind, err)
+		}
+		if got := res.GetMetadata().GetKind(); got != kind {
+			t.Errorf("metadata.kind = %q, want %q", got, kind)
+		}
+		spec, err := ExtractSpec(res)
+		if err != nil {
+			t.Errorf("ExtractSpec on %s resource: %v", kind, err)
+			continue
+		}
+		if got, err := KindFor(spec); err != nil || got != kind {
+			t.Errorf("KindFor(spec) = %q, %v, want %q", got, err, kind)
+		}
+	}
+}
+
+func TestNewResourceForKindUnknown(t *testing.T) {
+	if _, err := NewResourceForKind("NoSuchKind"); err == nil {
+		t.Error("NewResourceForKind accepted an unknown kind")
+	}
+}
@@ -0,0 +1,124 @@
+// Package clock abstracts time behind an interface so time-dependent
+// logic — reconcile tickers, TTL bookkeeping, timestamps — can be tested
+// by advancing a fake clock instead of sleeping.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the slice of the time package that microrun components use.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	Ticker(d time.Duration) Ticker
+}
+
+// Ticker is the clock-agnostic counterpart of time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real is the Clock backed by the actual wall clock.
+type Real struct{}
+
+func (Real) Now() time.Time                         { return time.Now() }
+func (Real) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (Real) Ticker(d time.Duration) Ticker          { return &realTicker{ticker: time.NewTicker(d)} }
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t *realTicker) C() <-chan time.Time { return t.ticker.C }
+func (t *realTicker) Stop()               { t.ticker.Stop() }
+
+// Fake is a manually advanced Clock for tests. Time only moves when
+// Advance is called; pending After channels and tickers whose deadline
+// has passed fire during the call, on the caller's goroutine.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter is one pending After channel or ticker. A zero period marks
+// a one-shot After.
+type fakeWaiter struct {
+	deadline time.Time
+	period   time.Duration
+	ch       chan time.Time
+	stopped  bool
+}
+
+// NewFake returns a fake clock frozen at start.
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &fakeWaiter{deadline: f.now.Add(d), ch: make(chan time.Time, 1)}
+	f.waiters = append(f.waiters, w)
+	return w.ch
+}
+
+func (f *Fake) Ticker(d time.Duration) Ticker {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &fakeWaiter{deadline: f.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	f.waiters = append(f.waiters, w)
+	return &fakeTicker{clock: f, waiter: w}
+}
+
+// Advance moves the clock forward by d and fires everything that came
+// due. Like the real time.Ticker, a fake ticker coalesces: advancing
+// across several periods at once delivers a single tick.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+
+	kept := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.stopped {
+			continue
+		}
+		if !w.deadline.After(f.now) {
+			select {
+			case w.ch <- f.now:
+			default:
+			}
+			if w.period == 0 {
+				continue
+			}
+			for !w.deadline.After(f.now) {
+				w.deadline = w.deadline.Add(w.period)
+			}
+		}
+		kept = append(kept, w)
+	}
+	f.waiters = kept
+}
+
+type fakeTicker struct {
+	clock  *Fake
+	waiter *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.waiter.ch }
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.waiter.stopped = true
+}
@@ -0,0 +1,53 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeAfterFiresOnAdvance(t *testing.T) {
+	fake := NewFake(time.Unix(0, 0))
+	ch := fake.After(time.Minute)
+
+	fake.Advance(30 * time.Second)
+	select {
+	case <-ch:
+		t.Fatal("After fired before its deadline")
+	default:
+	}
+
+	fake.Advance(30 * time.Second)
+	select {
+	case now := <-ch:
+		if want := time.Unix(60, 0); !now.Equal(want) {
+			t.Errorf("fired at %v, want %v", now, want)
+		}
+	default:
+		t.Fatal("After did not fire at its deadline")
+	}
+}
+
+func TestFakeTickerCoalescesAndStops(t *testing.T) {
+	fake := NewFake(time.Unix(0, 0))
+	ticker := fake.Ticker(time.Second)
+
+	fake.Advance(5 * time.Second)
+	select {
+	case <-ticker.C():
+	default:
+		t.Fatal("ticker did not fire")
+	}
+	select {
+	case <-ticker.C():
+		t.Fatal("ticker delivered more than one coalesced tick")
+	default:
+	}
+
+	ticker.Stop()
+	fake.Advance(5 * time.Second)
+	select {
+	case <-ticker.C():
+		t.Fatal("stopped ticker fired")
+	default:
+	}
+}
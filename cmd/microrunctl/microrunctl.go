@@ -0,0 +1,156 @@
+// microrunctl inspects and edits the resource store of a running runtimed
+// through its gRPC store service.
+//
+// Usage:
+//
+//	microrunctl [-addr host:port] get <kind> <name>
+//	microrunctl [-addr host:port] list <kind>
+//	microrunctl [-addr host:port] apply -f <file.yaml>
+//	microrunctl [-addr host:port] delete <kind> <name>
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:9091", "address of the runtimed store service")
+	flag.Parse()
+	if flag.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "usage: microrunctl [-addr host:port] get|list|apply|delete ...")
+		os.Exit(2)
+	}
+
+	conn, err := grpc.Dial(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "connecting:", err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+	client := pb.NewStoreServiceClient(conn)
+
+	ctx := context.Background()
+	args := flag.Args()
+	switch args[0] {
+	case "get":
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "usage: microrunctl get <kind> <name>")
+			os.Exit(2)
+		}
+		err = runGet(ctx, client, os.Stdout, args[1], args[2])
+	case "list":
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "usage: microrunctl list <kind>")
+			os.Exit(2)
+		}
+		err = runList(ctx, client, os.Stdout, args[1])
+	case "apply":
+		fs := flag.NewFlagSet("apply", flag.ExitOnError)
+		file := fs.String("f", "", "file containing the resource to apply")
+		fs.Parse(args[1:])
+		if *file == "" {
+			fmt.Fprintln(os.Stderr, "usage: microrunctl apply -f <file.yaml>")
+			os.Exit(2)
+		}
+		var data []byte
+		if data, err = os.ReadFile(*file); err == nil {
+			err = runApply(ctx, client, os.Stdout, data)
+		}
+	case "delete":
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "usage: microrunctl delete <kind> <name>")
+			os.Exit(2)
+		}
+		err = runDelete(ctx, client, os.Stdout, args[1], args[2])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", args[0])
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// runGet prints one resource as YAML.
+func runGet(ctx context.Context, client pb.StoreServiceClient, out io.Writer, kind, name string) error {
+	res, err := client.Get(ctx, &pb.GetRequest{Kind: kind, Name: name})
+	if err != nil {
+		return err
+	}
+	return printYAML(out, res)
+}
+
+// runList prints all resources of a kind as a stream of YAML documents.
+func runList(ctx context.Context, client pb.StoreServiceClient, out io.Writer, kind string) error {
+	resp, err := client.List(ctx, &pb.ListRequest{Kind: kind})
+	if err != nil {
+		return err
+	}
+	for i, res := range resp.GetResources() {
+		if i > 0 {
+			fmt.Fprintln(out, "---")
+		}
+		if err := printYAML(out, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runApply parses a YAML resource and creates it when it does not exist
+// yet or updates it when it does.
+func runApply(ctx context.Context, client pb.StoreServiceClient, out io.Writer, data []byte) error {
+	res, err := api.UnmarshalYAML(data)
+	if err != nil {
+		return err
+	}
+	kind := res.GetMetadata().GetKind()
+	name := res.GetMetadata().GetName()
+
+	_, err = client.Get(ctx, &pb.GetRequest{Kind: kind, Name: name})
+	switch {
+	case status.Code(err) == codes.NotFound:
+		if _, err := client.Create(ctx, &pb.CreateRequest{Resource: res}); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s/%s created\n", kind, name)
+		return nil
+	case err != nil:
+		return err
+	}
+	if _, err := client.Update(ctx, &pb.UpdateRequest{Resource: res}); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s/%s updated\n", kind, name)
+	return nil
+}
+
+// runDelete removes one resource.
+func runDelete(ctx context.Context, client pb.StoreServiceClient, out io.Writer, kind, name string) error {
+	if _, err := client.Delete(ctx, &pb.DeleteRequest{Kind: kind, Name: name}); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s/%s deleted\n", kind, name)
+	return nil
+}
+
+func printYAML(out io.Writer, res *pb.Resource) error {
+	data, err := api.MarshalYAML(res)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
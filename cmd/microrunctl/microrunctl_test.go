@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/server"
+	"github.com/microrun/microrun/state"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient serves a store service over an in-memory connection and
+// returns a client for it.
+func newTestClient(t *testing.T) pb.StoreServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	pb.RegisterStoreServiceServer(srv, server.NewStoreService(state.NewMemoryStore()))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dialing bufconn: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewStoreServiceClient(conn)
+}
+
+func TestApplyCreatesThenUpdates(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	res := &pb.Resource{
+		Metadata: &pb.ResourceMetadata{Name: "eth0", Kind: api.KindNetworkInterface},
+		Spec: &pb.Resource_NetworkInterface{NetworkInterface: &pb.NetworkInterface{
+			InterfaceName: "eth0",
+			AdminState:    "up",
+		}},
+	}
+	doc, err := api.MarshalYAML(res)
+	if err != nil {
+		t.Fatalf("MarshalYAML: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runApply(ctx, client, &out, doc); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if !strings.Contains(out.String(), "created") {
+		t.Errorf("first apply output = %q, want created", out.String())
+	}
+	got, err := client.Get(ctx, &pb.GetRequest{Kind: api.KindNetworkInterface, Name: "eth0"})
+	if err != nil {
+		t.Fatalf("Get after create: %v", err)
+	}
+	if got.GetNetworkInterface().GetAdminState() != "up" {
+		t.Errorf("admin state = %q, want up", got.GetNetworkInterface().GetAdminState())
+	}
+
+	// Applying a changed spec updates in place.
+	res.GetNetworkInterface().AdminState = "down"
+	doc, err = api.MarshalYAML(res)
+	if err != nil {
+		t.Fatalf("MarshalYAML: %v", err)
+	}
+	out.Reset()
+	if err := runApply(ctx, client, &out, doc); err != nil {
+		t.Fatalf("second apply: %v", err)
+	}
+	if !strings.Contains(out.String(), "updated") {
+		t.Errorf("second apply output = %q, want updated", out.String())
+	}
+	got, err = client.Get(ctx, &pb.GetRequest{Kind: api.KindNetworkInterface, Name: "eth0"})
+	if err != nil {
+		t.Fatalf("Get after update: %v", err)
+	}
+	if got.GetNetworkInterface().GetAdminState() != "down" {
+		t.Errorf("admin state after update = %q, want down", got.GetNetworkInterface().GetAdminState())
+	}
+	if got.GetMetadata().GetGeneration() != 2 {
+		t.Errorf("generation after update = %d, want 2", got.GetMetadata().GetGeneration())
+	}
+}
+
+func TestDeleteRemovesResource(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	res := &pb.Resource{
+		Metadata: &pb.ResourceMetadata{Name: "eth0", Kind: api.KindNetworkInterface},
+		Spec: &pb.Resource_NetworkInterface{NetworkInterface: &pb.NetworkInterface{
+			InterfaceName: "eth0",
+		}},
+	}
+	if _, err := client.Create(ctx, &pb.CreateRequest{Resource: res}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runDelete(ctx, client, &out, api.KindNetworkInterface, "eth0"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := client.Get(ctx, &pb.GetRequest{Kind: api.KindNetworkInterface, Name: "eth0"}); err == nil {
+		t.Error("Get after delete succeeded, want error")
+	}
+}
@@ -0,0 +1,122 @@
+// runtimed is the microrun daemon. It hosts the resource store and the
+// registered generators until it receives SIGINT or SIGTERM.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/configloader"
+	"github.com/microrun/microrun/logging"
+	"github.com/microrun/microrun/network"
+	"github.com/microrun/microrun/runtime"
+	"github.com/microrun/microrun/server"
+	"github.com/microrun/microrun/state"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	healthAddr := flag.String("health-addr", "127.0.0.1:9090", "listen address for the health endpoints")
+	grpcAddr := flag.String("grpc-addr", "127.0.0.1:9091", "listen address for the gRPC store service")
+	reapInterval := flag.Duration("reap-interval", 30*time.Second, "how often expired resources are reaped")
+	configDir := flag.String("config-dir", "", "directory of resource files to load at startup (and on SIGHUP)")
+	flag.Parse()
+
+	logger := logging.NewLogger("runtimed")
+
+	store := state.NewMemoryStore()
+	rt := runtime.New(store)
+
+	if err := rt.RegisterGeneratorTolerant(network.InterfaceGeneratorType{}); err != nil {
+		logger.Error("registering interface generator", "error", err)
+		os.Exit(1)
+	}
+	if err := rt.RegisterGeneratorTolerant(network.RouteGeneratorType{}); err != nil {
+		logger.Error("registering route generator", "error", err)
+		os.Exit(1)
+	}
+	if err := rt.RegisterGeneratorTolerant(network.DHCPServerGeneratorType{}); err != nil {
+		logger.Error("registering dhcp server generator", "error", err)
+		os.Exit(1)
+	}
+	if err := rt.RegisterGeneratorTolerant(network.BridgeGeneratorType{}); err != nil {
+		logger.Error("registering bridge generator", "error", err)
+		os.Exit(1)
+	}
+	if err := rt.RegisterGeneratorTolerant(network.VLANGeneratorType{}); err != nil {
+		logger.Error("registering vlan generator", "error", err)
+		os.Exit(1)
+	}
+	if err := rt.RegisterGeneratorTolerant(network.NeighborGeneratorType{}); err != nil {
+		logger.Error("registering neighbor generator", "error", err)
+		os.Exit(1)
+	}
+	if err := rt.RegisterGeneratorTolerant(network.WireGuardGeneratorType{}); err != nil {
+		logger.Error("registering wireguard generator", "error", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	lis, err := net.Listen("tcp", *grpcAddr)
+	if err != nil {
+		logger.Error("listening for grpc", "error", err)
+		os.Exit(1)
+	}
+	grpcServer := grpc.NewServer()
+	pb.RegisterStoreServiceServer(grpcServer, server.NewStoreService(store))
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			logger.Error("grpc server", "error", err)
+		}
+	}()
+
+	if *configDir != "" {
+		loader := configloader.New(store, *configDir)
+		if err := loader.Load(ctx); err != nil {
+			logger.Error("loading config resources", "error", err)
+			os.Exit(1)
+		}
+		go loader.ReloadOnSIGHUP(ctx)
+	}
+
+	reaper := state.NewReaper(store, *reapInterval)
+	go func() {
+		if err := reaper.Run(ctx); err != nil {
+			logger.Error("resource reaper", "error", err)
+		}
+	}()
+
+	health := &http.Server{Addr: *healthAddr, Handler: rt.HealthHandler()}
+	go func() {
+		if err := health.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("health server", "error", err)
+		}
+	}()
+
+	if err := rt.Start(ctx); err != nil {
+		logger.Error("starting runtime", "error", err)
+		os.Exit(1)
+	}
+	logger.Info("runtimed started")
+
+	<-ctx.Done()
+	logger.Info("shutting down")
+	stopCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	rt.Stop(stopCtx)
+	grpcServer.GracefulStop()
+	health.Shutdown(stopCtx)
+	if err := store.Close(); err != nil {
+		logger.Error("closing store", "error", err)
+	}
+}
@@ -0,0 +1,160 @@
+// Package configloader seeds the resource store from files on disk, so
+// runtimed can boot with desired state (DHCP clients, static routes)
+// without waiting for an external client to push it.
+package configloader
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/logging"
+	"github.com/microrun/microrun/state"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+// Loader reads resource files from a directory and applies them to the
+// store. Files ending in .textpb are parsed as prototext, .yaml and .yml
+// as the YAML form api.MarshalYAML produces; other files are ignored.
+type Loader struct {
+	store  state.Store
+	dir    string
+	logger *slog.Logger
+}
+
+// New returns a loader applying the resource files in dir to store.
+func New(store state.Store, dir string) *Loader {
+	return &Loader{store: store, dir: dir, logger: logging.NewLogger("configloader")}
+}
+
+// Load parses every resource file in the directory, in sorted order, and
+// creates each resource in the store — or updates it when it already
+// exists, so a reload converges on the files' state. A bad file does not
+// stop the rest; failures are aggregated into a MultiError, parse errors
+// keyed by file name and store errors by kind/name.
+func (l *Loader) Load(ctx context.Context) error {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return fmt.Errorf("reading config directory %s: %w", l.dir, err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	var multi state.MultiError
+	for _, name := range names {
+		res, err := l.parseFile(name)
+		if err != nil {
+			multi.Add("file", name, err)
+			continue
+		}
+		if res == nil {
+			continue
+		}
+		if err := l.apply(ctx, res); err != nil {
+			multi.Add(res.GetMetadata().GetKind(), res.GetMetadata().GetName(), err)
+			continue
+		}
+		l.logger.Info("loaded resource", "file", name,
+			"kind", res.GetMetadata().GetKind(), "name", res.GetMetadata().GetName())
+	}
+	return multi.ErrOrNil()
+}
+
+// ReloadOnSIGHUP re-runs Load every time the process receives SIGHUP,
+// until ctx is done. Reload failures are logged, not fatal: the store
+// keeps the last good state.
+func (l *Loader) ReloadOnSIGHUP(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			l.logger.Info("reloading config resources", "dir", l.dir)
+			if err := l.Load(ctx); err != nil {
+				l.logger.Error("reloading config resources", "error", err)
+			}
+		}
+	}
+}
+
+// parseFile reads and validates one resource file, returning nil for
+// files whose extension the loader does not handle.
+func (l *Loader) parseFile(name string) (*pb.Resource, error) {
+	data, err := os.ReadFile(filepath.Join(l.dir, name))
+	if err != nil {
+		return nil, err
+	}
+	var res *pb.Resource
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".textpb":
+		res = &pb.Resource{}
+		if err := prototext.Unmarshal(data, res); err != nil {
+			return nil, fmt.Errorf("parsing prototext: %w", err)
+		}
+	case ".yaml", ".yml":
+		res, err = api.UnmarshalYAML(data)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+	return res, validate(res)
+}
+
+// validate rejects resources the store would refuse anyway, so the error
+// carries the file context instead of surfacing later from a write.
+func validate(res *pb.Resource) error {
+	if res.GetMetadata().GetName() == "" {
+		return fmt.Errorf("resource has no metadata.name")
+	}
+	kind := res.GetMetadata().GetKind()
+	if kind == "" {
+		return fmt.Errorf("resource has no metadata.kind")
+	}
+	spec, err := api.ExtractSpec(res)
+	if err != nil {
+		return err
+	}
+	specKind, err := api.KindFor(spec)
+	if err != nil {
+		return err
+	}
+	if specKind != kind {
+		return fmt.Errorf("metadata.kind is %s but the spec is a %s", kind, specKind)
+	}
+	return nil
+}
+
+// apply creates the resource, or replaces the spec of the existing one
+// on reload. The stored metadata wins, so reloads do not fight the
+// store's bookkeeping.
+func (l *Loader) apply(ctx context.Context, res *pb.Resource) error {
+	existing, err := l.store.Get(ctx, res.GetMetadata().GetKind(),
+		res.GetMetadata().GetNamespace(), res.GetMetadata().GetName())
+	if state.IsNotFound(err) {
+		return l.store.Create(ctx, res)
+	}
+	if err != nil {
+		return err
+	}
+	existing.Spec = res.Spec
+	return l.store.Update(ctx, existing)
+}
@@ -0,0 +1,120 @@
+package configloader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/state"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("writing %s: %v", name, err)
+	}
+}
+
+func TestLoadCreatesResourcesFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "dhcp.textpb", `
+metadata { name: "eth0" kind: "DHCPClient" }
+dhcp_client { interface_ref: "eth0" enabled: true }
+`)
+	writeFile(t, dir, "route.yaml", `
+metadata:
+  name: default
+  kind: Route
+route:
+  destination: 0.0.0.0/0
+  gateway: 10.0.0.1
+  interfaceRef: eth0
+`)
+	writeFile(t, dir, "README.md", "not a resource\n")
+
+	store := state.NewMemoryStore()
+	loader := New(store, dir)
+	ctx := context.Background()
+	if err := loader.Load(ctx); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	dhcp, err := store.Get(ctx, api.KindDHCPClient, state.DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get dhcp client: %v", err)
+	}
+	if !dhcp.GetDhcpClient().GetEnabled() {
+		t.Error("dhcp client spec lost enabled flag")
+	}
+	route, err := store.Get(ctx, api.KindRoute, state.DefaultNamespace, "default")
+	if err != nil {
+		t.Fatalf("Get route: %v", err)
+	}
+	if got := route.GetRoute().GetGateway(); got != "10.0.0.1" {
+		t.Errorf("route gateway = %q, want 10.0.0.1", got)
+	}
+}
+
+func TestLoadAggregatesBadFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "bad.textpb", "not prototext {{{")
+	writeFile(t, dir, "mismatched.yaml", `
+metadata:
+  name: eth0
+  kind: Route
+dhcpClient:
+  interfaceRef: eth0
+`)
+	writeFile(t, dir, "good.textpb", `
+metadata { name: "eth0" kind: "DHCPClient" }
+dhcp_client { interface_ref: "eth0" }
+`)
+
+	store := state.NewMemoryStore()
+	err := New(store, dir).Load(context.Background())
+	if err == nil {
+		t.Fatal("Load with bad files succeeded")
+	}
+	multi, ok := state.IsMultiError(err)
+	if !ok {
+		t.Fatalf("Load = %v, want MultiError", err)
+	}
+	if len(multi.Errors) != 2 {
+		t.Errorf("MultiError holds %d errors, want 2: %v", len(multi.Errors), multi)
+	}
+	// The good file still loaded.
+	if ok, err := store.Exists(context.Background(), api.KindDHCPClient, state.DefaultNamespace, "eth0"); err != nil || !ok {
+		t.Errorf("good resource missing after partial load: %v, %v", ok, err)
+	}
+}
+
+func TestLoadReloadUpdatesExisting(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "dhcp.textpb", `
+metadata { name: "eth0" kind: "DHCPClient" }
+dhcp_client { interface_ref: "eth0" enabled: true }
+`)
+	store := state.NewMemoryStore()
+	loader := New(store, dir)
+	ctx := context.Background()
+	if err := loader.Load(ctx); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	writeFile(t, dir, "dhcp.textpb", `
+metadata { name: "eth0" kind: "DHCPClient" }
+dhcp_client { interface_ref: "eth0" enabled: false }
+`)
+	if err := loader.Load(ctx); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindDHCPClient, state.DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if res.GetDhcpClient().GetEnabled() {
+		t.Error("reload did not apply the changed spec")
+	}
+}
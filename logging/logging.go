@@ -0,0 +1,74 @@
+// Package logging provides the structured logger used across microrun
+// components. Each component gets its own logger tagged with its name so
+// log lines can be attributed and filtered.
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// defaultLevel is the log level applied to all components. It can be
+// overridden at startup with the MICRORUN_LOG_LEVEL environment variable.
+var defaultLevel = levelFromEnv()
+
+// componentLevels overrides the default level for individual components.
+// It is populated at startup from the MICRORUN_LOG_LEVELS environment
+// variable, e.g. "store=info,network-interfaces=debug".
+var componentLevels = parseLevels(os.Getenv("MICRORUN_LOG_LEVELS"))
+
+// NewLogger returns a logger for the named component. Lines are written to
+// stderr with the component attached as a "component" attribute, at the
+// component's configured level or the default.
+func NewLogger(component string) *slog.Logger {
+	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: levelFor(component)})
+	return slog.New(handler).With("component", component)
+}
+
+// levelFor returns the level configured for the component, falling back
+// to the default for components without an override.
+func levelFor(component string) slog.Level {
+	if level, ok := componentLevels[component]; ok {
+		return level
+	}
+	return defaultLevel
+}
+
+func levelFromEnv() slog.Level {
+	if level, ok := parseLevel(os.Getenv("MICRORUN_LOG_LEVEL")); ok {
+		return level
+	}
+	return slog.LevelInfo
+}
+
+// parseLevels parses a comma-separated list of component=level pairs.
+// Malformed entries and unknown levels are skipped so one typo does not
+// silence the rest of the list.
+func parseLevels(spec string) map[string]slog.Level {
+	levels := make(map[string]slog.Level)
+	for _, entry := range strings.Split(spec, ",") {
+		component, name, ok := strings.Cut(entry, "=")
+		if !ok || component == "" {
+			continue
+		}
+		if level, ok := parseLevel(name); ok {
+			levels[component] = level
+		}
+	}
+	return levels
+}
+
+func parseLevel(name string) (slog.Level, bool) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "info":
+		return slog.LevelInfo, true
+	case "warn":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	}
+	return 0, false
+}
@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestParseLevels(t *testing.T) {
+	levels := parseLevels("store=info,network-interfaces=debug,broken,=warn,typo=loud")
+	if got := levels["store"]; got != slog.LevelInfo {
+		t.Errorf("store level = %v, want info", got)
+	}
+	if got := levels["network-interfaces"]; got != slog.LevelDebug {
+		t.Errorf("network-interfaces level = %v, want debug", got)
+	}
+	if len(levels) != 2 {
+		t.Errorf("parseLevels kept %d entries, want 2: %v", len(levels), levels)
+	}
+}
+
+func TestComponentLevelOverridesDefault(t *testing.T) {
+	saved := componentLevels
+	componentLevels = map[string]slog.Level{"network-interfaces": slog.LevelDebug}
+	defer func() { componentLevels = saved }()
+
+	ctx := context.Background()
+	if !NewLogger("network-interfaces").Enabled(ctx, slog.LevelDebug) {
+		t.Error("component with a debug override does not log at debug")
+	}
+	if NewLogger("store").Enabled(ctx, slog.LevelDebug) {
+		t.Error("component without an override logs at debug")
+	}
+}
@@ -0,0 +1,212 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/runtime"
+	"github.com/microrun/microrun/state"
+	"github.com/vishvananda/netlink"
+)
+
+// bridgeBackend abstracts the netlink operations the bridge generator
+// needs, so tests can run against a fake instead of the kernel.
+type bridgeBackend interface {
+	LinkByName(name string) (netlink.Link, error)
+	LinkList() ([]netlink.Link, error)
+	LinkAdd(link netlink.Link) error
+	LinkDel(link netlink.Link) error
+	LinkSetMaster(link netlink.Link, master netlink.Link) error
+	LinkSetNoMaster(link netlink.Link) error
+	BridgeSetStp(name string, enabled bool) error
+}
+
+// netlinkBridgeBackend is the production backend delegating to netlink.
+type netlinkBridgeBackend struct{}
+
+func (netlinkBridgeBackend) LinkByName(name string) (netlink.Link, error) {
+	return netlink.LinkByName(name)
+}
+func (netlinkBridgeBackend) LinkList() ([]netlink.Link, error) { return netlink.LinkList() }
+func (netlinkBridgeBackend) LinkAdd(link netlink.Link) error   { return netlink.LinkAdd(link) }
+func (netlinkBridgeBackend) LinkDel(link netlink.Link) error   { return netlink.LinkDel(link) }
+func (netlinkBridgeBackend) LinkSetMaster(link netlink.Link, master netlink.Link) error {
+	return netlink.LinkSetMaster(link, master)
+}
+func (netlinkBridgeBackend) LinkSetNoMaster(link netlink.Link) error {
+	return netlink.LinkSetNoMaster(link)
+}
+
+// BridgeSetStp toggles the spanning tree protocol through sysfs; netlink
+// does not expose the flag.
+func (netlinkBridgeBackend) BridgeSetStp(name string, enabled bool) error {
+	value := "0"
+	if enabled {
+		value = "1"
+	}
+	path := fmt.Sprintf("/sys/class/net/%s/bridge/stp_state", name)
+	return os.WriteFile(path, []byte(value), 0o644)
+}
+
+// BridgeGeneratorType constructs generators that manage Linux bridges
+// from Bridge resources.
+type BridgeGeneratorType struct{}
+
+// Name implements runtime.GeneratorType.
+func (BridgeGeneratorType) Name() string {
+	return "network-bridges"
+}
+
+// ManagedKinds implements runtime.GeneratorType.
+func (BridgeGeneratorType) ManagedKinds() []string {
+	return []string{api.KindBridge}
+}
+
+// WatchedKinds implements runtime.GeneratorType.
+func (BridgeGeneratorType) WatchedKinds() []string {
+	return nil
+}
+
+// New implements runtime.GeneratorType.
+func (BridgeGeneratorType) New(gctx runtime.GeneratorContext) (runtime.Generator, error) {
+	store, err := state.NewTypedStore[*pb.Bridge](gctx.Store)
+	if err != nil {
+		return nil, err
+	}
+	return &BridgeGenerator{
+		store:   store,
+		backend: netlinkBridgeBackend{},
+		logger:  gctx.Logger,
+	}, nil
+}
+
+// BridgeGenerator creates a bridge device per Bridge resource, enslaves
+// the listed member links, and releases or deletes what is no longer
+// desired. Membership changes touch only the members that differ.
+type BridgeGenerator struct {
+	store   *state.TypedStore[*pb.Bridge]
+	backend bridgeBackend
+	logger  *slog.Logger
+}
+
+// Run implements runtime.Generator.
+func (g *BridgeGenerator) Run(ctx context.Context) error {
+	events, err := g.store.Watch(ctx, state.WithInitialState())
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			g.handleEvent(ev)
+		}
+	}
+}
+
+func (g *BridgeGenerator) handleEvent(ev state.TypedEvent[*pb.Bridge]) {
+	name := ev.Resource.Spec.GetBridgeName()
+	switch ev.Type {
+	case state.EventCreated, state.EventUpdated:
+		if err := g.reconcileBridge(ev.Resource.Spec); err != nil {
+			g.logger.Error("reconciling bridge", "bridge", name, "error", err)
+		}
+	case state.EventDeleted:
+		if err := g.deleteBridge(name); err != nil {
+			g.logger.Error("deleting bridge", "bridge", name, "error", err)
+		}
+	}
+}
+
+// reconcileBridge ensures the bridge device exists with the desired
+// attributes and that exactly the listed members are enslaved to it.
+func (g *BridgeGenerator) reconcileBridge(spec *pb.Bridge) error {
+	bridge, err := g.ensureBridge(spec)
+	if err != nil {
+		return err
+	}
+	return g.reconcileMembers(bridge, spec.GetInterfaceRefs())
+}
+
+// ensureBridge returns the bridge link for spec, creating the device if
+// it does not exist yet.
+func (g *BridgeGenerator) ensureBridge(spec *pb.Bridge) (netlink.Link, error) {
+	name := spec.GetBridgeName()
+	if link, err := g.backend.LinkByName(name); err == nil {
+		return link, nil
+	}
+	vlanFiltering := spec.GetVlanFiltering()
+	bridge := &netlink.Bridge{
+		LinkAttrs:     netlink.LinkAttrs{Name: name},
+		VlanFiltering: &vlanFiltering,
+	}
+	if err := g.backend.LinkAdd(bridge); err != nil {
+		return nil, fmt.Errorf("creating bridge %s: %w", name, err)
+	}
+	if err := g.backend.BridgeSetStp(name, spec.GetStp()); err != nil {
+		g.logger.Error("setting STP", "bridge", name, "error", err)
+	}
+	return g.backend.LinkByName(name)
+}
+
+// reconcileMembers enslaves desired member links and releases members
+// that are no longer listed, leaving unchanged members alone.
+func (g *BridgeGenerator) reconcileMembers(bridge netlink.Link, refs []string) error {
+	desired := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		desired[ref] = true
+	}
+
+	links, err := g.backend.LinkList()
+	if err != nil {
+		return fmt.Errorf("listing links: %w", err)
+	}
+	enslaved := make(map[string]bool)
+	for _, link := range links {
+		if link.Attrs().MasterIndex != bridge.Attrs().Index {
+			continue
+		}
+		name := link.Attrs().Name
+		enslaved[name] = true
+		if !desired[name] {
+			if err := g.backend.LinkSetNoMaster(link); err != nil {
+				g.logger.Error("releasing bridge member", "member", name, "error", err)
+			}
+		}
+	}
+	for _, ref := range refs {
+		if enslaved[ref] {
+			continue
+		}
+		link, err := g.backend.LinkByName(ref)
+		if err != nil {
+			g.logger.Error("resolving bridge member", "member", ref, "error", err)
+			continue
+		}
+		if err := g.backend.LinkSetMaster(link, bridge); err != nil {
+			g.logger.Error("enslaving bridge member", "member", ref, "error", err)
+		}
+	}
+	return nil
+}
+
+// deleteBridge removes the bridge device; a bridge already gone is not an
+// error.
+func (g *BridgeGenerator) deleteBridge(name string) error {
+	link, err := g.backend.LinkByName(name)
+	if err != nil {
+		return nil
+	}
+	if err := g.backend.LinkDel(link); err != nil {
+		return fmt.Errorf("deleting bridge %s: %w", name, err)
+	}
+	return nil
+}
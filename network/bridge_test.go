@@ -0,0 +1,171 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/logging"
+	"github.com/microrun/microrun/state"
+	"github.com/vishvananda/netlink"
+)
+
+// fakeBridgeBackend keeps an in-memory link table and records the master
+// assignments the generator makes.
+type fakeBridgeBackend struct {
+	links     map[string]*netlink.Dummy
+	nextIndex int
+	deleted   []string
+	stp       map[string]bool
+}
+
+func newFakeBridgeBackend(members ...string) *fakeBridgeBackend {
+	b := &fakeBridgeBackend{
+		links: make(map[string]*netlink.Dummy),
+		stp:   make(map[string]bool),
+	}
+	for _, name := range members {
+		b.addLink(name)
+	}
+	return b
+}
+
+func (b *fakeBridgeBackend) addLink(name string) *netlink.Dummy {
+	b.nextIndex++
+	link := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: name, Index: b.nextIndex}}
+	b.links[name] = link
+	return link
+}
+
+func (b *fakeBridgeBackend) LinkByName(name string) (netlink.Link, error) {
+	link, ok := b.links[name]
+	if !ok {
+		return nil, netlink.LinkNotFoundError{}
+	}
+	return link, nil
+}
+
+func (b *fakeBridgeBackend) LinkList() ([]netlink.Link, error) {
+	out := make([]netlink.Link, 0, len(b.links))
+	for _, link := range b.links {
+		out = append(out, link)
+	}
+	return out, nil
+}
+
+func (b *fakeBridgeBackend) LinkAdd(link netlink.Link) error {
+	b.addLink(link.Attrs().Name)
+	return nil
+}
+
+func (b *fakeBridgeBackend) LinkDel(link netlink.Link) error {
+	name := link.Attrs().Name
+	delete(b.links, name)
+	b.deleted = append(b.deleted, name)
+	return nil
+}
+
+func (b *fakeBridgeBackend) LinkSetMaster(link netlink.Link, master netlink.Link) error {
+	b.links[link.Attrs().Name].MasterIndex = master.Attrs().Index
+	return nil
+}
+
+func (b *fakeBridgeBackend) LinkSetNoMaster(link netlink.Link) error {
+	b.links[link.Attrs().Name].MasterIndex = 0
+	return nil
+}
+
+func (b *fakeBridgeBackend) BridgeSetStp(name string, enabled bool) error {
+	b.stp[name] = enabled
+	return nil
+}
+
+func newBridgeGenerator(backend *fakeBridgeBackend) *BridgeGenerator {
+	return &BridgeGenerator{
+		backend: backend,
+		logger:  logging.NewLogger("network-bridges"),
+	}
+}
+
+func bridgeEvent(typ state.EventType, name string, spec *pb.Bridge) state.TypedEvent[*pb.Bridge] {
+	return state.TypedEvent[*pb.Bridge]{
+		Type: typ,
+		Resource: &state.TypedResource[*pb.Bridge]{
+			Metadata: &pb.ResourceMetadata{Name: name, Kind: "Bridge"},
+			Spec:     spec,
+		},
+	}
+}
+
+func (b *fakeBridgeBackend) master(t *testing.T, name string) int {
+	t.Helper()
+	link, ok := b.links[name]
+	if !ok {
+		t.Fatalf("link %s does not exist", name)
+	}
+	return link.MasterIndex
+}
+
+func TestBridgeGeneratorCreatesBridgeAndEnslavesMembers(t *testing.T) {
+	backend := newFakeBridgeBackend("eth0", "eth1")
+	gen := newBridgeGenerator(backend)
+
+	gen.handleEvent(bridgeEvent(state.EventCreated, "lan", &pb.Bridge{
+		BridgeName:    "br0",
+		InterfaceRefs: []string{"eth0", "eth1"},
+		Stp:           true,
+	}))
+
+	bridge, ok := backend.links["br0"]
+	if !ok {
+		t.Fatal("bridge br0 not created")
+	}
+	if !backend.stp["br0"] {
+		t.Error("STP not enabled on br0")
+	}
+	for _, member := range []string{"eth0", "eth1"} {
+		if got := backend.master(t, member); got != bridge.Index {
+			t.Errorf("%s master = %d, want %d", member, got, bridge.Index)
+		}
+	}
+}
+
+func TestBridgeGeneratorDiffsMembership(t *testing.T) {
+	backend := newFakeBridgeBackend("eth0", "eth1", "eth2")
+	gen := newBridgeGenerator(backend)
+
+	gen.handleEvent(bridgeEvent(state.EventCreated, "lan", &pb.Bridge{
+		BridgeName:    "br0",
+		InterfaceRefs: []string{"eth0", "eth1"},
+	}))
+	gen.handleEvent(bridgeEvent(state.EventUpdated, "lan", &pb.Bridge{
+		BridgeName:    "br0",
+		InterfaceRefs: []string{"eth1", "eth2"},
+	}))
+
+	bridge := backend.links["br0"]
+	if got := backend.master(t, "eth0"); got != 0 {
+		t.Errorf("eth0 still enslaved (master %d), want released", got)
+	}
+	if got := backend.master(t, "eth1"); got != bridge.Index {
+		t.Errorf("eth1 master = %d, want %d", got, bridge.Index)
+	}
+	if got := backend.master(t, "eth2"); got != bridge.Index {
+		t.Errorf("eth2 master = %d, want %d", got, bridge.Index)
+	}
+}
+
+func TestBridgeGeneratorDeletesBridge(t *testing.T) {
+	backend := newFakeBridgeBackend("eth0")
+	gen := newBridgeGenerator(backend)
+
+	spec := &pb.Bridge{BridgeName: "br0", InterfaceRefs: []string{"eth0"}}
+	gen.handleEvent(bridgeEvent(state.EventCreated, "lan", spec))
+	gen.handleEvent(bridgeEvent(state.EventDeleted, "lan", spec))
+
+	if _, ok := backend.links["br0"]; ok {
+		t.Error("bridge br0 still exists after delete")
+	}
+	if len(backend.deleted) != 1 || backend.deleted[0] != "br0" {
+		t.Errorf("deleted links = %v, want [br0]", backend.deleted)
+	}
+}
@@ -0,0 +1,195 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"sort"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/runtime"
+	"github.com/microrun/microrun/state"
+)
+
+// leaseServer is a running DHCP lease server bound to one interface.
+type leaseServer interface {
+	// Reload applies a changed spec to the running server.
+	Reload(spec *pb.DHCPServer) error
+	// Stop terminates the server and releases its resources.
+	Stop() error
+}
+
+// leaseServerBackend starts lease servers. It sits behind an interface so
+// tests can substitute a fake for the dnsmasq-backed implementation.
+type leaseServerBackend interface {
+	Start(spec *pb.DHCPServer) (leaseServer, error)
+}
+
+// dnsmasqBackend runs one dnsmasq process per DHCPServer resource.
+type dnsmasqBackend struct{}
+
+func (dnsmasqBackend) Start(spec *pb.DHCPServer) (leaseServer, error) {
+	srv := &dnsmasqServer{}
+	if err := srv.start(spec); err != nil {
+		return nil, err
+	}
+	return srv, nil
+}
+
+// dnsmasqServer wraps a dnsmasq process serving leases on one interface.
+type dnsmasqServer struct {
+	cmd *exec.Cmd
+}
+
+func (s *dnsmasqServer) start(spec *pb.DHCPServer) error {
+	args := []string{
+		"--keep-in-foreground",
+		"--port=0",
+		"--interface=" + spec.GetInterfaceRef(),
+		"--bind-interfaces",
+	}
+	rng := fmt.Sprintf("--dhcp-range=%s,%s", spec.GetPoolStart(), spec.GetPoolEnd())
+	if lease := spec.GetLeaseSeconds(); lease != 0 {
+		rng += fmt.Sprintf(",%ds", lease)
+	}
+	args = append(args, rng)
+	names := make([]string, 0, len(spec.GetOptions()))
+	for name := range spec.GetOptions() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, fmt.Sprintf("--dhcp-option=%s,%s", name, spec.GetOptions()[name]))
+	}
+	cmd := exec.Command("dnsmasq", args...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting dnsmasq: %w", err)
+	}
+	s.cmd = cmd
+	return nil
+}
+
+// Reload restarts the process with the new configuration; dnsmasq cannot
+// change its DHCP range at runtime.
+func (s *dnsmasqServer) Reload(spec *pb.DHCPServer) error {
+	if err := s.Stop(); err != nil {
+		return err
+	}
+	return s.start(spec)
+}
+
+func (s *dnsmasqServer) Stop() error {
+	if s.cmd == nil || s.cmd.Process == nil {
+		return nil
+	}
+	if err := s.cmd.Process.Kill(); err != nil {
+		return fmt.Errorf("stopping dnsmasq: %w", err)
+	}
+	s.cmd.Wait()
+	s.cmd = nil
+	return nil
+}
+
+// DHCPServerGeneratorType constructs generators that run DHCP lease
+// servers from DHCPServer resources.
+type DHCPServerGeneratorType struct{}
+
+// Name implements runtime.GeneratorType.
+func (DHCPServerGeneratorType) Name() string {
+	return "dhcp-server"
+}
+
+// ManagedKinds implements runtime.GeneratorType.
+func (DHCPServerGeneratorType) ManagedKinds() []string {
+	return []string{api.KindDHCPServer}
+}
+
+// WatchedKinds implements runtime.GeneratorType.
+func (DHCPServerGeneratorType) WatchedKinds() []string {
+	return nil
+}
+
+// New implements runtime.GeneratorType.
+func (DHCPServerGeneratorType) New(gctx runtime.GeneratorContext) (runtime.Generator, error) {
+	store, err := state.NewTypedStore[*pb.DHCPServer](gctx.Store)
+	if err != nil {
+		return nil, err
+	}
+	return &DHCPServerGenerator{
+		store:   store,
+		backend: dnsmasqBackend{},
+		logger:  gctx.Logger,
+		servers: make(map[string]leaseServer),
+	}, nil
+}
+
+// DHCPServerGenerator starts a lease server per DHCPServer resource,
+// reloads it when the resource changes, and stops it when the resource is
+// deleted.
+type DHCPServerGenerator struct {
+	store   *state.TypedStore[*pb.DHCPServer]
+	backend leaseServerBackend
+	logger  *slog.Logger
+	servers map[string]leaseServer
+}
+
+// Run implements runtime.Generator.
+func (g *DHCPServerGenerator) Run(ctx context.Context) error {
+	events, err := g.store.Watch(ctx, state.WithInitialState())
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			g.handleEvent(ev)
+		}
+	}
+}
+
+func (g *DHCPServerGenerator) handleEvent(ev state.TypedEvent[*pb.DHCPServer]) {
+	name := ev.Resource.Metadata.GetName()
+	switch ev.Type {
+	case state.EventCreated, state.EventUpdated:
+		if srv, ok := g.servers[name]; ok {
+			if err := srv.Reload(ev.Resource.Spec); err != nil {
+				g.logger.Error("reloading lease server", "server", name, "error", err)
+			}
+			return
+		}
+		srv, err := g.backend.Start(ev.Resource.Spec)
+		if err != nil {
+			g.logger.Error("starting lease server", "server", name, "error", err)
+			return
+		}
+		g.servers[name] = srv
+	case state.EventDeleted:
+		srv, ok := g.servers[name]
+		if !ok {
+			return
+		}
+		if err := srv.Stop(); err != nil {
+			g.logger.Error("stopping lease server", "server", name, "error", err)
+		}
+		delete(g.servers, name)
+	}
+}
+
+// Shutdown implements runtime.ShutdownGenerator: it stops every lease
+// server still running once the watch loop has exited.
+func (g *DHCPServerGenerator) Shutdown(ctx context.Context) error {
+	for name, srv := range g.servers {
+		if err := srv.Stop(); err != nil {
+			g.logger.Error("stopping lease server", "server", name, "error", err)
+		}
+		delete(g.servers, name)
+	}
+	return nil
+}
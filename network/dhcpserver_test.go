@@ -0,0 +1,142 @@
+package network
+
+import (
+	"context"
+	"testing"
+
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/logging"
+	"github.com/microrun/microrun/state"
+)
+
+// fakeLeaseServer records reload and stop calls.
+type fakeLeaseServer struct {
+	spec    *pb.DHCPServer
+	reloads int
+	stopped bool
+}
+
+func (f *fakeLeaseServer) Reload(spec *pb.DHCPServer) error {
+	f.spec = spec
+	f.reloads++
+	return nil
+}
+
+func (f *fakeLeaseServer) Stop() error {
+	f.stopped = true
+	return nil
+}
+
+// fakeLeaseBackend hands out fake servers instead of spawning processes.
+type fakeLeaseBackend struct {
+	started []*fakeLeaseServer
+}
+
+func (f *fakeLeaseBackend) Start(spec *pb.DHCPServer) (leaseServer, error) {
+	srv := &fakeLeaseServer{spec: spec}
+	f.started = append(f.started, srv)
+	return srv, nil
+}
+
+func newDHCPServerGenerator(t *testing.T, backend *fakeLeaseBackend) *DHCPServerGenerator {
+	t.Helper()
+	store, err := state.NewTypedStore[*pb.DHCPServer](state.NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+	return &DHCPServerGenerator{
+		store:   store,
+		backend: backend,
+		logger:  logging.NewLogger("dhcp-server"),
+		servers: make(map[string]leaseServer),
+	}
+}
+
+func serverEvent(typ state.EventType, name string, spec *pb.DHCPServer) state.TypedEvent[*pb.DHCPServer] {
+	return state.TypedEvent[*pb.DHCPServer]{
+		Type: typ,
+		Resource: &state.TypedResource[*pb.DHCPServer]{
+			Metadata: &pb.ResourceMetadata{Name: name, Kind: "DHCPServer"},
+			Spec:     spec,
+		},
+	}
+}
+
+func TestDHCPServerGeneratorStartsOnCreate(t *testing.T) {
+	backend := &fakeLeaseBackend{}
+	gen := newDHCPServerGenerator(t, backend)
+
+	spec := &pb.DHCPServer{
+		InterfaceRef: "eth0",
+		PoolStart:    "192.0.2.100",
+		PoolEnd:      "192.0.2.200",
+		LeaseSeconds: 3600,
+	}
+	gen.handleEvent(serverEvent(state.EventCreated, "lan", spec))
+
+	if len(backend.started) != 1 {
+		t.Fatalf("started %d servers, want 1", len(backend.started))
+	}
+	if got := backend.started[0].spec.GetInterfaceRef(); got != "eth0" {
+		t.Errorf("server bound to %q, want eth0", got)
+	}
+}
+
+func TestDHCPServerGeneratorReloadsOnUpdate(t *testing.T) {
+	backend := &fakeLeaseBackend{}
+	gen := newDHCPServerGenerator(t, backend)
+
+	gen.handleEvent(serverEvent(state.EventCreated, "lan", &pb.DHCPServer{
+		InterfaceRef: "eth0",
+		PoolStart:    "192.0.2.100",
+		PoolEnd:      "192.0.2.200",
+	}))
+	gen.handleEvent(serverEvent(state.EventUpdated, "lan", &pb.DHCPServer{
+		InterfaceRef: "eth0",
+		PoolStart:    "192.0.2.50",
+		PoolEnd:      "192.0.2.200",
+	}))
+
+	if len(backend.started) != 1 {
+		t.Fatalf("started %d servers, want 1", len(backend.started))
+	}
+	srv := backend.started[0]
+	if srv.reloads != 1 {
+		t.Errorf("reloads = %d, want 1", srv.reloads)
+	}
+	if got := srv.spec.GetPoolStart(); got != "192.0.2.50" {
+		t.Errorf("pool start after reload = %q, want 192.0.2.50", got)
+	}
+}
+
+func TestDHCPServerGeneratorStopsOnDelete(t *testing.T) {
+	backend := &fakeLeaseBackend{}
+	gen := newDHCPServerGenerator(t, backend)
+
+	gen.handleEvent(serverEvent(state.EventCreated, "lan", &pb.DHCPServer{InterfaceRef: "eth0"}))
+	gen.handleEvent(serverEvent(state.EventDeleted, "lan", &pb.DHCPServer{InterfaceRef: "eth0"}))
+
+	if !backend.started[0].stopped {
+		t.Error("server not stopped on delete")
+	}
+	if len(gen.servers) != 0 {
+		t.Errorf("%d servers still tracked after delete", len(gen.servers))
+	}
+}
+
+func TestDHCPServerGeneratorShutdownStopsAll(t *testing.T) {
+	backend := &fakeLeaseBackend{}
+	gen := newDHCPServerGenerator(t, backend)
+
+	gen.handleEvent(serverEvent(state.EventCreated, "lan", &pb.DHCPServer{InterfaceRef: "eth0"}))
+	gen.handleEvent(serverEvent(state.EventCreated, "guest", &pb.DHCPServer{InterfaceRef: "eth1"}))
+
+	if err := gen.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	for _, srv := range backend.started {
+		if !srv.stopped {
+			t.Errorf("server on %s not stopped", srv.spec.GetInterfaceRef())
+		}
+	}
+}
@@ -0,0 +1,382 @@
+// Package network contains generators that observe and manage the host's
+// network configuration through netlink.
+package network
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/clock"
+	"github.com/microrun/microrun/runtime"
+	"github.com/microrun/microrun/state"
+	"github.com/vishvananda/netlink"
+)
+
+// reconcileInterval is how often the interface generator re-reads the
+// system's links.
+const reconcileInterval = 10 * time.Second
+
+// linkBackend is the slice of netlink the interface generator uses to
+// observe and change links. Tests substitute a fake.
+type linkBackend interface {
+	LinkList() ([]netlink.Link, error)
+	AddrList(link netlink.Link, family int) ([]netlink.Addr, error)
+	LinkSetUp(link netlink.Link) error
+	LinkSetDown(link netlink.Link) error
+	LinkSetMTU(link netlink.Link, mtu int) error
+	AddrAdd(link netlink.Link, addr *netlink.Addr) error
+	AddrDel(link netlink.Link, addr *netlink.Addr) error
+	SetPromiscOn(link netlink.Link) error
+	SetPromiscOff(link netlink.Link) error
+	LinkSetMulticastOn(link netlink.Link) error
+	LinkSetMulticastOff(link netlink.Link) error
+	LinkSetAllmulticastOn(link netlink.Link) error
+	LinkSetAllmulticastOff(link netlink.Link) error
+	LinkSetAlias(link netlink.Link, alias string) error
+}
+
+// netlinkLinkBackend delegates to the package-level netlink functions.
+type netlinkLinkBackend struct{}
+
+func (netlinkLinkBackend) LinkList() ([]netlink.Link, error) { return netlink.LinkList() }
+func (netlinkLinkBackend) AddrList(link netlink.Link, family int) ([]netlink.Addr, error) {
+	return netlink.AddrList(link, family)
+}
+func (netlinkLinkBackend) LinkSetUp(link netlink.Link) error   { return netlink.LinkSetUp(link) }
+func (netlinkLinkBackend) LinkSetDown(link netlink.Link) error { return netlink.LinkSetDown(link) }
+func (netlinkLinkBackend) LinkSetMTU(link netlink.Link, mtu int) error {
+	return netlink.LinkSetMTU(link, mtu)
+}
+func (netlinkLinkBackend) AddrAdd(link netlink.Link, addr *netlink.Addr) error {
+	return netlink.AddrAdd(link, addr)
+}
+func (netlinkLinkBackend) AddrDel(link netlink.Link, addr *netlink.Addr) error {
+	return netlink.AddrDel(link, addr)
+}
+func (netlinkLinkBackend) SetPromiscOn(link netlink.Link) error  { return netlink.SetPromiscOn(link) }
+func (netlinkLinkBackend) SetPromiscOff(link netlink.Link) error { return netlink.SetPromiscOff(link) }
+func (netlinkLinkBackend) LinkSetMulticastOn(link netlink.Link) error {
+	return netlink.LinkSetMulticastOn(link)
+}
+func (netlinkLinkBackend) LinkSetMulticastOff(link netlink.Link) error {
+	return netlink.LinkSetMulticastOff(link)
+}
+func (netlinkLinkBackend) LinkSetAllmulticastOn(link netlink.Link) error {
+	return netlink.LinkSetAllmulticastOn(link)
+}
+func (netlinkLinkBackend) LinkSetAllmulticastOff(link netlink.Link) error {
+	return netlink.LinkSetAllmulticastOff(link)
+}
+func (netlinkLinkBackend) LinkSetAlias(link netlink.Link, alias string) error {
+	return netlink.LinkSetAlias(link, alias)
+}
+
+// InterfaceGeneratorType constructs generators that mirror the host's
+// network links into NetworkInterface resources.
+type InterfaceGeneratorType struct{}
+
+// Name implements runtime.GeneratorType.
+func (InterfaceGeneratorType) Name() string {
+	return "network-interfaces"
+}
+
+// ManagedKinds implements runtime.GeneratorType.
+func (InterfaceGeneratorType) ManagedKinds() []string {
+	return []string{api.KindNetworkInterface}
+}
+
+// WatchedKinds implements runtime.GeneratorType.
+func (InterfaceGeneratorType) WatchedKinds() []string {
+	return nil
+}
+
+// New implements runtime.GeneratorType.
+func (InterfaceGeneratorType) New(gctx runtime.GeneratorContext) (runtime.Generator, error) {
+	return &InterfaceGenerator{
+		store:  gctx.Store,
+		links:  netlinkLinkBackend{},
+		clock:  clock.Real{},
+		logger: gctx.Logger,
+	}, nil
+}
+
+// InterfaceGenerator periodically lists the host's links and reconciles
+// NetworkInterface resources to match what it observes. A resource's
+// admin_state field is the one piece of spec the generator treats as
+// desired state: it drives the link up or down to match and never
+// overwrites it with what it observes.
+type InterfaceGenerator struct {
+	store  state.Store
+	links  linkBackend
+	clock  clock.Clock
+	logger *slog.Logger
+}
+
+// Run implements runtime.Generator.
+func (g *InterfaceGenerator) Run(ctx context.Context) error {
+	ticker := g.clock.Ticker(reconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := g.reconcileInterfaces(ctx); err != nil {
+			g.logger.Error("reconciling interfaces", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
+
+// reconcileInterfaces reads the current links off the system, applies any
+// desired admin state, and creates, updates, or deletes NetworkInterface
+// resources to match.
+func (g *InterfaceGenerator) reconcileInterfaces(ctx context.Context) error {
+	links, err := g.links.LinkList()
+	if err != nil {
+		return fmt.Errorf("listing links: %w", err)
+	}
+
+	existing, err := g.store.List(ctx, api.KindNetworkInterface, state.DefaultNamespace)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]*pb.Resource, len(existing))
+	for _, res := range existing {
+		byName[res.GetMetadata().GetName()] = res
+	}
+
+	observed := make(map[string]bool, len(links))
+	for _, link := range links {
+		name := link.Attrs().Name
+		addrs, err := g.links.AddrList(link, netlink.FAMILY_ALL)
+		if err != nil {
+			g.logger.Error("observing link", "link", name, "error", err)
+			continue
+		}
+		spec, status := observeLink(link, addrs)
+		observed[name] = true
+
+		// admin_state, mtu, a non-empty ip_addresses list, and the
+		// link flags are desired state: carry them over from the
+		// stored resource and reconcile the link towards them.
+		if cur := byName[name]; cur != nil {
+			spec.AdminState = cur.GetNetworkInterface().GetAdminState()
+			spec.Mtu = cur.GetNetworkInterface().GetMtu()
+			if want := cur.GetNetworkInterface().GetIpAddresses(); len(want) > 0 {
+				spec.IpAddresses = want
+			}
+			spec.Promisc = cur.GetNetworkInterface().Promisc
+			spec.Multicast = cur.GetNetworkInterface().Multicast
+			spec.Allmulticast = cur.GetNetworkInterface().Allmulticast
+			spec.Alias = cur.GetNetworkInterface().Alias
+		}
+		if err := g.applyAdminState(link, spec.GetAdminState()); err != nil {
+			g.logger.Error("applying admin state", "interface", name, "error", err)
+		}
+		if err := g.applyMTU(link, spec.GetMtu()); err != nil {
+			g.logger.Error("applying MTU", "interface", name, "error", err)
+		}
+		if err := g.applyAddresses(link, spec.GetIpAddresses(), addrs); err != nil {
+			g.logger.Error("applying addresses", "interface", name, "error", err)
+		}
+		if err := g.applyFlags(link, spec); err != nil {
+			g.logger.Error("applying link flags", "interface", name, "error", err)
+		}
+		if err := g.applyAlias(link, spec); err != nil {
+			g.logger.Error("applying alias", "interface", name, "error", err)
+		}
+
+		if err := g.writeInterface(ctx, byName[name], spec, status); err != nil {
+			g.logger.Error("storing interface", "interface", name, "error", err)
+		}
+	}
+	for name := range byName {
+		if !observed[name] {
+			if err := g.store.Delete(ctx, api.KindNetworkInterface, state.DefaultNamespace, name); err != nil {
+				g.logger.Error("deleting stale interface", "interface", name, "error", err)
+			}
+		}
+	}
+	return nil
+}
+
+// applyAdminState drives the link up or down when the desired admin state
+// differs from the link's current one. An empty desired state leaves the
+// link alone.
+func (g *InterfaceGenerator) applyAdminState(link netlink.Link, desired string) error {
+	if desired == "" {
+		return nil
+	}
+	up := link.Attrs().Flags&net.FlagUp != 0
+	switch desired {
+	case "up":
+		if !up {
+			return g.links.LinkSetUp(link)
+		}
+	case "down":
+		if up {
+			return g.links.LinkSetDown(link)
+		}
+	default:
+		return fmt.Errorf("unknown admin state %q", desired)
+	}
+	return nil
+}
+
+// applyMTU sets the link's MTU when the desired MTU differs from the
+// current one. A zero desired MTU leaves the link alone.
+func (g *InterfaceGenerator) applyMTU(link netlink.Link, desired uint32) error {
+	if desired == 0 {
+		return nil
+	}
+	if int(desired) != link.Attrs().MTU {
+		return g.links.LinkSetMTU(link, int(desired))
+	}
+	return nil
+}
+
+// applyFlags toggles the link's promisc, multicast, and allmulticast
+// flags towards the desired ones, touching only flags that are both set
+// in the spec and differ from what the link reports.
+func (g *InterfaceGenerator) applyFlags(link netlink.Link, spec *pb.NetworkInterface) error {
+	attrs := link.Attrs()
+	if spec.Promisc != nil && *spec.Promisc != (attrs.Promisc != 0) {
+		if *spec.Promisc {
+			if err := g.links.SetPromiscOn(link); err != nil {
+				return fmt.Errorf("enabling promisc: %w", err)
+			}
+		} else if err := g.links.SetPromiscOff(link); err != nil {
+			return fmt.Errorf("disabling promisc: %w", err)
+		}
+	}
+	if spec.Multicast != nil && *spec.Multicast != (attrs.Multi != 0) {
+		if *spec.Multicast {
+			if err := g.links.LinkSetMulticastOn(link); err != nil {
+				return fmt.Errorf("enabling multicast: %w", err)
+			}
+		} else if err := g.links.LinkSetMulticastOff(link); err != nil {
+			return fmt.Errorf("disabling multicast: %w", err)
+		}
+	}
+	if spec.Allmulticast != nil && *spec.Allmulticast != (attrs.Allmulti != 0) {
+		if *spec.Allmulticast {
+			if err := g.links.LinkSetAllmulticastOn(link); err != nil {
+				return fmt.Errorf("enabling allmulticast: %w", err)
+			}
+		} else if err := g.links.LinkSetAllmulticastOff(link); err != nil {
+			return fmt.Errorf("disabling allmulticast: %w", err)
+		}
+	}
+	return nil
+}
+
+// applyAlias sets the link's alias when the desired alias differs from
+// the current one. An unset alias leaves the link alone; an explicit
+// empty string clears whatever alias the link carries.
+func (g *InterfaceGenerator) applyAlias(link netlink.Link, spec *pb.NetworkInterface) error {
+	if spec.Alias == nil {
+		return nil
+	}
+	if *spec.Alias != link.Attrs().Alias {
+		return g.links.LinkSetAlias(link, *spec.Alias)
+	}
+	return nil
+}
+
+// applyAddresses diffs the desired CIDR addresses against the ones
+// currently on the link and adds or removes addresses to converge. An
+// empty desired list leaves the link's addresses unmanaged. Malformed
+// CIDRs are logged and skipped rather than failing the whole reconcile.
+func (g *InterfaceGenerator) applyAddresses(link netlink.Link, desired []string, current []netlink.Addr) error {
+	if len(desired) == 0 {
+		return nil
+	}
+	want := make(map[string]*netlink.Addr, len(desired))
+	for _, cidr := range desired {
+		addr, err := netlink.ParseAddr(cidr)
+		if err != nil {
+			g.logger.Error("parsing desired address", "interface", link.Attrs().Name,
+				"address", cidr, "error", err)
+			continue
+		}
+		want[addr.IPNet.String()] = addr
+	}
+	have := make(map[string]netlink.Addr, len(current))
+	for _, addr := range current {
+		have[addr.IPNet.String()] = addr
+	}
+
+	for key, addr := range want {
+		if _, ok := have[key]; ok {
+			continue
+		}
+		if err := g.links.AddrAdd(link, addr); err != nil {
+			return fmt.Errorf("adding address %s: %w", key, err)
+		}
+	}
+	for key, addr := range have {
+		if _, ok := want[key]; ok {
+			continue
+		}
+		addr := addr
+		if err := g.links.AddrDel(link, &addr); err != nil {
+			return fmt.Errorf("removing address %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// writeInterface stores the observed spec and status for a link, creating
+// the resource if cur is nil and updating it otherwise.
+func (g *InterfaceGenerator) writeInterface(ctx context.Context, cur *pb.Resource, spec *pb.NetworkInterface, status *pb.NetworkInterfaceStatus) error {
+	res := &pb.Resource{
+		Metadata: &pb.ResourceMetadata{Name: spec.GetInterfaceName(), Kind: api.KindNetworkInterface},
+		Spec:     &pb.Resource_NetworkInterface{NetworkInterface: spec},
+		Status:   &pb.Resource_NetworkInterfaceStatus{NetworkInterfaceStatus: status},
+	}
+	if cur == nil {
+		return g.store.Create(ctx, res)
+	}
+	res.Metadata = cur.GetMetadata()
+	return g.store.Update(ctx, res)
+}
+
+// observeLink converts a netlink link and its addresses into a
+// NetworkInterface spec and the status observed for it.
+func observeLink(link netlink.Link, addrs []netlink.Addr) (*pb.NetworkInterface, *pb.NetworkInterfaceStatus) {
+	attrs := link.Attrs()
+	iface := &pb.NetworkInterface{
+		InterfaceName: attrs.Name,
+		MacAddress:    attrs.HardwareAddr.String(),
+	}
+	for _, addr := range addrs {
+		iface.IpAddresses = append(iface.IpAddresses, addr.IPNet.String())
+	}
+	status := &pb.NetworkInterfaceStatus{
+		OperState:    attrs.OperState.String(),
+		Mtu:          uint32(attrs.MTU),
+		Promisc:      attrs.Promisc != 0,
+		Multicast:    attrs.Multi != 0,
+		Allmulticast: attrs.Allmulti != 0,
+		Alias:        attrs.Alias,
+		// The kernel has no separate carrier attribute here: only
+		// OperUp means the lower layer detects a carrier. An admin-down
+		// or lower-layer-down link reports false.
+		Carrier: attrs.OperState == netlink.OperUp,
+	}
+	if stats := attrs.Statistics; stats != nil {
+		status.RxBytes = stats.RxBytes
+		status.TxBytes = stats.TxBytes
+		status.RxPackets = stats.RxPackets
+		status.TxPackets = stats.TxPackets
+		status.Errors = stats.RxErrors + stats.TxErrors
+	}
+	return iface, status
+}
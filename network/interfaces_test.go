@@ -0,0 +1,615 @@
+package network
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/clock"
+	"github.com/microrun/microrun/logging"
+	"github.com/microrun/microrun/state"
+	"github.com/vishvananda/netlink"
+)
+
+// fakeLinkBackend records link mutations instead of touching the kernel.
+type fakeLinkBackend struct {
+	// links and addrs seed what the fake reports from LinkList and
+	// AddrList, keyed by link name.
+	links []netlink.Link
+	addrs map[string][]netlink.Addr
+
+	up          []string
+	down        []string
+	mtus        map[string]int
+	addrAdded   []string
+	addrDeleted []string
+
+	promiscOn       []string
+	promiscOff      []string
+	multicastOn     []string
+	multicastOff    []string
+	allmulticastOn  []string
+	allmulticastOff []string
+	aliases         map[string]string
+}
+
+func (f *fakeLinkBackend) LinkList() ([]netlink.Link, error) {
+	return f.links, nil
+}
+
+func (f *fakeLinkBackend) AddrList(link netlink.Link, family int) ([]netlink.Addr, error) {
+	return f.addrs[link.Attrs().Name], nil
+}
+
+func (f *fakeLinkBackend) LinkSetUp(link netlink.Link) error {
+	f.up = append(f.up, link.Attrs().Name)
+	return nil
+}
+
+func (f *fakeLinkBackend) LinkSetDown(link netlink.Link) error {
+	f.down = append(f.down, link.Attrs().Name)
+	return nil
+}
+
+func (f *fakeLinkBackend) LinkSetMTU(link netlink.Link, mtu int) error {
+	if f.mtus == nil {
+		f.mtus = make(map[string]int)
+	}
+	f.mtus[link.Attrs().Name] = mtu
+	return nil
+}
+
+func (f *fakeLinkBackend) AddrAdd(link netlink.Link, addr *netlink.Addr) error {
+	f.addrAdded = append(f.addrAdded, addr.IPNet.String())
+	return nil
+}
+
+func (f *fakeLinkBackend) AddrDel(link netlink.Link, addr *netlink.Addr) error {
+	f.addrDeleted = append(f.addrDeleted, addr.IPNet.String())
+	return nil
+}
+
+func (f *fakeLinkBackend) LinkSetAlias(link netlink.Link, alias string) error {
+	if f.aliases == nil {
+		f.aliases = make(map[string]string)
+	}
+	f.aliases[link.Attrs().Name] = alias
+	return nil
+}
+
+func newInterfaceGenerator(backend *fakeLinkBackend) *InterfaceGenerator {
+	return &InterfaceGenerator{
+		store:  state.NewMemoryStore(),
+		links:  backend,
+		logger: logging.NewLogger("network-interfaces"),
+	}
+}
+
+func testLink(name string, up bool) netlink.Link {
+	attrs := netlink.LinkAttrs{Name: name}
+	if up {
+		attrs.Flags = net.FlagUp
+	}
+	return &netlink.Dummy{LinkAttrs: attrs}
+}
+
+func TestApplyAdminStateBringsLinkUp(t *testing.T) {
+	backend := &fakeLinkBackend{}
+	gen := newInterfaceGenerator(backend)
+
+	if err := gen.applyAdminState(testLink("eth0", false), "up"); err != nil {
+		t.Fatalf("applyAdminState: %v", err)
+	}
+	if len(backend.up) != 1 || backend.up[0] != "eth0" {
+		t.Errorf("up calls = %v, want [eth0]", backend.up)
+	}
+	if len(backend.down) != 0 {
+		t.Errorf("down calls = %v, want none", backend.down)
+	}
+}
+
+func TestApplyAdminStateTakesLinkDown(t *testing.T) {
+	backend := &fakeLinkBackend{}
+	gen := newInterfaceGenerator(backend)
+
+	if err := gen.applyAdminState(testLink("eth0", true), "down"); err != nil {
+		t.Fatalf("applyAdminState: %v", err)
+	}
+	if len(backend.down) != 1 || backend.down[0] != "eth0" {
+		t.Errorf("down calls = %v, want [eth0]", backend.down)
+	}
+}
+
+func TestApplyAdminStateNoOpWhenConverged(t *testing.T) {
+	backend := &fakeLinkBackend{}
+	gen := newInterfaceGenerator(backend)
+
+	if err := gen.applyAdminState(testLink("eth0", true), "up"); err != nil {
+		t.Fatalf("applyAdminState (up): %v", err)
+	}
+	if err := gen.applyAdminState(testLink("eth1", false), "down"); err != nil {
+		t.Fatalf("applyAdminState (down): %v", err)
+	}
+	// An empty desired state leaves the link unmanaged.
+	if err := gen.applyAdminState(testLink("eth2", false), ""); err != nil {
+		t.Fatalf("applyAdminState (unmanaged): %v", err)
+	}
+	if len(backend.up) != 0 || len(backend.down) != 0 {
+		t.Errorf("calls = up %v down %v, want none", backend.up, backend.down)
+	}
+}
+
+func TestApplyAdminStateRejectsUnknownState(t *testing.T) {
+	gen := newInterfaceGenerator(&fakeLinkBackend{})
+	if err := gen.applyAdminState(testLink("eth0", false), "sideways"); err == nil {
+		t.Fatal("applyAdminState accepted an unknown admin state")
+	}
+}
+
+func mustAddr(t *testing.T, cidr string) netlink.Addr {
+	t.Helper()
+	addr, err := netlink.ParseAddr(cidr)
+	if err != nil {
+		t.Fatalf("ParseAddr(%q): %v", cidr, err)
+	}
+	return *addr
+}
+
+func TestApplyAddressesConvergesDiff(t *testing.T) {
+	backend := &fakeLinkBackend{}
+	gen := newInterfaceGenerator(backend)
+	link := testLink("eth0", true)
+
+	desired := []string{"192.0.2.1/24", "2001:db8::1/64"}
+	current := []netlink.Addr{
+		mustAddr(t, "192.0.2.1/24"),
+		mustAddr(t, "198.51.100.5/24"),
+	}
+	if err := gen.applyAddresses(link, desired, current); err != nil {
+		t.Fatalf("applyAddresses: %v", err)
+	}
+	if len(backend.addrAdded) != 1 || backend.addrAdded[0] != "2001:db8::1/64" {
+		t.Errorf("added = %v, want [2001:db8::1/64]", backend.addrAdded)
+	}
+	if len(backend.addrDeleted) != 1 || backend.addrDeleted[0] != "198.51.100.5/24" {
+		t.Errorf("deleted = %v, want [198.51.100.5/24]", backend.addrDeleted)
+	}
+}
+
+func TestApplyAddressesUnmanagedAndMalformed(t *testing.T) {
+	backend := &fakeLinkBackend{}
+	gen := newInterfaceGenerator(backend)
+	link := testLink("eth0", true)
+	current := []netlink.Addr{mustAddr(t, "192.0.2.1/24")}
+
+	// An empty desired list leaves the link's addresses unmanaged.
+	if err := gen.applyAddresses(link, nil, current); err != nil {
+		t.Fatalf("applyAddresses (unmanaged): %v", err)
+	}
+	// A malformed CIDR is logged and skipped, and must not cause the
+	// remaining addresses to be treated as undesired.
+	desired := []string{"not-a-cidr", "192.0.2.1/24"}
+	if err := gen.applyAddresses(link, desired, current); err != nil {
+		t.Fatalf("applyAddresses (malformed): %v", err)
+	}
+	if len(backend.addrAdded) != 0 || len(backend.addrDeleted) != 0 {
+		t.Errorf("calls = added %v deleted %v, want none", backend.addrAdded, backend.addrDeleted)
+	}
+}
+
+func TestApplyMTUChangesDivergedLink(t *testing.T) {
+	backend := &fakeLinkBackend{}
+	gen := newInterfaceGenerator(backend)
+	link := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "eth0", MTU: 1500}}
+
+	if err := gen.applyMTU(link, 9000); err != nil {
+		t.Fatalf("applyMTU: %v", err)
+	}
+	if backend.mtus["eth0"] != 9000 {
+		t.Errorf("mtu calls = %v, want eth0:9000", backend.mtus)
+	}
+}
+
+func TestApplyMTUNoOpWhenConverged(t *testing.T) {
+	backend := &fakeLinkBackend{}
+	gen := newInterfaceGenerator(backend)
+	link := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "eth0", MTU: 9000}}
+
+	if err := gen.applyMTU(link, 9000); err != nil {
+		t.Fatalf("applyMTU (converged): %v", err)
+	}
+	// A zero desired MTU leaves the link unmanaged.
+	if err := gen.applyMTU(link, 0); err != nil {
+		t.Fatalf("applyMTU (unmanaged): %v", err)
+	}
+	if len(backend.mtus) != 0 {
+		t.Errorf("mtu calls = %v, want none", backend.mtus)
+	}
+}
+
+func TestObserveLinkStatistics(t *testing.T) {
+	link := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{
+		Name: "eth0",
+		Statistics: &netlink.LinkStatistics{
+			RxBytes:   1000,
+			TxBytes:   2000,
+			RxPackets: 10,
+			TxPackets: 20,
+			RxErrors:  1,
+			TxErrors:  2,
+		},
+	}}
+
+	_, status := observeLink(link, nil)
+	if status.GetRxBytes() != 1000 || status.GetTxBytes() != 2000 {
+		t.Errorf("bytes = %d/%d, want 1000/2000", status.GetRxBytes(), status.GetTxBytes())
+	}
+	if status.GetRxPackets() != 10 || status.GetTxPackets() != 20 {
+		t.Errorf("packets = %d/%d, want 10/20", status.GetRxPackets(), status.GetTxPackets())
+	}
+	if status.GetErrors() != 3 {
+		t.Errorf("errors = %d, want 3", status.GetErrors())
+	}
+}
+
+func TestObserveLinkWithoutStatistics(t *testing.T) {
+	_, status := observeLink(testLink("eth0", true), nil)
+	if status.GetRxBytes() != 0 || status.GetErrors() != 0 {
+		t.Errorf("statistics-less link reported counters: %v", status)
+	}
+}
+
+func (f *fakeLinkBackend) SetPromiscOn(link netlink.Link) error {
+	f.promiscOn = append(f.promiscOn, link.Attrs().Name)
+	return nil
+}
+
+func (f *fakeLinkBackend) SetPromiscOff(link netlink.Link) error {
+	f.promiscOff = append(f.promiscOff, link.Attrs().Name)
+	return nil
+}
+
+func (f *fakeLinkBackend) LinkSetMulticastOn(link netlink.Link) error {
+	f.multicastOn = append(f.multicastOn, link.Attrs().Name)
+	return nil
+}
+
+func (f *fakeLinkBackend) LinkSetMulticastOff(link netlink.Link) error {
+	f.multicastOff = append(f.multicastOff, link.Attrs().Name)
+	return nil
+}
+
+func (f *fakeLinkBackend) LinkSetAllmulticastOn(link netlink.Link) error {
+	f.allmulticastOn = append(f.allmulticastOn, link.Attrs().Name)
+	return nil
+}
+
+func (f *fakeLinkBackend) LinkSetAllmulticastOff(link netlink.Link) error {
+	f.allmulticastOff = append(f.allmulticastOff, link.Attrs().Name)
+	return nil
+}
+
+func boolPtr(v bool) *bool { return &v }
+
+func TestApplyFlagsTogglesOnDivergence(t *testing.T) {
+	backend := &fakeLinkBackend{}
+	gen := newInterfaceGenerator(backend)
+
+	// Link has promisc off and multicast on; spec wants the opposite.
+	link := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "eth0", Multi: 1}}
+	spec := &pb.NetworkInterface{
+		InterfaceName: "eth0",
+		Promisc:       boolPtr(true),
+		Multicast:     boolPtr(false),
+	}
+	if err := gen.applyFlags(link, spec); err != nil {
+		t.Fatalf("applyFlags: %v", err)
+	}
+	if len(backend.promiscOn) != 1 || backend.promiscOn[0] != "eth0" {
+		t.Errorf("promisc on calls = %v, want [eth0]", backend.promiscOn)
+	}
+	if len(backend.multicastOff) != 1 || backend.multicastOff[0] != "eth0" {
+		t.Errorf("multicast off calls = %v, want [eth0]", backend.multicastOff)
+	}
+	if len(backend.allmulticastOn)+len(backend.allmulticastOff) != 0 {
+		t.Error("allmulticast toggled without a desired value")
+	}
+}
+
+func TestApplyFlagsSkipsConvergedFlags(t *testing.T) {
+	backend := &fakeLinkBackend{}
+	gen := newInterfaceGenerator(backend)
+
+	// Link already matches the desired flags: nothing may be called.
+	link := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "eth0", Promisc: 1, Allmulti: 1}}
+	spec := &pb.NetworkInterface{
+		InterfaceName: "eth0",
+		Promisc:       boolPtr(true),
+		Allmulticast:  boolPtr(true),
+	}
+	if err := gen.applyFlags(link, spec); err != nil {
+		t.Fatalf("applyFlags: %v", err)
+	}
+	calls := len(backend.promiscOn) + len(backend.promiscOff) +
+		len(backend.multicastOn) + len(backend.multicastOff) +
+		len(backend.allmulticastOn) + len(backend.allmulticastOff)
+	if calls != 0 {
+		t.Errorf("%d flag calls on converged link, want 0", calls)
+	}
+}
+
+func TestObserveLinkRecordsFlags(t *testing.T) {
+	link := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "eth0", Promisc: 1, Multi: 1}}
+	_, status := observeLink(link, nil)
+	if !status.GetPromisc() {
+		t.Error("promisc not recorded in status")
+	}
+	if !status.GetMulticast() {
+		t.Error("multicast not recorded in status")
+	}
+	if status.GetAllmulticast() {
+		t.Error("allmulticast recorded as on for a link with it off")
+	}
+}
+
+func fakeLink(name, mac string, mtu int) *netlink.Dummy {
+	hw, _ := net.ParseMAC(mac)
+	return &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{
+		Name:         name,
+		HardwareAddr: hw,
+		MTU:          mtu,
+		OperState:    netlink.OperUp,
+	}}
+}
+
+func TestInterfaceGeneratorReconcilesFromBackend(t *testing.T) {
+	addr, err := netlink.ParseAddr("10.0.0.2/24")
+	if err != nil {
+		t.Fatalf("ParseAddr: %v", err)
+	}
+	backend := &fakeLinkBackend{
+		links: []netlink.Link{
+			fakeLink("eth0", "aa:bb:cc:dd:ee:01", 1500),
+			fakeLink("eth1", "aa:bb:cc:dd:ee:02", 9000),
+		},
+		addrs: map[string][]netlink.Addr{"eth0": {*addr}},
+	}
+	g := newInterfaceGenerator(backend)
+	ctx := context.Background()
+
+	if err := g.reconcileInterfaces(ctx); err != nil {
+		t.Fatalf("reconcileInterfaces: %v", err)
+	}
+
+	res, err := g.store.Get(ctx, api.KindNetworkInterface, state.DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get eth0: %v", err)
+	}
+	spec := res.GetNetworkInterface()
+	if got := spec.GetMacAddress(); got != "aa:bb:cc:dd:ee:01" {
+		t.Errorf("eth0 mac = %q, want aa:bb:cc:dd:ee:01", got)
+	}
+	if got := spec.GetIpAddresses(); len(got) != 1 || got[0] != "10.0.0.2/24" {
+		t.Errorf("eth0 addresses = %v, want [10.0.0.2/24]", got)
+	}
+	status := res.GetNetworkInterfaceStatus()
+	if got := status.GetOperState(); got != "up" {
+		t.Errorf("eth0 oper_state = %q, want up", got)
+	}
+	if got := status.GetMtu(); got != 1500 {
+		t.Errorf("eth0 status mtu = %d, want 1500", got)
+	}
+
+	if _, err := g.store.Get(ctx, api.KindNetworkInterface, state.DefaultNamespace, "eth1"); err != nil {
+		t.Errorf("Get eth1: %v", err)
+	}
+}
+
+func TestInterfaceGeneratorDeletesVanishedLink(t *testing.T) {
+	backend := &fakeLinkBackend{
+		links: []netlink.Link{
+			fakeLink("eth0", "aa:bb:cc:dd:ee:01", 1500),
+			fakeLink("eth1", "aa:bb:cc:dd:ee:02", 1500),
+		},
+	}
+	g := newInterfaceGenerator(backend)
+	ctx := context.Background()
+
+	if err := g.reconcileInterfaces(ctx); err != nil {
+		t.Fatalf("reconcileInterfaces: %v", err)
+	}
+	backend.links = backend.links[:1]
+	if err := g.reconcileInterfaces(ctx); err != nil {
+		t.Fatalf("reconcileInterfaces after removal: %v", err)
+	}
+
+	if _, err := g.store.Get(ctx, api.KindNetworkInterface, state.DefaultNamespace, "eth0"); err != nil {
+		t.Errorf("Get of surviving link: %v", err)
+	}
+	if _, err := g.store.Get(ctx, api.KindNetworkInterface, state.DefaultNamespace, "eth1"); !state.IsNotFound(err) {
+		t.Errorf("Get of vanished link = %v, want NotFoundError", err)
+	}
+}
+
+func TestInterfaceGeneratorDrivesDesiredAdminState(t *testing.T) {
+	link := fakeLink("eth0", "aa:bb:cc:dd:ee:01", 1500)
+	backend := &fakeLinkBackend{links: []netlink.Link{link}}
+	g := newInterfaceGenerator(backend)
+	ctx := context.Background()
+
+	if err := g.reconcileInterfaces(ctx); err != nil {
+		t.Fatalf("reconcileInterfaces: %v", err)
+	}
+	res, err := g.store.Get(ctx, api.KindNetworkInterface, state.DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.GetNetworkInterface().AdminState = "up"
+	if err := g.store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	if err := g.reconcileInterfaces(ctx); err != nil {
+		t.Fatalf("reconcileInterfaces with desired state: %v", err)
+	}
+	if len(backend.up) != 1 || backend.up[0] != "eth0" {
+		t.Errorf("LinkSetUp calls = %v, want [eth0]", backend.up)
+	}
+	// The stored spec keeps the desired admin state across reconciles.
+	res, err = g.store.Get(ctx, api.KindNetworkInterface, state.DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get after reconcile: %v", err)
+	}
+	if got := res.GetNetworkInterface().GetAdminState(); got != "up" {
+		t.Errorf("stored admin_state = %q, want up", got)
+	}
+}
+
+func strPtr(v string) *string { return &v }
+
+func TestApplyAliasSetsOnDivergence(t *testing.T) {
+	backend := &fakeLinkBackend{}
+	gen := newInterfaceGenerator(backend)
+
+	link := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "eth0", Alias: "old label"}}
+	spec := &pb.NetworkInterface{InterfaceName: "eth0", Alias: strPtr("uplink to core")}
+	if err := gen.applyAlias(link, spec); err != nil {
+		t.Fatalf("applyAlias: %v", err)
+	}
+	if got := backend.aliases["eth0"]; got != "uplink to core" {
+		t.Errorf("alias call = %q, want %q", got, "uplink to core")
+	}
+
+	// A converged alias, or one left unmanaged, must not be touched.
+	backend.aliases = nil
+	link.LinkAttrs.Alias = "uplink to core"
+	if err := gen.applyAlias(link, spec); err != nil {
+		t.Fatalf("applyAlias converged: %v", err)
+	}
+	if err := gen.applyAlias(link, &pb.NetworkInterface{InterfaceName: "eth0"}); err != nil {
+		t.Fatalf("applyAlias unmanaged: %v", err)
+	}
+	if len(backend.aliases) != 0 {
+		t.Errorf("alias calls = %v, want none", backend.aliases)
+	}
+}
+
+func TestApplyAliasClearsOnEmpty(t *testing.T) {
+	backend := &fakeLinkBackend{}
+	gen := newInterfaceGenerator(backend)
+
+	link := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "eth0", Alias: "old label"}}
+	spec := &pb.NetworkInterface{InterfaceName: "eth0", Alias: strPtr("")}
+	if err := gen.applyAlias(link, spec); err != nil {
+		t.Fatalf("applyAlias: %v", err)
+	}
+	if got, ok := backend.aliases["eth0"]; !ok || got != "" {
+		t.Errorf("alias calls = %v, want eth0 cleared", backend.aliases)
+	}
+}
+
+func TestObserveLinkRecordsAlias(t *testing.T) {
+	link := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "eth0", Alias: "uplink to core"}}
+	_, status := observeLink(link, nil)
+	if got := status.GetAlias(); got != "uplink to core" {
+		t.Errorf("status alias = %q, want %q", got, "uplink to core")
+	}
+}
+
+func TestInterfaceGeneratorReconcilesOnFakeTick(t *testing.T) {
+	backend := &fakeLinkBackend{links: []netlink.Link{fakeLink("eth0", "aa:bb:cc:dd:ee:01", 1500)}}
+	gen := newInterfaceGenerator(backend)
+	fake := clock.NewFake(time.Unix(0, 0))
+	gen.clock = fake
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		gen.Run(ctx)
+	}()
+
+	waitForInterface := func(name string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			if _, err := gen.store.Get(ctx, api.KindNetworkInterface, state.DefaultNamespace, name); err == nil {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("interface %s never reconciled", name)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitForInterface("eth0")
+
+	// A new link appears; the next tick must pick it up without any
+	// real reconcileInterval elapsing.
+	backend.links = append(backend.links, fakeLink("eth1", "aa:bb:cc:dd:ee:02", 1500))
+	fake.Advance(reconcileInterval)
+	waitForInterface("eth1")
+
+	cancel()
+	<-done
+}
+
+func TestObserveLinkRecordsCarrier(t *testing.T) {
+	up := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "eth0", OperState: netlink.OperUp}}
+	_, status := observeLink(up, nil)
+	if !status.GetCarrier() {
+		t.Error("link with oper state up reported no carrier")
+	}
+	if got := status.GetOperState(); got != "up" {
+		t.Errorf("oper_state = %q, want up", got)
+	}
+
+	unplugged := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "eth0", OperState: netlink.OperLowerLayerDown}}
+	_, status = observeLink(unplugged, nil)
+	if status.GetCarrier() {
+		t.Error("link with lower layer down reported a carrier")
+	}
+	if got := status.GetOperState(); got != "lower-layer-down" {
+		t.Errorf("oper_state = %q, want lower-layer-down", got)
+	}
+}
+
+func TestCarrierChangeDoesNotBumpGeneration(t *testing.T) {
+	link := fakeLink("eth0", "aa:bb:cc:dd:ee:01", 1500)
+	backend := &fakeLinkBackend{links: []netlink.Link{link}}
+	g := newInterfaceGenerator(backend)
+	ctx := context.Background()
+
+	if err := g.reconcileInterfaces(ctx); err != nil {
+		t.Fatalf("reconcileInterfaces: %v", err)
+	}
+	res, err := g.store.Get(ctx, api.KindNetworkInterface, state.DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !res.GetNetworkInterfaceStatus().GetCarrier() {
+		t.Error("initial status reported no carrier")
+	}
+	gen := res.GetMetadata().GetGeneration()
+
+	// The cable is pulled: the next observation flips the carrier in
+	// status without touching the spec, so the generation stays put.
+	link.LinkAttrs.OperState = netlink.OperLowerLayerDown
+	if err := g.reconcileInterfaces(ctx); err != nil {
+		t.Fatalf("reconcileInterfaces: %v", err)
+	}
+	res, err = g.store.Get(ctx, api.KindNetworkInterface, state.DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if res.GetNetworkInterfaceStatus().GetCarrier() {
+		t.Error("status still reports a carrier after the link lost it")
+	}
+	if got := res.GetMetadata().GetGeneration(); got != gen {
+		t.Errorf("generation = %d after a status-only change, want %d", got, gen)
+	}
+}
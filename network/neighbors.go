@@ -0,0 +1,147 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/runtime"
+	"github.com/microrun/microrun/state"
+	"github.com/vishvananda/netlink"
+)
+
+// neighborBackend is the slice of netlink the neighbor generator reads.
+// Tests substitute a fake.
+type neighborBackend interface {
+	LinkList() ([]netlink.Link, error)
+	NeighList(linkIndex, family int) ([]netlink.Neigh, error)
+}
+
+// netlinkNeighborBackend delegates to the package-level netlink functions.
+type netlinkNeighborBackend struct{}
+
+func (netlinkNeighborBackend) LinkList() ([]netlink.Link, error) { return netlink.LinkList() }
+func (netlinkNeighborBackend) NeighList(linkIndex, family int) ([]netlink.Neigh, error) {
+	return netlink.NeighList(linkIndex, family)
+}
+
+// NeighborGeneratorType constructs generators that mirror the kernel's
+// neighbor table into Neighbor resources.
+type NeighborGeneratorType struct{}
+
+// Name implements runtime.GeneratorType.
+func (NeighborGeneratorType) Name() string {
+	return "network-neighbors"
+}
+
+// ManagedKinds implements runtime.GeneratorType.
+func (NeighborGeneratorType) ManagedKinds() []string {
+	return []string{api.KindNeighbor}
+}
+
+// WatchedKinds implements runtime.GeneratorType.
+func (NeighborGeneratorType) WatchedKinds() []string {
+	return nil
+}
+
+// New implements runtime.GeneratorType.
+func (NeighborGeneratorType) New(gctx runtime.GeneratorContext) (runtime.Generator, error) {
+	return &NeighborGenerator{
+		store:  gctx.Store,
+		neigh:  netlinkNeighborBackend{},
+		logger: gctx.Logger,
+	}, nil
+}
+
+// NeighborGenerator periodically reads the neighbor table and replaces
+// the Neighbor resources with what it observes. The resources are pure
+// observations: nothing in their spec is treated as desired state.
+type NeighborGenerator struct {
+	store  state.Store
+	neigh  neighborBackend
+	logger *slog.Logger
+}
+
+// Run implements runtime.Generator.
+func (g *NeighborGenerator) Run(ctx context.Context) error {
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := g.reconcileNeighbors(ctx); err != nil {
+			g.logger.Error("reconciling neighbors", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcileNeighbors reads the neighbor table off every link and swaps
+// the stored Neighbor resources for the observed set in one Replace.
+func (g *NeighborGenerator) reconcileNeighbors(ctx context.Context) error {
+	links, err := g.neigh.LinkList()
+	if err != nil {
+		return fmt.Errorf("listing links: %w", err)
+	}
+
+	var desired []*pb.Resource
+	for _, link := range links {
+		name := link.Attrs().Name
+		neighs, err := g.neigh.NeighList(link.Attrs().Index, netlink.FAMILY_ALL)
+		if err != nil {
+			g.logger.Error("listing neighbors", "link", name, "error", err)
+			continue
+		}
+		for _, neigh := range neighs {
+			if neigh.IP == nil {
+				continue
+			}
+			spec := &pb.Neighbor{
+				InterfaceRef: name,
+				Ip:           neigh.IP.String(),
+				State:        neighState(neigh.State),
+			}
+			if neigh.HardwareAddr != nil {
+				spec.Mac = neigh.HardwareAddr.String()
+			}
+			desired = append(desired, &pb.Resource{
+				Metadata: &pb.ResourceMetadata{
+					Name: fmt.Sprintf("%s-%s", name, spec.Ip),
+					Kind: api.KindNeighbor,
+				},
+				Spec: &pb.Resource_Neighbor{Neighbor: spec},
+			})
+		}
+	}
+	return g.store.Replace(ctx, api.KindNeighbor, desired)
+}
+
+// neighState renders a kernel NUD state bitmask as the most significant
+// state name it carries.
+func neighState(state int) string {
+	switch {
+	case state&netlink.NUD_PERMANENT != 0:
+		return "permanent"
+	case state&netlink.NUD_REACHABLE != 0:
+		return "reachable"
+	case state&netlink.NUD_STALE != 0:
+		return "stale"
+	case state&netlink.NUD_DELAY != 0:
+		return "delay"
+	case state&netlink.NUD_PROBE != 0:
+		return "probe"
+	case state&netlink.NUD_INCOMPLETE != 0:
+		return "incomplete"
+	case state&netlink.NUD_FAILED != 0:
+		return "failed"
+	case state&netlink.NUD_NOARP != 0:
+		return "noarp"
+	}
+	return "none"
+}
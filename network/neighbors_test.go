@@ -0,0 +1,119 @@
+package network
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/logging"
+	"github.com/microrun/microrun/state"
+	"github.com/vishvananda/netlink"
+)
+
+// fakeNeighborBackend reports a configurable neighbor table.
+type fakeNeighborBackend struct {
+	links  []netlink.Link
+	neighs map[int][]netlink.Neigh
+}
+
+func (f *fakeNeighborBackend) LinkList() ([]netlink.Link, error) {
+	return f.links, nil
+}
+
+func (f *fakeNeighborBackend) NeighList(linkIndex, family int) ([]netlink.Neigh, error) {
+	return f.neighs[linkIndex], nil
+}
+
+func newNeighborGenerator(backend *fakeNeighborBackend) *NeighborGenerator {
+	return &NeighborGenerator{
+		store:  state.NewMemoryStore(),
+		neigh:  backend,
+		logger: logging.NewLogger("network-neighbors"),
+	}
+}
+
+func testNeigh(ip, mac string, state int) netlink.Neigh {
+	hw, _ := net.ParseMAC(mac)
+	return netlink.Neigh{IP: net.ParseIP(ip), HardwareAddr: hw, State: state}
+}
+
+func TestNeighborGeneratorCreatesNeighbors(t *testing.T) {
+	link := fakeLink("eth0", "aa:bb:cc:dd:ee:01", 1500)
+	link.LinkAttrs.Index = 2
+	backend := &fakeNeighborBackend{
+		links: []netlink.Link{link},
+		neighs: map[int][]netlink.Neigh{
+			2: {
+				testNeigh("10.0.0.1", "aa:bb:cc:dd:ee:10", netlink.NUD_REACHABLE),
+				testNeigh("10.0.0.7", "aa:bb:cc:dd:ee:17", netlink.NUD_STALE),
+			},
+		},
+	}
+	g := newNeighborGenerator(backend)
+	ctx := context.Background()
+
+	if err := g.reconcileNeighbors(ctx); err != nil {
+		t.Fatalf("reconcileNeighbors: %v", err)
+	}
+
+	res, err := g.store.Get(ctx, api.KindNeighbor, state.DefaultNamespace, "eth0-10.0.0.1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	spec := res.GetNeighbor()
+	if spec.GetInterfaceRef() != "eth0" || spec.GetMac() != "aa:bb:cc:dd:ee:10" {
+		t.Errorf("neighbor spec = %v, want eth0/aa:bb:cc:dd:ee:10", spec)
+	}
+	if got := spec.GetState(); got != "reachable" {
+		t.Errorf("neighbor state = %q, want reachable", got)
+	}
+
+	res, err = g.store.Get(ctx, api.KindNeighbor, state.DefaultNamespace, "eth0-10.0.0.7")
+	if err != nil {
+		t.Fatalf("Get second neighbor: %v", err)
+	}
+	if got := res.GetNeighbor().GetState(); got != "stale" {
+		t.Errorf("second neighbor state = %q, want stale", got)
+	}
+}
+
+func TestNeighborGeneratorRemovesStaleEntries(t *testing.T) {
+	link := fakeLink("eth0", "aa:bb:cc:dd:ee:01", 1500)
+	link.LinkAttrs.Index = 2
+	backend := &fakeNeighborBackend{
+		links: []netlink.Link{link},
+		neighs: map[int][]netlink.Neigh{
+			2: {
+				testNeigh("10.0.0.1", "aa:bb:cc:dd:ee:10", netlink.NUD_REACHABLE),
+				testNeigh("10.0.0.7", "aa:bb:cc:dd:ee:17", netlink.NUD_REACHABLE),
+			},
+		},
+	}
+	g := newNeighborGenerator(backend)
+	ctx := context.Background()
+
+	if err := g.reconcileNeighbors(ctx); err != nil {
+		t.Fatalf("reconcileNeighbors: %v", err)
+	}
+	backend.neighs[2] = backend.neighs[2][:1]
+	if err := g.reconcileNeighbors(ctx); err != nil {
+		t.Fatalf("reconcileNeighbors after expiry: %v", err)
+	}
+
+	if _, err := g.store.Get(ctx, api.KindNeighbor, state.DefaultNamespace, "eth0-10.0.0.1"); err != nil {
+		t.Errorf("Get of live neighbor: %v", err)
+	}
+	if _, err := g.store.Get(ctx, api.KindNeighbor, state.DefaultNamespace, "eth0-10.0.0.7"); !state.IsNotFound(err) {
+		t.Errorf("Get of vanished neighbor = %v, want NotFoundError", err)
+	}
+}
+
+func TestNeighStateRendersMostSignificantBit(t *testing.T) {
+	if got := neighState(netlink.NUD_REACHABLE | netlink.NUD_STALE); got != "reachable" {
+		t.Errorf("neighState = %q, want reachable", got)
+	}
+	if got := neighState(0); got != "none" {
+		t.Errorf("neighState(0) = %q, want none", got)
+	}
+}
@@ -0,0 +1,205 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/runtime"
+	"github.com/microrun/microrun/state"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// routeProtocol marks routes programmed by the route generator so it can
+// tell its own routes apart from those installed by the kernel or other
+// daemons. The value is from the range reserved for routing daemons.
+const routeProtocol = 201
+
+// routeBackend abstracts the netlink operations the route generator needs,
+// so tests can run against a fake instead of the kernel.
+type routeBackend interface {
+	RouteList() ([]netlink.Route, error)
+	RouteAdd(route *netlink.Route) error
+	RouteDel(route *netlink.Route) error
+	LinkByName(name string) (netlink.Link, error)
+}
+
+// netlinkRouteBackend is the production backend delegating to netlink.
+type netlinkRouteBackend struct{}
+
+func (netlinkRouteBackend) RouteList() ([]netlink.Route, error) {
+	return netlink.RouteList(nil, netlink.FAMILY_ALL)
+}
+
+func (netlinkRouteBackend) RouteAdd(route *netlink.Route) error { return netlink.RouteAdd(route) }
+func (netlinkRouteBackend) RouteDel(route *netlink.Route) error { return netlink.RouteDel(route) }
+
+func (netlinkRouteBackend) LinkByName(name string) (netlink.Link, error) {
+	return netlink.LinkByName(name)
+}
+
+// RouteGeneratorType constructs generators that program the kernel routing
+// table from Route resources.
+type RouteGeneratorType struct{}
+
+// Name implements runtime.GeneratorType.
+func (RouteGeneratorType) Name() string {
+	return "network-routes"
+}
+
+// ManagedKinds implements runtime.GeneratorType.
+func (RouteGeneratorType) ManagedKinds() []string {
+	return []string{api.KindRoute}
+}
+
+// WatchedKinds implements runtime.GeneratorType.
+func (RouteGeneratorType) WatchedKinds() []string {
+	return nil
+}
+
+// New implements runtime.GeneratorType.
+func (RouteGeneratorType) New(gctx runtime.GeneratorContext) (runtime.Generator, error) {
+	store, err := state.NewTypedStore[*pb.Route](gctx.Store)
+	if err != nil {
+		return nil, err
+	}
+	return &RouteGenerator{
+		store:   store,
+		backend: netlinkRouteBackend{},
+		logger:  gctx.Logger,
+	}, nil
+}
+
+// RouteGenerator reconciles Route resources against the kernel routing
+// table: desired routes are added, and routes it previously programmed
+// that are no longer desired are removed.
+type RouteGenerator struct {
+	store   *state.TypedStore[*pb.Route]
+	backend routeBackend
+	logger  *slog.Logger
+}
+
+// Run implements runtime.Generator.
+func (g *RouteGenerator) Run(ctx context.Context) error {
+	events, err := g.store.Watch(ctx, state.WithInitialState())
+	if err != nil {
+		return err
+	}
+	// Reconcile once at startup even if no resources exist, to clean up
+	// routes left behind by a previous run.
+	if err := g.reconcileRoutes(ctx); err != nil {
+		g.logger.Error("reconciling routes", "error", err)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := g.reconcileRoutes(ctx); err != nil {
+				g.logger.Error("reconciling routes", "error", err)
+			}
+		}
+	}
+}
+
+// reconcileRoutes diffs the desired Route resources against the routes
+// currently installed and converges the kernel to the desired set.
+func (g *RouteGenerator) reconcileRoutes(ctx context.Context) error {
+	resources, err := g.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	desired := make(map[string]*netlink.Route, len(resources))
+	for _, res := range resources {
+		route, err := g.specToRoute(res.Spec)
+		if err != nil {
+			g.logger.Error("invalid route spec", "route", res.Metadata.GetName(), "error", err)
+			continue
+		}
+		desired[routeKey(route)] = route
+	}
+
+	system, err := g.backend.RouteList()
+	if err != nil {
+		return fmt.Errorf("listing routes: %w", err)
+	}
+	installed := make(map[string]netlink.Route, len(system))
+	for _, route := range system {
+		installed[routeKey(&route)] = route
+	}
+
+	for key, route := range desired {
+		if _, ok := installed[key]; ok {
+			continue
+		}
+		if err := g.backend.RouteAdd(route); err != nil {
+			g.logger.Error("adding route", "destination", route.Dst, "error", err)
+		}
+	}
+	for key, route := range installed {
+		// Only remove routes this generator programmed.
+		if route.Protocol != routeProtocol {
+			continue
+		}
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		route := route
+		if err := g.backend.RouteDel(&route); err != nil {
+			g.logger.Error("removing route", "destination", route.Dst, "error", err)
+		}
+	}
+	return nil
+}
+
+// specToRoute converts a Route spec into the netlink form the generator
+// programs into the kernel.
+func (g *RouteGenerator) specToRoute(spec *pb.Route) (*netlink.Route, error) {
+	_, dst, err := net.ParseCIDR(spec.GetDestination())
+	if err != nil {
+		return nil, fmt.Errorf("parsing destination: %w", err)
+	}
+	route := &netlink.Route{
+		Dst:      dst,
+		Priority: int(spec.GetMetric()),
+		Table:    int(spec.GetTable()),
+		Protocol: routeProtocol,
+	}
+	if route.Table == 0 {
+		route.Table = unix.RT_TABLE_MAIN
+	}
+	if gw := spec.GetGateway(); gw != "" {
+		route.Gw = net.ParseIP(gw)
+		if route.Gw == nil {
+			return nil, fmt.Errorf("invalid gateway %q", gw)
+		}
+	}
+	if ref := spec.GetInterfaceRef(); ref != "" {
+		link, err := g.backend.LinkByName(ref)
+		if err != nil {
+			return nil, fmt.Errorf("resolving interface %q: %w", ref, err)
+		}
+		route.LinkIndex = link.Attrs().Index
+	}
+	return route, nil
+}
+
+// routeKey identifies a route for diffing purposes.
+func routeKey(route *netlink.Route) string {
+	dst := "default"
+	if route.Dst != nil {
+		dst = route.Dst.String()
+	}
+	table := route.Table
+	if table == 0 {
+		table = unix.RT_TABLE_MAIN
+	}
+	return fmt.Sprintf("%s|%s|%d|%d|%d", dst, route.Gw, route.LinkIndex, route.Priority, table)
+}
@@ -0,0 +1,138 @@
+package network
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/logging"
+	"github.com/microrun/microrun/state"
+	"github.com/vishvananda/netlink"
+)
+
+// fakeRouteBackend records route mutations instead of touching the kernel.
+type fakeRouteBackend struct {
+	routes  []netlink.Route
+	links   map[string]int
+	added   []*netlink.Route
+	deleted []*netlink.Route
+}
+
+func (f *fakeRouteBackend) RouteList() ([]netlink.Route, error) { return f.routes, nil }
+
+func (f *fakeRouteBackend) RouteAdd(route *netlink.Route) error {
+	f.added = append(f.added, route)
+	return nil
+}
+
+func (f *fakeRouteBackend) RouteDel(route *netlink.Route) error {
+	f.deleted = append(f.deleted, route)
+	return nil
+}
+
+func (f *fakeRouteBackend) LinkByName(name string) (netlink.Link, error) {
+	return &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: name, Index: f.links[name]}}, nil
+}
+
+func newRouteGenerator(t *testing.T, backend *fakeRouteBackend) (*RouteGenerator, *state.TypedStore[*pb.Route]) {
+	t.Helper()
+	store, err := state.NewTypedStore[*pb.Route](state.NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+	return &RouteGenerator{
+		store:   store,
+		backend: backend,
+		logger:  logging.NewLogger("network-routes"),
+	}, store
+}
+
+func TestRouteGeneratorAddsDesiredRoutes(t *testing.T) {
+	ctx := context.Background()
+	backend := &fakeRouteBackend{links: map[string]int{"eth0": 2}}
+	gen, store := newRouteGenerator(t, backend)
+
+	spec := &pb.Route{
+		Destination:  "10.0.0.0/8",
+		Gateway:      "192.0.2.254",
+		InterfaceRef: "eth0",
+		Metric:       100,
+	}
+	if err := store.Create(ctx, "corp", spec); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := gen.reconcileRoutes(ctx); err != nil {
+		t.Fatalf("reconcileRoutes: %v", err)
+	}
+
+	if len(backend.added) != 1 {
+		t.Fatalf("added %d routes, want 1", len(backend.added))
+	}
+	route := backend.added[0]
+	if route.Dst.String() != "10.0.0.0/8" {
+		t.Errorf("destination = %s", route.Dst)
+	}
+	if !route.Gw.Equal(net.ParseIP("192.0.2.254")) {
+		t.Errorf("gateway = %s", route.Gw)
+	}
+	if route.LinkIndex != 2 {
+		t.Errorf("link index = %d, want 2", route.LinkIndex)
+	}
+	if route.Priority != 100 {
+		t.Errorf("metric = %d, want 100", route.Priority)
+	}
+	if route.Protocol != routeProtocol {
+		t.Errorf("protocol = %d, want %d", route.Protocol, routeProtocol)
+	}
+
+	// Re-reconciling with the route now installed adds nothing.
+	backend.routes = append(backend.routes, *route)
+	backend.added = nil
+	if err := gen.reconcileRoutes(ctx); err != nil {
+		t.Fatalf("reconcileRoutes: %v", err)
+	}
+	if len(backend.added) != 0 {
+		t.Errorf("re-reconcile added %d routes, want 0", len(backend.added))
+	}
+}
+
+func TestRouteGeneratorRemovesUndesiredRoutes(t *testing.T) {
+	ctx := context.Background()
+	_, dst, _ := net.ParseCIDR("10.0.0.0/8")
+	backend := &fakeRouteBackend{
+		routes: []netlink.Route{
+			// A route this generator programmed earlier.
+			{Dst: dst, Gw: net.ParseIP("192.0.2.254"), Protocol: routeProtocol},
+			// A foreign route that must be left alone.
+			{Dst: nil, Gw: net.ParseIP("192.0.2.1"), Protocol: 2},
+		},
+	}
+	gen, _ := newRouteGenerator(t, backend)
+
+	if err := gen.reconcileRoutes(ctx); err != nil {
+		t.Fatalf("reconcileRoutes: %v", err)
+	}
+	if len(backend.deleted) != 1 {
+		t.Fatalf("deleted %d routes, want 1", len(backend.deleted))
+	}
+	if backend.deleted[0].Dst.String() != "10.0.0.0/8" {
+		t.Errorf("deleted %s, want 10.0.0.0/8", backend.deleted[0].Dst)
+	}
+}
+
+func TestRouteGeneratorRejectsInvalidSpec(t *testing.T) {
+	ctx := context.Background()
+	backend := &fakeRouteBackend{}
+	gen, store := newRouteGenerator(t, backend)
+
+	if err := store.Create(ctx, "bad", &pb.Route{Destination: "not-a-cidr"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := gen.reconcileRoutes(ctx); err != nil {
+		t.Fatalf("reconcileRoutes: %v", err)
+	}
+	if len(backend.added) != 0 {
+		t.Errorf("invalid spec produced %d route adds", len(backend.added))
+	}
+}
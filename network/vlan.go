@@ -0,0 +1,164 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/runtime"
+	"github.com/microrun/microrun/state"
+	"github.com/vishvananda/netlink"
+)
+
+// vlanBackend abstracts the netlink operations the VLAN generator needs,
+// so tests can run against a fake instead of the kernel.
+type vlanBackend interface {
+	LinkByName(name string) (netlink.Link, error)
+	LinkAdd(link netlink.Link) error
+	LinkDel(link netlink.Link) error
+}
+
+// netlinkVlanBackend is the production backend delegating to netlink.
+type netlinkVlanBackend struct{}
+
+func (netlinkVlanBackend) LinkByName(name string) (netlink.Link, error) {
+	return netlink.LinkByName(name)
+}
+func (netlinkVlanBackend) LinkAdd(link netlink.Link) error { return netlink.LinkAdd(link) }
+func (netlinkVlanBackend) LinkDel(link netlink.Link) error { return netlink.LinkDel(link) }
+
+// VLANGeneratorType constructs generators that manage 802.1Q
+// subinterfaces from VLAN resources.
+type VLANGeneratorType struct{}
+
+// Name implements runtime.GeneratorType.
+func (VLANGeneratorType) Name() string {
+	return "network-vlans"
+}
+
+// ManagedKinds implements runtime.GeneratorType.
+func (VLANGeneratorType) ManagedKinds() []string {
+	return []string{api.KindVLAN}
+}
+
+// WatchedKinds implements runtime.GeneratorType.
+func (VLANGeneratorType) WatchedKinds() []string {
+	return nil
+}
+
+// New implements runtime.GeneratorType.
+func (VLANGeneratorType) New(gctx runtime.GeneratorContext) (runtime.Generator, error) {
+	store, err := state.NewTypedStore[*pb.VLAN](gctx.Store)
+	if err != nil {
+		return nil, err
+	}
+	return &VLANGenerator{
+		store:   store,
+		backend: netlinkVlanBackend{},
+		logger:  gctx.Logger,
+	}, nil
+}
+
+// VLANGenerator creates a VLAN subinterface per VLAN resource and deletes
+// it when the resource goes away. A changed parent or tag recreates the
+// link, since neither can be changed in place.
+type VLANGenerator struct {
+	store   *state.TypedStore[*pb.VLAN]
+	backend vlanBackend
+	logger  *slog.Logger
+}
+
+// Run implements runtime.Generator.
+func (g *VLANGenerator) Run(ctx context.Context) error {
+	events, err := g.store.Watch(ctx, state.WithInitialState())
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			g.handleEvent(ev)
+		}
+	}
+}
+
+func (g *VLANGenerator) handleEvent(ev state.TypedEvent[*pb.VLAN]) {
+	name := ev.Resource.Spec.GetVlanName()
+	switch ev.Type {
+	case state.EventCreated, state.EventUpdated:
+		if err := g.reconcileVlan(ev.Resource.Spec); err != nil {
+			g.logger.Error("reconciling vlan", "vlan", name, "error", err)
+		}
+	case state.EventDeleted:
+		if err := g.deleteVlan(name); err != nil {
+			g.logger.Error("deleting vlan", "vlan", name, "error", err)
+		}
+	}
+}
+
+// validateVLAN rejects specs the kernel would refuse, most importantly an
+// 802.1Q tag outside 1-4094.
+func validateVLAN(spec *pb.VLAN) error {
+	if spec.GetVlanName() == "" {
+		return fmt.Errorf("vlan_name must be set")
+	}
+	if spec.GetInterfaceRef() == "" {
+		return fmt.Errorf("interface_ref must be set")
+	}
+	if id := spec.GetVlanId(); id < 1 || id > 4094 {
+		return fmt.Errorf("vlan_id %d out of range 1-4094", id)
+	}
+	return nil
+}
+
+// reconcileVlan ensures a VLAN link matching spec exists, recreating it
+// when the stored parent or tag differs.
+func (g *VLANGenerator) reconcileVlan(spec *pb.VLAN) error {
+	if err := validateVLAN(spec); err != nil {
+		return err
+	}
+	parent, err := g.backend.LinkByName(spec.GetInterfaceRef())
+	if err != nil {
+		return fmt.Errorf("resolving parent %q: %w", spec.GetInterfaceRef(), err)
+	}
+	if existing, err := g.backend.LinkByName(spec.GetVlanName()); err == nil {
+		if vlan, ok := existing.(*netlink.Vlan); ok &&
+			vlan.VlanId == int(spec.GetVlanId()) &&
+			vlan.Attrs().ParentIndex == parent.Attrs().Index {
+			return nil
+		}
+		if err := g.backend.LinkDel(existing); err != nil {
+			return fmt.Errorf("removing stale vlan %s: %w", spec.GetVlanName(), err)
+		}
+	}
+	vlan := &netlink.Vlan{
+		LinkAttrs: netlink.LinkAttrs{
+			Name:        spec.GetVlanName(),
+			ParentIndex: parent.Attrs().Index,
+		},
+		VlanId: int(spec.GetVlanId()),
+	}
+	if err := g.backend.LinkAdd(vlan); err != nil {
+		return fmt.Errorf("creating vlan %s: %w", spec.GetVlanName(), err)
+	}
+	return nil
+}
+
+// deleteVlan removes the VLAN link; a link already gone is not an error.
+func (g *VLANGenerator) deleteVlan(name string) error {
+	link, err := g.backend.LinkByName(name)
+	if err != nil {
+		return nil
+	}
+	if err := g.backend.LinkDel(link); err != nil {
+		return fmt.Errorf("deleting vlan %s: %w", name, err)
+	}
+	return nil
+}
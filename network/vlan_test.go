@@ -0,0 +1,143 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/logging"
+	"github.com/microrun/microrun/state"
+	"github.com/vishvananda/netlink"
+)
+
+// fakeVlanBackend keeps an in-memory link table.
+type fakeVlanBackend struct {
+	links     map[string]netlink.Link
+	nextIndex int
+	deleted   []string
+}
+
+func newFakeVlanBackend(parents ...string) *fakeVlanBackend {
+	b := &fakeVlanBackend{links: make(map[string]netlink.Link)}
+	for _, name := range parents {
+		b.nextIndex++
+		b.links[name] = &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: name, Index: b.nextIndex}}
+	}
+	return b
+}
+
+func (b *fakeVlanBackend) LinkByName(name string) (netlink.Link, error) {
+	link, ok := b.links[name]
+	if !ok {
+		return nil, netlink.LinkNotFoundError{}
+	}
+	return link, nil
+}
+
+func (b *fakeVlanBackend) LinkAdd(link netlink.Link) error {
+	b.nextIndex++
+	link.Attrs().Index = b.nextIndex
+	b.links[link.Attrs().Name] = link
+	return nil
+}
+
+func (b *fakeVlanBackend) LinkDel(link netlink.Link) error {
+	name := link.Attrs().Name
+	delete(b.links, name)
+	b.deleted = append(b.deleted, name)
+	return nil
+}
+
+func newVLANGenerator(backend *fakeVlanBackend) *VLANGenerator {
+	return &VLANGenerator{
+		backend: backend,
+		logger:  logging.NewLogger("network-vlans"),
+	}
+}
+
+func vlanEvent(typ state.EventType, name string, spec *pb.VLAN) state.TypedEvent[*pb.VLAN] {
+	return state.TypedEvent[*pb.VLAN]{
+		Type: typ,
+		Resource: &state.TypedResource[*pb.VLAN]{
+			Metadata: &pb.ResourceMetadata{Name: name, Kind: "VLAN"},
+			Spec:     spec,
+		},
+	}
+}
+
+func TestVLANGeneratorCreatesLink(t *testing.T) {
+	backend := newFakeVlanBackend("eth0")
+	gen := newVLANGenerator(backend)
+
+	gen.handleEvent(vlanEvent(state.EventCreated, "mgmt", &pb.VLAN{
+		VlanName:     "eth0.100",
+		InterfaceRef: "eth0",
+		VlanId:       100,
+	}))
+
+	link, ok := backend.links["eth0.100"]
+	if !ok {
+		t.Fatal("vlan link eth0.100 not created")
+	}
+	vlan, ok := link.(*netlink.Vlan)
+	if !ok {
+		t.Fatalf("created link is %T, want *netlink.Vlan", link)
+	}
+	if vlan.VlanId != 100 {
+		t.Errorf("vlan id = %d, want 100", vlan.VlanId)
+	}
+	if parent := backend.links["eth0"].Attrs().Index; vlan.ParentIndex != parent {
+		t.Errorf("parent index = %d, want %d", vlan.ParentIndex, parent)
+	}
+}
+
+func TestVLANGeneratorDeletesLink(t *testing.T) {
+	backend := newFakeVlanBackend("eth0")
+	gen := newVLANGenerator(backend)
+
+	spec := &pb.VLAN{VlanName: "eth0.100", InterfaceRef: "eth0", VlanId: 100}
+	gen.handleEvent(vlanEvent(state.EventCreated, "mgmt", spec))
+	gen.handleEvent(vlanEvent(state.EventDeleted, "mgmt", spec))
+
+	if _, ok := backend.links["eth0.100"]; ok {
+		t.Error("vlan link still exists after delete")
+	}
+	if len(backend.deleted) != 1 || backend.deleted[0] != "eth0.100" {
+		t.Errorf("deleted links = %v, want [eth0.100]", backend.deleted)
+	}
+}
+
+func TestVLANGeneratorRecreatesOnTagChange(t *testing.T) {
+	backend := newFakeVlanBackend("eth0")
+	gen := newVLANGenerator(backend)
+
+	gen.handleEvent(vlanEvent(state.EventCreated, "mgmt", &pb.VLAN{
+		VlanName: "eth0.100", InterfaceRef: "eth0", VlanId: 100,
+	}))
+	gen.handleEvent(vlanEvent(state.EventUpdated, "mgmt", &pb.VLAN{
+		VlanName: "eth0.100", InterfaceRef: "eth0", VlanId: 200,
+	}))
+
+	vlan, ok := backend.links["eth0.100"].(*netlink.Vlan)
+	if !ok {
+		t.Fatal("vlan link missing after update")
+	}
+	if vlan.VlanId != 200 {
+		t.Errorf("vlan id after update = %d, want 200", vlan.VlanId)
+	}
+	if len(backend.deleted) != 1 {
+		t.Errorf("stale link deleted %d times, want 1", len(backend.deleted))
+	}
+}
+
+func TestValidateVLANRejectsOutOfRangeID(t *testing.T) {
+	for _, id := range []uint32{0, 4095, 65535} {
+		spec := &pb.VLAN{VlanName: "eth0.x", InterfaceRef: "eth0", VlanId: id}
+		if err := validateVLAN(spec); err == nil {
+			t.Errorf("validateVLAN accepted vlan_id %d, want error", id)
+		}
+	}
+	ok := &pb.VLAN{VlanName: "eth0.1", InterfaceRef: "eth0", VlanId: 1}
+	if err := validateVLAN(ok); err != nil {
+		t.Errorf("validateVLAN(%v) = %v, want nil", ok, err)
+	}
+}
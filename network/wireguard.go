@@ -0,0 +1,269 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/runtime"
+	"github.com/microrun/microrun/state"
+	"github.com/vishvananda/netlink"
+	"golang.zx2c4.com/wireguard/wgctrl"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+// wireguardBackend abstracts the netlink and wgctrl operations the
+// WireGuard generator needs, so tests can run against a fake.
+type wireguardBackend interface {
+	LinkByName(name string) (netlink.Link, error)
+	LinkAdd(link netlink.Link) error
+	LinkDel(link netlink.Link) error
+	Device(name string) (*wgtypes.Device, error)
+	ConfigureDevice(name string, cfg wgtypes.Config) error
+}
+
+// wgctrlBackend is the production backend delegating to netlink and a
+// wgctrl client.
+type wgctrlBackend struct {
+	client *wgctrl.Client
+}
+
+func (wgctrlBackend) LinkByName(name string) (netlink.Link, error) {
+	return netlink.LinkByName(name)
+}
+func (wgctrlBackend) LinkAdd(link netlink.Link) error { return netlink.LinkAdd(link) }
+func (wgctrlBackend) LinkDel(link netlink.Link) error { return netlink.LinkDel(link) }
+func (b wgctrlBackend) Device(name string) (*wgtypes.Device, error) {
+	return b.client.Device(name)
+}
+func (b wgctrlBackend) ConfigureDevice(name string, cfg wgtypes.Config) error {
+	return b.client.ConfigureDevice(name, cfg)
+}
+
+// WireGuardGeneratorType constructs generators that manage WireGuard
+// devices from WireGuardInterface resources.
+type WireGuardGeneratorType struct{}
+
+// Name implements runtime.GeneratorType.
+func (WireGuardGeneratorType) Name() string {
+	return "network-wireguard"
+}
+
+// ManagedKinds implements runtime.GeneratorType.
+func (WireGuardGeneratorType) ManagedKinds() []string {
+	return []string{api.KindWireGuardInterface}
+}
+
+// WatchedKinds implements runtime.GeneratorType.
+func (WireGuardGeneratorType) WatchedKinds() []string {
+	return nil
+}
+
+// New implements runtime.GeneratorType.
+func (WireGuardGeneratorType) New(gctx runtime.GeneratorContext) (runtime.Generator, error) {
+	store, err := state.NewTypedStore[*pb.WireGuardInterface](gctx.Store)
+	if err != nil {
+		return nil, err
+	}
+	client, err := wgctrl.New()
+	if err != nil {
+		return nil, fmt.Errorf("opening wgctrl client: %w", err)
+	}
+	return &WireGuardGenerator{
+		store:   store,
+		backend: wgctrlBackend{client: client},
+		logger:  gctx.Logger,
+	}, nil
+}
+
+// WireGuardGenerator creates a WireGuard link per WireGuardInterface
+// resource and keeps the device's peers in sync with the spec, touching
+// only peers that were added, changed, or removed.
+type WireGuardGenerator struct {
+	store   *state.TypedStore[*pb.WireGuardInterface]
+	backend wireguardBackend
+	logger  *slog.Logger
+}
+
+// Run implements runtime.Generator.
+func (g *WireGuardGenerator) Run(ctx context.Context) error {
+	events, err := g.store.Watch(ctx, state.WithInitialState())
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			g.handleEvent(ev)
+		}
+	}
+}
+
+func (g *WireGuardGenerator) handleEvent(ev state.TypedEvent[*pb.WireGuardInterface]) {
+	name := ev.Resource.Spec.GetInterfaceName()
+	switch ev.Type {
+	case state.EventCreated, state.EventUpdated:
+		if err := g.reconcileWireGuard(ev.Resource.Spec); err != nil {
+			g.logger.Error("reconciling wireguard device", "device", name, "error", err)
+		}
+	case state.EventDeleted:
+		if err := g.deleteWireGuard(name); err != nil {
+			g.logger.Error("deleting wireguard device", "device", name, "error", err)
+		}
+	}
+}
+
+// reconcileWireGuard ensures the device exists and applies the key,
+// listen port, and the peer diff against what the device reports.
+func (g *WireGuardGenerator) reconcileWireGuard(spec *pb.WireGuardInterface) error {
+	name := spec.GetInterfaceName()
+	if _, err := g.backend.LinkByName(name); err != nil {
+		wg := &netlink.Wireguard{LinkAttrs: netlink.LinkAttrs{Name: name}}
+		if err := g.backend.LinkAdd(wg); err != nil {
+			return fmt.Errorf("creating wireguard link %s: %w", name, err)
+		}
+	}
+
+	cfg := wgtypes.Config{}
+	if ref := spec.GetPrivateKeyRef(); ref != "" {
+		keyData, err := os.ReadFile(ref)
+		if err != nil {
+			return fmt.Errorf("reading private key %s: %w", ref, err)
+		}
+		key, err := wgtypes.ParseKey(strings.TrimSpace(string(keyData)))
+		if err != nil {
+			return fmt.Errorf("parsing private key %s: %w", ref, err)
+		}
+		cfg.PrivateKey = &key
+	}
+	if port := spec.GetListenPort(); port != 0 {
+		listenPort := int(port)
+		cfg.ListenPort = &listenPort
+	}
+
+	device, err := g.backend.Device(name)
+	if err != nil {
+		return fmt.Errorf("reading wireguard device %s: %w", name, err)
+	}
+	peers, err := diffPeers(device.Peers, spec.GetPeers())
+	if err != nil {
+		return err
+	}
+	cfg.Peers = peers
+	return g.backend.ConfigureDevice(name, cfg)
+}
+
+// deleteWireGuard removes the device's link; the peers go with it.
+func (g *WireGuardGenerator) deleteWireGuard(name string) error {
+	link, err := g.backend.LinkByName(name)
+	if err != nil {
+		return nil
+	}
+	return g.backend.LinkDel(link)
+}
+
+// diffPeers compares the device's current peers against the desired spec
+// and returns the minimal set of peer configs: additions and changes
+// (with their allowed IPs replaced wholesale) plus removals for peers no
+// longer in the spec. Unchanged peers are left out entirely.
+func diffPeers(current []wgtypes.Peer, desired []*pb.WireGuardPeer) ([]wgtypes.PeerConfig, error) {
+	currentByKey := make(map[wgtypes.Key]wgtypes.Peer, len(current))
+	for _, peer := range current {
+		currentByKey[peer.PublicKey] = peer
+	}
+
+	var configs []wgtypes.PeerConfig
+	desiredKeys := make(map[wgtypes.Key]bool, len(desired))
+	for _, want := range desired {
+		key, err := wgtypes.ParseKey(want.GetPublicKey())
+		if err != nil {
+			return nil, fmt.Errorf("parsing peer public key %q: %w", want.GetPublicKey(), err)
+		}
+		desiredKeys[key] = true
+
+		cfg, err := peerConfig(key, want)
+		if err != nil {
+			return nil, err
+		}
+		if have, ok := currentByKey[key]; ok && peerConverged(have, cfg) {
+			continue
+		}
+		configs = append(configs, cfg)
+	}
+	for _, peer := range current {
+		if !desiredKeys[peer.PublicKey] {
+			configs = append(configs, wgtypes.PeerConfig{PublicKey: peer.PublicKey, Remove: true})
+		}
+	}
+	return configs, nil
+}
+
+// peerConfig renders one desired peer as a wgtypes config.
+func peerConfig(key wgtypes.Key, want *pb.WireGuardPeer) (wgtypes.PeerConfig, error) {
+	cfg := wgtypes.PeerConfig{PublicKey: key, ReplaceAllowedIPs: true}
+	for _, cidr := range want.GetAllowedIps() {
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return cfg, fmt.Errorf("parsing allowed IP %q: %w", cidr, err)
+		}
+		cfg.AllowedIPs = append(cfg.AllowedIPs, *ipnet)
+	}
+	if endpoint := want.GetEndpoint(); endpoint != "" {
+		addr, err := net.ResolveUDPAddr("udp", endpoint)
+		if err != nil {
+			return cfg, fmt.Errorf("resolving peer endpoint %q: %w", endpoint, err)
+		}
+		cfg.Endpoint = addr
+	}
+	if secs := want.GetPersistentKeepaliveSeconds(); secs != 0 {
+		interval := time.Duration(secs) * time.Second
+		cfg.PersistentKeepaliveInterval = &interval
+	}
+	return cfg, nil
+}
+
+// peerConverged reports whether the device's peer already matches the
+// desired config, so the sync can skip it.
+func peerConverged(have wgtypes.Peer, want wgtypes.PeerConfig) bool {
+	haveIPs := make([]string, 0, len(have.AllowedIPs))
+	for _, ipnet := range have.AllowedIPs {
+		haveIPs = append(haveIPs, ipnet.String())
+	}
+	wantIPs := make([]string, 0, len(want.AllowedIPs))
+	for _, ipnet := range want.AllowedIPs {
+		wantIPs = append(wantIPs, ipnet.String())
+	}
+	slices.Sort(haveIPs)
+	slices.Sort(wantIPs)
+	if !slices.Equal(haveIPs, wantIPs) {
+		return false
+	}
+	haveEndpoint := ""
+	if have.Endpoint != nil {
+		haveEndpoint = have.Endpoint.String()
+	}
+	wantEndpoint := ""
+	if want.Endpoint != nil {
+		wantEndpoint = want.Endpoint.String()
+	}
+	if haveEndpoint != wantEndpoint {
+		return false
+	}
+	wantKeepalive := time.Duration(0)
+	if want.PersistentKeepaliveInterval != nil {
+		wantKeepalive = *want.PersistentKeepaliveInterval
+	}
+	return have.PersistentKeepaliveInterval == wantKeepalive
+}
@@ -0,0 +1,176 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/logging"
+	"github.com/microrun/microrun/state"
+	"github.com/vishvananda/netlink"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+// fakeWireGuardBackend keeps a device's peers in memory and records the
+// configs applied to it.
+type fakeWireGuardBackend struct {
+	links   map[string]netlink.Link
+	device  *wgtypes.Device
+	applied []wgtypes.Config
+	deleted []string
+}
+
+func newFakeWireGuardBackend() *fakeWireGuardBackend {
+	return &fakeWireGuardBackend{
+		links:  make(map[string]netlink.Link),
+		device: &wgtypes.Device{},
+	}
+}
+
+func (f *fakeWireGuardBackend) LinkByName(name string) (netlink.Link, error) {
+	if link, ok := f.links[name]; ok {
+		return link, nil
+	}
+	return nil, netlink.LinkNotFoundError{}
+}
+
+func (f *fakeWireGuardBackend) LinkAdd(link netlink.Link) error {
+	f.links[link.Attrs().Name] = link
+	return nil
+}
+
+func (f *fakeWireGuardBackend) LinkDel(link netlink.Link) error {
+	f.deleted = append(f.deleted, link.Attrs().Name)
+	delete(f.links, link.Attrs().Name)
+	return nil
+}
+
+func (f *fakeWireGuardBackend) Device(name string) (*wgtypes.Device, error) {
+	return f.device, nil
+}
+
+func (f *fakeWireGuardBackend) ConfigureDevice(name string, cfg wgtypes.Config) error {
+	f.applied = append(f.applied, cfg)
+	return nil
+}
+
+func newWireGuardGenerator(t *testing.T, backend *fakeWireGuardBackend) *WireGuardGenerator {
+	t.Helper()
+	store, err := state.NewTypedStore[*pb.WireGuardInterface](state.NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+	return &WireGuardGenerator{
+		store:   store,
+		backend: backend,
+		logger:  logging.NewLogger("network-wireguard"),
+	}
+}
+
+func testPeerKey(t *testing.T) wgtypes.Key {
+	t.Helper()
+	key, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("GeneratePrivateKey: %v", err)
+	}
+	return key.PublicKey()
+}
+
+func TestWireGuardGeneratorCreatesDeviceAndPeers(t *testing.T) {
+	backend := newFakeWireGuardBackend()
+	gen := newWireGuardGenerator(t, backend)
+	peer := testPeerKey(t)
+
+	spec := &pb.WireGuardInterface{
+		InterfaceName: "wg0",
+		ListenPort:    51820,
+		Peers: []*pb.WireGuardPeer{{
+			PublicKey:  peer.String(),
+			AllowedIps: []string{"10.10.0.0/24"},
+			Endpoint:   "192.0.2.10:51820",
+		}},
+	}
+	if err := gen.reconcileWireGuard(spec); err != nil {
+		t.Fatalf("reconcileWireGuard: %v", err)
+	}
+
+	if _, ok := backend.links["wg0"]; !ok {
+		t.Error("wg0 link was not created")
+	}
+	if len(backend.applied) != 1 {
+		t.Fatalf("got %d configs, want 1", len(backend.applied))
+	}
+	cfg := backend.applied[0]
+	if cfg.ListenPort == nil || *cfg.ListenPort != 51820 {
+		t.Errorf("listen port = %v, want 51820", cfg.ListenPort)
+	}
+	if len(cfg.Peers) != 1 || cfg.Peers[0].PublicKey != peer {
+		t.Fatalf("peers = %v, want the one desired peer", cfg.Peers)
+	}
+	if !cfg.Peers[0].ReplaceAllowedIPs || len(cfg.Peers[0].AllowedIPs) != 1 {
+		t.Errorf("peer allowed IPs = %v, want [10.10.0.0/24] replaced", cfg.Peers[0].AllowedIPs)
+	}
+}
+
+func TestWireGuardGeneratorSyncsOnlyChangedPeers(t *testing.T) {
+	backend := newFakeWireGuardBackend()
+	gen := newWireGuardGenerator(t, backend)
+	kept, changed, dropped := testPeerKey(t), testPeerKey(t), testPeerKey(t)
+
+	_, keptNet, _ := net.ParseCIDR("10.10.0.0/24")
+	_, oldNet, _ := net.ParseCIDR("10.20.0.0/24")
+	backend.device.Peers = []wgtypes.Peer{
+		{PublicKey: kept, AllowedIPs: []net.IPNet{*keptNet}},
+		{PublicKey: changed, AllowedIPs: []net.IPNet{*oldNet}},
+		{PublicKey: dropped},
+	}
+
+	spec := &pb.WireGuardInterface{
+		InterfaceName: "wg0",
+		Peers: []*pb.WireGuardPeer{
+			{PublicKey: kept.String(), AllowedIps: []string{"10.10.0.0/24"}},
+			{PublicKey: changed.String(), AllowedIps: []string{"10.30.0.0/24"}},
+		},
+	}
+	if err := gen.reconcileWireGuard(spec); err != nil {
+		t.Fatalf("reconcileWireGuard: %v", err)
+	}
+
+	if len(backend.applied) != 1 {
+		t.Fatalf("got %d configs, want 1", len(backend.applied))
+	}
+	peers := backend.applied[0].Peers
+	if len(peers) != 2 {
+		t.Fatalf("synced %d peers, want only the changed and removed ones: %v", len(peers), peers)
+	}
+	byKey := make(map[wgtypes.Key]wgtypes.PeerConfig, len(peers))
+	for _, peer := range peers {
+		byKey[peer.PublicKey] = peer
+	}
+	if _, ok := byKey[kept]; ok {
+		t.Error("unchanged peer was re-synced")
+	}
+	if cfg, ok := byKey[changed]; !ok || cfg.Remove {
+		t.Errorf("changed peer config = %v, want an update", cfg)
+	} else if len(cfg.AllowedIPs) != 1 || cfg.AllowedIPs[0].String() != "10.30.0.0/24" {
+		t.Errorf("changed peer allowed IPs = %v, want [10.30.0.0/24]", cfg.AllowedIPs)
+	}
+	if cfg, ok := byKey[dropped]; !ok || !cfg.Remove {
+		t.Errorf("dropped peer config = %v, want a removal", cfg)
+	}
+}
+
+func TestWireGuardGeneratorDeletesDevice(t *testing.T) {
+	backend := newFakeWireGuardBackend()
+	gen := newWireGuardGenerator(t, backend)
+
+	if err := gen.reconcileWireGuard(&pb.WireGuardInterface{InterfaceName: "wg0"}); err != nil {
+		t.Fatalf("reconcileWireGuard: %v", err)
+	}
+	if err := gen.deleteWireGuard("wg0"); err != nil {
+		t.Fatalf("deleteWireGuard: %v", err)
+	}
+	if len(backend.deleted) != 1 || backend.deleted[0] != "wg0" {
+		t.Errorf("deleted links = %v, want [wg0]", backend.deleted)
+	}
+}
@@ -0,0 +1,187 @@
+package runtime
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/microrun/microrun/state"
+)
+
+// ReconcileRequest identifies a resource that needs reconciling.
+type ReconcileRequest struct {
+	Kind string
+	Name string
+}
+
+// Controller is a level-triggered alternative to implementing Generator
+// directly. Instead of hand-rolling a watch loop, a controller is handed
+// the key of each changed resource and reconciles the world to match; it
+// must look up current state itself, as the event that triggered the call
+// may be stale by the time it runs. Returning an error requeues the key
+// with backoff.
+type Controller interface {
+	Reconcile(ctx context.Context, req ReconcileRequest) error
+}
+
+// ControllerType adapts a Controller constructor into a GeneratorType.
+// The runtime harness watches the managed kinds, enqueues changed keys
+// into a deduplicating workqueue, and calls Reconcile for each.
+type ControllerType struct {
+	// TypeName is the generator type name, also the ownership scope.
+	TypeName string
+	// Kinds are the resource kinds watched and managed by the controller.
+	Kinds []string
+	// NewController constructs the controller.
+	NewController func(gctx GeneratorContext) (Controller, error)
+	// RequeueBackoff is the base delay before a failed key is retried;
+	// it doubles per consecutive failure of that key. Defaults to 100ms.
+	RequeueBackoff time.Duration
+}
+
+// Name implements GeneratorType.
+func (t *ControllerType) Name() string { return t.TypeName }
+
+// ManagedKinds implements GeneratorType.
+func (t *ControllerType) ManagedKinds() []string { return t.Kinds }
+
+// WatchedKinds implements GeneratorType.
+func (t *ControllerType) WatchedKinds() []string { return nil }
+
+// New implements GeneratorType.
+func (t *ControllerType) New(gctx GeneratorContext) (Generator, error) {
+	controller, err := t.NewController(gctx)
+	if err != nil {
+		return nil, err
+	}
+	backoff := t.RequeueBackoff
+	if backoff == 0 {
+		backoff = 100 * time.Millisecond
+	}
+	return &controllerHarness{
+		controller: controller,
+		name:       gctx.Name,
+		kinds:      t.Kinds,
+		store:      gctx.Store,
+		logger:     gctx.Logger,
+		metrics:    gctx.Metrics,
+		backoff:    backoff,
+	}, nil
+}
+
+// controllerHarness runs the watch/enqueue/reconcile loop around a
+// Controller.
+type controllerHarness struct {
+	controller Controller
+	name       string
+	kinds      []string
+	store      state.Store
+	logger     *slog.Logger
+	metrics    *Metrics
+	backoff    time.Duration
+}
+
+// Run implements Generator.
+func (h *controllerHarness) Run(ctx context.Context) error {
+	queue := newWorkqueue()
+	defer queue.shutDown()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for _, kind := range h.kinds {
+		events, err := h.store.Watch(ctx, kind, state.DefaultNamespace, state.WithInitialState())
+		if err != nil {
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ev := range events {
+				queue.add(ReconcileRequest{
+					Kind: ev.Resource.GetMetadata().GetKind(),
+					Name: ev.Resource.GetMetadata().GetName(),
+				})
+			}
+		}()
+	}
+
+	go func() {
+		<-ctx.Done()
+		queue.shutDown()
+	}()
+
+	failures := make(map[ReconcileRequest]int)
+	for {
+		req, ok := queue.get()
+		if !ok {
+			return nil
+		}
+		start := time.Now()
+		err := h.controller.Reconcile(ctx, req)
+		h.metrics.ObserveReconcile(h.name, start, err)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			failures[req]++
+			delay := h.backoff << (failures[req] - 1)
+			h.logger.Error("reconcile failed", "kind", req.Kind, "name", req.Name,
+				"error", err, "requeue_after", delay)
+			time.AfterFunc(delay, func() { queue.add(req) })
+		} else {
+			delete(failures, req)
+		}
+	}
+}
+
+// workqueue is a deduplicating FIFO of reconcile requests. Adding a key
+// that is already queued is a no-op, which coalesces bursts of events for
+// the same resource into a single reconcile.
+type workqueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	items   []ReconcileRequest
+	present map[ReconcileRequest]bool
+	shut    bool
+}
+
+func newWorkqueue() *workqueue {
+	q := &workqueue{present: make(map[ReconcileRequest]bool)}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *workqueue) add(req ReconcileRequest) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shut || q.present[req] {
+		return
+	}
+	q.items = append(q.items, req)
+	q.present[req] = true
+	q.cond.Signal()
+}
+
+// get blocks until an item is available or the queue is shut down.
+func (q *workqueue) get() (ReconcileRequest, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) == 0 && !q.shut {
+		q.cond.Wait()
+	}
+	if len(q.items) == 0 {
+		return ReconcileRequest{}, false
+	}
+	req := q.items[0]
+	q.items = q.items[1:]
+	delete(q.present, req)
+	return req, true
+}
+
+func (q *workqueue) shutDown() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.shut = true
+	q.cond.Broadcast()
+}
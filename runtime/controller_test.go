@@ -0,0 +1,128 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/state"
+)
+
+// fakeController records every reconcile request it receives.
+type fakeController struct {
+	mu       sync.Mutex
+	requests []ReconcileRequest
+	fail     map[ReconcileRequest]int
+}
+
+func (c *fakeController) Reconcile(ctx context.Context, req ReconcileRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	if c.fail[req] > 0 {
+		c.fail[req]--
+		return fmt.Errorf("transient failure for %s/%s", req.Kind, req.Name)
+	}
+	return nil
+}
+
+func (c *fakeController) calls(req ReconcileRequest) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, got := range c.requests {
+		if got == req {
+			n++
+		}
+	}
+	return n
+}
+
+func waitForCalls(t *testing.T, c *fakeController, req ReconcileRequest, want int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if c.calls(req) >= want {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("reconcile of %s/%s called %d times, want at least %d",
+		req.Kind, req.Name, c.calls(req), want)
+}
+
+func TestControllerReconcilesOnEvents(t *testing.T) {
+	store := state.NewMemoryStore()
+	rt := New(store)
+
+	controller := &fakeController{}
+	err := rt.RegisterGenerator(&ControllerType{
+		TypeName: "fake-controller",
+		Kinds:    []string{api.KindNetworkInterface},
+		NewController: func(gctx GeneratorContext) (Controller, error) {
+			return controller, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator: %v", err)
+	}
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer rt.Stop(context.Background())
+
+	ctx := context.Background()
+	req := ReconcileRequest{Kind: api.KindNetworkInterface, Name: "eth0"}
+
+	if err := store.Create(ctx, testInterface("eth0")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	waitForCalls(t, controller, req, 1)
+
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.GetNetworkInterface().MacAddress = "aa:bb:cc:dd:ee:ff"
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	waitForCalls(t, controller, req, 2)
+
+	if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	waitForCalls(t, controller, req, 3)
+}
+
+func TestControllerRequeuesOnError(t *testing.T) {
+	store := state.NewMemoryStore()
+	rt := New(store)
+
+	req := ReconcileRequest{Kind: api.KindNetworkInterface, Name: "eth0"}
+	controller := &fakeController{fail: map[ReconcileRequest]int{req: 2}}
+	err := rt.RegisterGenerator(&ControllerType{
+		TypeName: "fake-controller",
+		Kinds:    []string{api.KindNetworkInterface},
+		NewController: func(gctx GeneratorContext) (Controller, error) {
+			return controller, nil
+		},
+		RequeueBackoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator: %v", err)
+	}
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer rt.Stop(context.Background())
+
+	if err := store.Create(context.Background(), testInterface("eth0")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// Two failing attempts plus the final successful one.
+	waitForCalls(t, controller, req, 3)
+}
@@ -0,0 +1,25 @@
+package runtime
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// HealthHandler returns an HTTP handler exposing the runtime's health:
+// /healthz answers 200 as long as the process is serving requests, and
+// /readyz answers 200 once the runtime is Ready and 503 before that.
+// Orchestrators point their liveness and readiness probes at these paths.
+func (r *Runtime) HealthHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, req *http.Request) {
+		if !r.Ready() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
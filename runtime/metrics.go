@@ -0,0 +1,52 @@
+package runtime
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics exports reconcile instrumentation for generators: how long each
+// reconcile takes and how often it fails, labelled by generator name. The
+// controller harness records into it automatically; Run-style generators
+// receive it through their GeneratorContext and call ObserveReconcile
+// around their own work.
+type Metrics struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+// NewMetrics returns reconcile metrics registered with reg.
+func NewMetrics(reg prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "microrun_runtime_reconcile_duration_seconds",
+			Help:    "Duration of generator reconciles, by generator.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"generator"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "microrun_runtime_reconcile_errors_total",
+			Help: "Failed generator reconciles, by generator.",
+		}, []string{"generator"}),
+	}
+	for _, c := range []prometheus.Collector{m.duration, m.errors} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ObserveReconcile records one reconcile of the named generator: the time
+// elapsed since start, plus an error tick when err is non-nil. Calling it
+// on a nil Metrics is a no-op, so generators need not check whether the
+// runtime was given metrics.
+func (m *Metrics) ObserveReconcile(generator string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	m.duration.WithLabelValues(generator).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.errors.WithLabelValues(generator).Inc()
+	}
+}
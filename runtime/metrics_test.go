@@ -0,0 +1,75 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/state"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestMetricsRecordReconciles(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	metrics, err := NewMetrics(reg)
+	if err != nil {
+		t.Fatalf("NewMetrics: %v", err)
+	}
+	store := state.NewMemoryStore()
+	rt := New(store, WithMetrics(metrics))
+
+	req := ReconcileRequest{Kind: api.KindNetworkInterface, Name: "eth0"}
+	controller := &fakeController{fail: map[ReconcileRequest]int{req: 1}}
+	err = rt.RegisterGenerator(&ControllerType{
+		TypeName: "fake-controller",
+		Kinds:    []string{api.KindNetworkInterface},
+		NewController: func(gctx GeneratorContext) (Controller, error) {
+			return controller, nil
+		},
+		RequeueBackoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator: %v", err)
+	}
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer rt.Stop(context.Background())
+
+	ctx := context.Background()
+	if err := store.Create(ctx, testInterface("eth0")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// First reconcile fails, the requeued one succeeds.
+	waitForCalls(t, controller, req, 2)
+
+	histogram := metrics.duration.WithLabelValues("fake-controller").(prometheus.Histogram)
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) && sampleCount(t, histogram) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	if got := sampleCount(t, histogram); got < 2 {
+		t.Errorf("duration histogram recorded %d reconciles, want at least 2", got)
+	}
+	if got := testutil.ToFloat64(metrics.errors.WithLabelValues("fake-controller")); got != 1 {
+		t.Errorf("error counter = %v, want 1", got)
+	}
+}
+
+// sampleCount returns the number of observations the histogram holds.
+func sampleCount(t *testing.T, histogram prometheus.Histogram) uint64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := histogram.Write(&metric); err != nil {
+		t.Fatalf("reading histogram: %v", err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestNilMetricsIsNoOp(t *testing.T) {
+	var metrics *Metrics
+	metrics.ObserveReconcile("fake-controller", time.Now(), nil)
+}
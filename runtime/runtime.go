@@ -0,0 +1,454 @@
+// Package runtime hosts generators: long-running components that observe
+// the system and reconcile resources in the store. The runtime scopes each
+// generator's store access to its managed kinds and its own ownership.
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/microrun/microrun/logging"
+	"github.com/microrun/microrun/state"
+)
+
+// Generator is a long-running component started by the runtime. Run should
+// block until ctx is cancelled and return nil on clean shutdown.
+type Generator interface {
+	Run(ctx context.Context) error
+}
+
+// ShutdownGenerator is implemented by generators that need to clean up
+// external state (release leases, remove configured addresses) once their
+// Run loop has exited. The runtime calls Shutdown after cancelling the run
+// context and waiting for Run to return, bounded by the stop context.
+type ShutdownGenerator interface {
+	Shutdown(ctx context.Context) error
+}
+
+// GeneratorContext carries the dependencies handed to a generator when it
+// is constructed.
+type GeneratorContext struct {
+	// Name is the name the generator was registered under.
+	Name string
+	// Store is scoped to the generator's managed kinds and ownership.
+	Store state.Store
+	// Logger is tagged with the generator's name.
+	Logger *slog.Logger
+	// Metrics records reconcile durations and errors; nil (a no-op) when
+	// the runtime was built without WithMetrics.
+	Metrics *Metrics
+}
+
+// GeneratorType describes a kind of generator and knows how to construct
+// instances of it.
+type GeneratorType interface {
+	// Name identifies the generator type; it is also the ownership scope
+	// for resources the generator creates.
+	Name() string
+	// ManagedKinds lists the resource kinds the generator owns: it may
+	// read and write them.
+	ManagedKinds() []string
+	// WatchedKinds lists resource kinds the generator needs to observe
+	// but must not mutate. The runtime grants read-only access to them.
+	WatchedKinds() []string
+	// New constructs a generator with the given context.
+	New(gctx GeneratorContext) (Generator, error)
+}
+
+// DependentGeneratorType is implemented by generator types that must not
+// start before other generators are running, for example a DHCP controller
+// that needs the interface generator to have populated the store. The
+// returned names are generator names as used at registration; the runtime
+// delays the dependent's start until each of them has entered its Run
+// loop.
+type DependentGeneratorType interface {
+	DependsOn() []string
+}
+
+// RestartPolicy controls how the runtime restarts generators whose Run
+// returns an error or panics. A generator that returns nil is considered
+// cleanly shut down and is never restarted.
+type RestartPolicy struct {
+	// MaxRetries is the number of consecutive failed runs tolerated
+	// before the runtime gives up on a generator. Zero means retry
+	// forever.
+	MaxRetries int
+	// InitialBackoff is the delay before the first restart; it doubles
+	// after every consecutive failure.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the restart delay.
+	MaxBackoff time.Duration
+}
+
+// DefaultRestartPolicy retries forever, backing off from half a second up
+// to thirty seconds.
+func DefaultRestartPolicy() RestartPolicy {
+	return RestartPolicy{
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// Option configures a Runtime.
+type Option func(*Runtime)
+
+// WithRestartPolicy overrides the default restart policy.
+func WithRestartPolicy(policy RestartPolicy) Option {
+	return func(r *Runtime) {
+		r.restart = policy
+	}
+}
+
+// WithMetrics hands every generator the given reconcile metrics through
+// its GeneratorContext.
+func WithMetrics(metrics *Metrics) Option {
+	return func(r *Runtime) {
+		r.metrics = metrics
+	}
+}
+
+// registration holds everything needed to run a generator instance and to
+// re-create it after a failure.
+type registration struct {
+	name    string
+	genType GeneratorType
+	gctx    GeneratorContext
+	gen     Generator
+	// deps names the generators that must be running before this one
+	// starts.
+	deps []string
+	// started flips once the generator's Run loop has been entered for
+	// the first time; it feeds the runtime's readiness.
+	started bool
+	// running is closed when the Run loop is entered, releasing
+	// generators that depend on this one.
+	running chan struct{}
+}
+
+// Runtime owns the set of registered generators and their lifecycles.
+type Runtime struct {
+	store   state.Store
+	logger  *slog.Logger
+	restart RestartPolicy
+	metrics *Metrics
+
+	mu         sync.Mutex
+	generators map[string]*registration
+	failed     map[string]error
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// New returns a runtime whose generators share the given store.
+func New(store state.Store, opts ...Option) *Runtime {
+	r := &Runtime{
+		store:      store,
+		logger:     logging.NewLogger("runtime"),
+		restart:    DefaultRestartPolicy(),
+		generators: make(map[string]*registration),
+		failed:     make(map[string]error),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// RegisterGenerator constructs a generator from genType and adds it to the
+// runtime. The generator's store access is read-write on its managed
+// kinds, read-only on its watched kinds, and owned by the generator's
+// name.
+func (r *Runtime) RegisterGenerator(genType GeneratorType) error {
+	return r.registerGenerator(genType.Name(), genType, false)
+}
+
+// RegisterGeneratorTolerant registers genType like RegisterGenerator but
+// degrades gracefully when the generator's New fails: the failure is
+// recorded, published as a failed GeneratorStatus resource, and nil is
+// returned so callers can keep registering the remaining generators.
+// Configuration errors — duplicate names, overlapping managed kinds,
+// dependency cycles — still fail hard; those are caller bugs, not faults
+// of the generator being registered.
+func (r *Runtime) RegisterGeneratorTolerant(genType GeneratorType) error {
+	return r.registerGenerator(genType.Name(), genType, true)
+}
+
+// RegisterGeneratorInstance registers one instance of genType under the
+// given id. The instance is keyed and owns its resources as "<name>/<id>",
+// so several instances of the same type can run side by side (for example
+// two interface generators scoped to different namespaces).
+func (r *Runtime) RegisterGeneratorInstance(id string, genType GeneratorType) error {
+	if id == "" {
+		return fmt.Errorf("generator instance id must not be empty")
+	}
+	return r.registerGenerator(genType.Name()+"/"+id, genType, false)
+}
+
+func (r *Runtime) registerGenerator(name string, genType GeneratorType, tolerant bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.generators[name]; ok {
+		return fmt.Errorf("generator %q is already registered", name)
+	}
+	// Two different generator types fighting over the same kind is a
+	// configuration bug: both would try to own the same resources and
+	// the loser would see nothing but AlreadyExists and Ownership
+	// errors. Instances of the same type share their kinds by design.
+	for _, kind := range genType.ManagedKinds() {
+		for _, reg := range r.generators {
+			if reg.genType.Name() == genType.Name() {
+				continue
+			}
+			for _, managed := range reg.genType.ManagedKinds() {
+				if managed == kind {
+					return fmt.Errorf("generator %q manages kind %s, which %q already manages",
+						name, kind, reg.name)
+				}
+			}
+		}
+	}
+	store := state.Chain(r.store).
+		Restrict(genType.ManagedKinds(), genType.WatchedKinds()).
+		Owned(name).
+		Build()
+	gctx := GeneratorContext{
+		Name:    name,
+		Store:   store,
+		Logger:  logging.NewLogger(name),
+		Metrics: r.metrics,
+	}
+	var deps []string
+	if dt, ok := genType.(DependentGeneratorType); ok {
+		deps = dt.DependsOn()
+	}
+	if err := r.checkCycle(name, deps); err != nil {
+		return err
+	}
+	gen, err := genType.New(gctx)
+	if err != nil {
+		err = fmt.Errorf("constructing generator %q: %w", name, err)
+		if !tolerant {
+			return err
+		}
+		r.failed[name] = err
+		r.logger.Error("generator failed to construct; continuing without it",
+			"generator", name, "error", err)
+		r.publishGeneratorStatus(&registration{name: name, genType: genType},
+			generatorFailed, 0, err.Error())
+		return nil
+	}
+	reg := &registration{name: name, genType: genType, gctx: gctx, gen: gen, deps: deps}
+	r.generators[name] = reg
+	r.publishGeneratorStatus(reg, generatorRegistered, 0, "")
+	return nil
+}
+
+// FailedGenerators returns the generators that could not be constructed
+// under tolerant registration, keyed by name.
+func (r *Runtime) FailedGenerators() map[string]error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	failed := make(map[string]error, len(r.failed))
+	for name, err := range r.failed {
+		failed[name] = err
+	}
+	return failed
+}
+
+// ManagersOf returns the names of the registered generators that manage
+// kind, sorted. Several names only occur for instances of the same
+// generator type; distinct types cannot overlap.
+func (r *Runtime) ManagersOf(kind string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var names []string
+	for _, reg := range r.generators {
+		for _, managed := range reg.genType.ManagedKinds() {
+			if managed == kind {
+				names = append(names, reg.name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkCycle rejects a registration whose dependencies would close a cycle
+// through the generators registered so far. Called with r.mu held.
+func (r *Runtime) checkCycle(name string, deps []string) error {
+	// Walk the dependency graph from the new generator; reaching it
+	// again means the registration closes a cycle.
+	var visit func(from string, path []string) error
+	visit = func(from string, path []string) error {
+		var next []string
+		if from == name {
+			next = deps
+		} else if reg, ok := r.generators[from]; ok {
+			next = reg.deps
+		}
+		for _, dep := range next {
+			if dep == name {
+				return fmt.Errorf("generator %q introduces a dependency cycle: %s",
+					name, strings.Join(append(path, dep), " -> "))
+			}
+			if err := visit(dep, append(path, dep)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit(name, []string{name})
+}
+
+// Start launches every registered generator, honoring declared
+// dependencies: a generator with a DependsOn list is held back until each
+// named generator has entered its Run loop. It returns immediately; use
+// Stop to shut the generators down and wait for them.
+func (r *Runtime) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, reg := range r.generators {
+		for _, dep := range reg.deps {
+			if _, ok := r.generators[dep]; !ok {
+				return fmt.Errorf("generator %q depends on unregistered generator %q", reg.name, dep)
+			}
+		}
+		reg.running = make(chan struct{})
+	}
+
+	ctx, r.cancel = context.WithCancel(ctx)
+	for _, reg := range r.generators {
+		reg := reg
+		var waits []chan struct{}
+		for _, dep := range reg.deps {
+			waits = append(waits, r.generators[dep].running)
+		}
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			for _, ch := range waits {
+				select {
+				case <-ch:
+				case <-ctx.Done():
+					return
+				}
+			}
+			r.supervise(ctx, reg)
+		}()
+	}
+	return nil
+}
+
+// Ready reports whether every registered generator has entered its Run
+// loop at least once. It is the runtime's readiness signal; health
+// endpoints expose it via HealthHandler.
+func (r *Runtime) Ready() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, reg := range r.generators {
+		if !reg.started {
+			return false
+		}
+	}
+	return true
+}
+
+// supervise runs a generator, restarting it with exponential backoff when
+// it fails. A failed generator is re-created through its GeneratorType so
+// it starts from fresh state.
+func (r *Runtime) supervise(ctx context.Context, reg *registration) {
+	gen := reg.gen
+	backoff := r.restart.InitialBackoff
+	failures := 0
+	r.mu.Lock()
+	reg.started = true
+	r.mu.Unlock()
+	close(reg.running)
+	for {
+		r.publishGeneratorStatus(reg, generatorRunning, failures, "")
+		err := runGenerator(ctx, gen)
+		if err == nil {
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		failures++
+		r.logger.Error("generator failed", "generator", reg.name,
+			"error", err, "failures", failures)
+		r.publishGeneratorStatus(reg, generatorFailed, failures, err.Error())
+		if r.restart.MaxRetries > 0 && failures > r.restart.MaxRetries {
+			r.logger.Error("giving up on generator", "generator", reg.name)
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		if backoff *= 2; backoff > r.restart.MaxBackoff {
+			backoff = r.restart.MaxBackoff
+		}
+		next, err := reg.genType.New(reg.gctx)
+		if err != nil {
+			r.logger.Error("re-creating generator", "generator", reg.name, "error", err)
+			continue
+		}
+		gen = next
+		r.mu.Lock()
+		reg.gen = next
+		r.mu.Unlock()
+	}
+}
+
+// runGenerator invokes gen.Run, converting a panic into an error so the
+// supervisor can treat both the same way.
+func runGenerator(ctx context.Context, gen Generator) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("generator panicked: %v", v)
+		}
+	}()
+	return gen.Run(ctx)
+}
+
+// Stop cancels all generators, waits for them to return, and then runs
+// the Shutdown hook of every generator that has one. Hooks share the given
+// context; a hook that outlives the context's deadline is abandoned.
+func (r *Runtime) Stop(ctx context.Context) {
+	r.mu.Lock()
+	cancel := r.cancel
+	r.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	r.wg.Wait()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, reg := range r.generators {
+		sg, ok := reg.gen.(ShutdownGenerator)
+		if !ok {
+			continue
+		}
+		done := make(chan error, 1)
+		go func() { done <- sg.Shutdown(ctx) }()
+		select {
+		case err := <-done:
+			if err != nil {
+				r.logger.Error("generator shutdown failed", "generator", reg.name, "error", err)
+			}
+		case <-ctx.Done():
+			r.logger.Error("generator shutdown cut off", "generator", reg.name, "error", ctx.Err())
+		}
+	}
+}
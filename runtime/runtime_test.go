@@ -0,0 +1,724 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/state"
+)
+
+// mockGeneratorType is a configurable generator type for runtime tests.
+type mockGeneratorType struct {
+	name     string
+	kinds    []string
+	watched  []string
+	deps     []string
+	run      func(ctx context.Context, store state.Store) error
+	shutdown func(ctx context.Context) error
+	newErr   error
+}
+
+func (t *mockGeneratorType) Name() string           { return t.name }
+func (t *mockGeneratorType) ManagedKinds() []string { return t.kinds }
+func (t *mockGeneratorType) WatchedKinds() []string { return t.watched }
+func (t *mockGeneratorType) DependsOn() []string    { return t.deps }
+
+func (t *mockGeneratorType) New(gctx GeneratorContext) (Generator, error) {
+	if t.newErr != nil {
+		return nil, t.newErr
+	}
+	gen := &mockGenerator{store: gctx.Store, run: t.run}
+	if t.shutdown != nil {
+		return &mockShutdownGenerator{mockGenerator: gen, shutdown: t.shutdown}, nil
+	}
+	return gen, nil
+}
+
+type mockGenerator struct {
+	store state.Store
+	run   func(ctx context.Context, store state.Store) error
+}
+
+func (g *mockGenerator) Run(ctx context.Context) error {
+	if g.run != nil {
+		return g.run(ctx, g.store)
+	}
+	<-ctx.Done()
+	return nil
+}
+
+type mockShutdownGenerator struct {
+	*mockGenerator
+	shutdown func(ctx context.Context) error
+}
+
+func (g *mockShutdownGenerator) Shutdown(ctx context.Context) error {
+	return g.shutdown(ctx)
+}
+
+func testInterface(name string) *pb.Resource {
+	return &pb.Resource{
+		Metadata: &pb.ResourceMetadata{Name: name, Kind: api.KindNetworkInterface},
+		Spec: &pb.Resource_NetworkInterface{NetworkInterface: &pb.NetworkInterface{
+			InterfaceName: name,
+		}},
+	}
+}
+
+func TestRuntimeRunsGenerators(t *testing.T) {
+	store := state.NewMemoryStore()
+	rt := New(store)
+
+	created := make(chan error, 1)
+	err := rt.RegisterGenerator(&mockGeneratorType{
+		name:  "mock",
+		kinds: []string{api.KindNetworkInterface},
+		run: func(ctx context.Context, st state.Store) error {
+			created <- st.Create(ctx, testInterface("eth0"))
+			<-ctx.Done()
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator: %v", err)
+	}
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer rt.Stop(context.Background())
+
+	select {
+	case err := <-created:
+		if err != nil {
+			t.Fatalf("generator Create: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("generator never ran")
+	}
+
+	res, err := store.Get(context.Background(), api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if owner := res.GetMetadata().GetOwner(); owner != "mock" {
+		t.Errorf("owner = %q, want mock", owner)
+	}
+}
+
+func TestRuntimeGeneratorInstances(t *testing.T) {
+	store := state.NewMemoryStore()
+	rt := New(store)
+
+	created := make(chan error, 2)
+	newInstanceType := func(iface string) *mockGeneratorType {
+		return &mockGeneratorType{
+			name:  "mock",
+			kinds: []string{api.KindNetworkInterface},
+			run: func(ctx context.Context, st state.Store) error {
+				created <- st.Create(ctx, testInterface(iface))
+				<-ctx.Done()
+				return nil
+			},
+		}
+	}
+	if err := rt.RegisterGeneratorInstance("a", newInstanceType("eth0")); err != nil {
+		t.Fatalf("RegisterGeneratorInstance a: %v", err)
+	}
+	if err := rt.RegisterGeneratorInstance("b", newInstanceType("eth1")); err != nil {
+		t.Fatalf("RegisterGeneratorInstance b: %v", err)
+	}
+	if err := rt.RegisterGeneratorInstance("a", newInstanceType("eth2")); err == nil {
+		t.Error("duplicate instance id accepted, want error")
+	}
+	if err := rt.RegisterGeneratorInstance("", newInstanceType("eth3")); err == nil {
+		t.Error("empty instance id accepted, want error")
+	}
+
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer rt.Stop(context.Background())
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-created:
+			if err != nil {
+				t.Fatalf("instance Create: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("instance never ran")
+		}
+	}
+
+	// Each instance owns the resources it created under its own scope.
+	for iface, owner := range map[string]string{"eth0": "mock/a", "eth1": "mock/b"} {
+		res, err := store.Get(context.Background(), api.KindNetworkInterface, "", iface)
+		if err != nil {
+			t.Fatalf("Get %s: %v", iface, err)
+		}
+		if got := res.GetMetadata().GetOwner(); got != owner {
+			t.Errorf("owner of %s = %q, want %q", iface, got, owner)
+		}
+	}
+}
+
+func TestRuntimeDuplicateRegistration(t *testing.T) {
+	rt := New(state.NewMemoryStore())
+	gt := &mockGeneratorType{name: "mock", kinds: []string{api.KindNetworkInterface}}
+	if err := rt.RegisterGenerator(gt); err != nil {
+		t.Fatalf("RegisterGenerator: %v", err)
+	}
+	if err := rt.RegisterGenerator(gt); err == nil {
+		t.Error("duplicate RegisterGenerator succeeded, want error")
+	}
+}
+
+func TestRuntimeRestartsFailedGenerator(t *testing.T) {
+	rt := New(state.NewMemoryStore(), WithRestartPolicy(RestartPolicy{
+		MaxRetries:     5,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     4 * time.Millisecond,
+	}))
+
+	var runs atomic.Int32
+	done := make(chan struct{})
+	err := rt.RegisterGenerator(&mockGeneratorType{
+		name:  "flaky",
+		kinds: []string{api.KindNetworkInterface},
+		run: func(ctx context.Context, st state.Store) error {
+			switch n := runs.Add(1); n {
+			case 1:
+				return fmt.Errorf("failure %d", n)
+			case 2:
+				panic("failure 2")
+			}
+			close(done)
+			<-ctx.Done()
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator: %v", err)
+	}
+
+	start := time.Now()
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer rt.Stop(context.Background())
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("generator was not restarted to a successful run")
+	}
+	if got := runs.Load(); got != 3 {
+		t.Errorf("generator ran %d times, want 3", got)
+	}
+	// Two restarts with 1ms then 2ms backoff must take at least 3ms.
+	if elapsed := time.Since(start); elapsed < 3*time.Millisecond {
+		t.Errorf("restarts took %v, want at least 3ms of backoff", elapsed)
+	}
+}
+
+func TestRuntimeDoesNotRestartCleanShutdown(t *testing.T) {
+	rt := New(state.NewMemoryStore(), WithRestartPolicy(RestartPolicy{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	}))
+
+	var runs atomic.Int32
+	err := rt.RegisterGenerator(&mockGeneratorType{
+		name:  "oneshot",
+		kinds: []string{api.KindNetworkInterface},
+		run: func(ctx context.Context, st state.Store) error {
+			runs.Add(1)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator: %v", err)
+	}
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	rt.Stop(context.Background())
+	if got := runs.Load(); got != 1 {
+		t.Errorf("generator ran %d times after clean shutdown, want 1", got)
+	}
+}
+
+func TestRuntimeCallsShutdownHook(t *testing.T) {
+	rt := New(state.NewMemoryStore())
+
+	shutdownCalled := make(chan struct{})
+	err := rt.RegisterGenerator(&mockGeneratorType{
+		name:  "cleanup",
+		kinds: []string{api.KindNetworkInterface},
+		shutdown: func(ctx context.Context) error {
+			close(shutdownCalled)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator: %v", err)
+	}
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	rt.Stop(context.Background())
+
+	select {
+	case <-shutdownCalled:
+	default:
+		t.Error("Shutdown hook was not called during Stop")
+	}
+}
+
+func TestRuntimeShutdownHonorsDeadline(t *testing.T) {
+	rt := New(state.NewMemoryStore())
+
+	err := rt.RegisterGenerator(&mockGeneratorType{
+		name:  "slow-cleanup",
+		kinds: []string{api.KindNetworkInterface},
+		shutdown: func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(time.Hour)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator: %v", err)
+	}
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		rt.Stop(stopCtx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return once the stop context expired")
+	}
+}
+
+func TestRuntimeRestrictsGeneratorStore(t *testing.T) {
+	store := state.NewMemoryStore()
+	rt := New(store)
+
+	// A generator managing only DHCPClient must not be able to create
+	// NetworkInterface resources.
+	result := make(chan error, 1)
+	err := rt.RegisterGenerator(&mockGeneratorType{
+		name:  "dhcp",
+		kinds: []string{api.KindDHCPClient},
+		run: func(ctx context.Context, st state.Store) error {
+			result <- st.Create(ctx, testInterface("eth0"))
+			<-ctx.Done()
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator: %v", err)
+	}
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer rt.Stop(context.Background())
+
+	select {
+	case err := <-result:
+		if _, ok := err.(*state.TypeRestrictedError); !ok {
+			t.Errorf("dhcp store Create returned %v, want *state.TypeRestrictedError", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("generator never ran")
+	}
+}
+
+func TestRuntimeWatchedKindsAreReadOnly(t *testing.T) {
+	store := state.NewMemoryStore()
+	if err := store.Create(context.Background(), testInterface("eth0")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	rt := New(store)
+
+	// A generator watching NetworkInterface can read it but not write it.
+	type outcome struct {
+		getErr    error
+		listErr   error
+		watchErr  error
+		createErr error
+	}
+	result := make(chan outcome, 1)
+	err := rt.RegisterGenerator(&mockGeneratorType{
+		name:    "dhcp",
+		kinds:   []string{api.KindDHCPClient},
+		watched: []string{api.KindNetworkInterface},
+		run: func(ctx context.Context, st state.Store) error {
+			var out outcome
+			_, out.getErr = st.Get(ctx, api.KindNetworkInterface, "", "eth0")
+			_, out.listErr = st.List(ctx, api.KindNetworkInterface, "")
+			_, out.watchErr = st.Watch(ctx, api.KindNetworkInterface, "")
+			out.createErr = st.Create(ctx, testInterface("eth1"))
+			result <- out
+			<-ctx.Done()
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator: %v", err)
+	}
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer rt.Stop(context.Background())
+
+	select {
+	case out := <-result:
+		if out.getErr != nil || out.listErr != nil || out.watchErr != nil {
+			t.Errorf("reads on watched kind failed: get=%v list=%v watch=%v",
+				out.getErr, out.listErr, out.watchErr)
+		}
+		if _, ok := out.createErr.(*state.TypeRestrictedError); !ok {
+			t.Errorf("Create on watched kind returned %v, want *state.TypeRestrictedError", out.createErr)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("generator never ran")
+	}
+}
+
+func TestRuntimeReadiness(t *testing.T) {
+	rt := New(state.NewMemoryStore())
+	err := rt.RegisterGenerator(&mockGeneratorType{
+		name:  "mock",
+		kinds: []string{api.KindNetworkInterface},
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator: %v", err)
+	}
+
+	handler := rt.HealthHandler()
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		return rec.Code
+	}
+
+	if code := get("/healthz"); code != 200 {
+		t.Errorf("healthz before start = %d, want 200", code)
+	}
+	if code := get("/readyz"); code != 503 {
+		t.Errorf("readyz before start = %d, want 503", code)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := rt.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer rt.Stop(context.Background())
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !rt.Ready() {
+		if time.Now().After(deadline) {
+			t.Fatal("runtime never became ready")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if code := get("/readyz"); code != 200 {
+		t.Errorf("readyz after start = %d, want 200", code)
+	}
+}
+
+func TestRuntimeStartHonorsDependencies(t *testing.T) {
+	rt := New(state.NewMemoryStore())
+
+	var mu sync.Mutex
+	var order []string
+	started := make(chan struct{}, 2)
+	record := func(name string) func(ctx context.Context, store state.Store) error {
+		return func(ctx context.Context, store state.Store) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			started <- struct{}{}
+			<-ctx.Done()
+			return nil
+		}
+	}
+
+	// Register the dependent first so map iteration order cannot save us.
+	err := rt.RegisterGenerator(&mockGeneratorType{
+		name:  "dhcp",
+		kinds: []string{api.KindDHCPClient},
+		deps:  []string{"interfaces"},
+		run:   record("dhcp"),
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator dhcp: %v", err)
+	}
+	err = rt.RegisterGenerator(&mockGeneratorType{
+		name:  "interfaces",
+		kinds: []string{api.KindNetworkInterface},
+		run:   record("interfaces"),
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator interfaces: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := rt.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer rt.Stop(context.Background())
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(2 * time.Second):
+			t.Fatal("generators did not start")
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if order[0] != "interfaces" || order[1] != "dhcp" {
+		t.Errorf("start order = %v, want [interfaces dhcp]", order)
+	}
+}
+
+func TestRuntimeRejectsDependencyCycle(t *testing.T) {
+	rt := New(state.NewMemoryStore())
+
+	err := rt.RegisterGenerator(&mockGeneratorType{
+		name:  "a",
+		kinds: []string{api.KindNetworkInterface},
+		deps:  []string{"b"},
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator a: %v", err)
+	}
+	err = rt.RegisterGenerator(&mockGeneratorType{
+		name:  "b",
+		kinds: []string{api.KindRoute},
+		deps:  []string{"a"},
+	})
+	if err == nil {
+		t.Fatal("registering a dependency cycle succeeded, want error")
+	}
+}
+
+func TestRuntimeStartRejectsUnknownDependency(t *testing.T) {
+	rt := New(state.NewMemoryStore())
+
+	err := rt.RegisterGenerator(&mockGeneratorType{
+		name:  "dhcp",
+		kinds: []string{api.KindDHCPClient},
+		deps:  []string{"missing"},
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator: %v", err)
+	}
+	if err := rt.Start(context.Background()); err == nil {
+		t.Fatal("Start with unknown dependency succeeded, want error")
+	}
+}
+
+func getGeneratorStatus(t *testing.T, store state.Store, name string) *pb.GeneratorStatus {
+	t.Helper()
+	res, err := store.Get(context.Background(), api.KindGeneratorStatus, "", name)
+	if err != nil {
+		t.Fatalf("Get generator status %s: %v", name, err)
+	}
+	return res.GetGeneratorStatus()
+}
+
+func TestRuntimePublishesGeneratorStatus(t *testing.T) {
+	store := state.NewMemoryStore()
+	rt := New(store)
+
+	err := rt.RegisterGenerator(&mockGeneratorType{
+		name:  "mock",
+		kinds: []string{api.KindNetworkInterface},
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator: %v", err)
+	}
+
+	status := getGeneratorStatus(t, store, "mock")
+	if status.GetState() != "registered" {
+		t.Errorf("state after registration = %q, want registered", status.GetState())
+	}
+	if kinds := status.GetManagedKinds(); len(kinds) != 1 || kinds[0] != api.KindNetworkInterface {
+		t.Errorf("managed kinds = %v, want [%s]", kinds, api.KindNetworkInterface)
+	}
+
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer rt.Stop(context.Background())
+
+	deadline := time.After(time.Second)
+	for {
+		status = getGeneratorStatus(t, store, "mock")
+		if status.GetState() == "running" {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("state = %q, never became running", status.GetState())
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if status.GetStartedAt() == nil {
+		t.Error("running status has no started_at")
+	}
+}
+
+func TestRuntimeGeneratorStatusTracksFailures(t *testing.T) {
+	store := state.NewMemoryStore()
+	rt := New(store, WithRestartPolicy(RestartPolicy{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	}))
+
+	var runs atomic.Int32
+	err := rt.RegisterGenerator(&mockGeneratorType{
+		name:  "flaky",
+		kinds: []string{api.KindNetworkInterface},
+		run: func(ctx context.Context, st state.Store) error {
+			if runs.Add(1) == 1 {
+				return fmt.Errorf("transient failure")
+			}
+			<-ctx.Done()
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterGenerator: %v", err)
+	}
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer rt.Stop(context.Background())
+
+	// The first run fails; the restarted run publishes a status carrying
+	// the failure count.
+	deadline := time.After(time.Second)
+	for {
+		status := getGeneratorStatus(t, store, "flaky")
+		if status.GetState() == "running" && status.GetRestarts() == 1 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("status = %+v, want running with 1 restart", status)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestRegisterGeneratorRejectsOverlappingKinds(t *testing.T) {
+	rt := New(state.NewMemoryStore())
+	if err := rt.RegisterGenerator(&mockGeneratorType{name: "first", kinds: []string{api.KindRoute}}); err != nil {
+		t.Fatalf("RegisterGenerator first: %v", err)
+	}
+	err := rt.RegisterGenerator(&mockGeneratorType{name: "second", kinds: []string{api.KindRoute}})
+	if err == nil {
+		t.Fatal("registering a second type managing the same kind succeeded")
+	}
+	if !strings.Contains(err.Error(), api.KindRoute) || !strings.Contains(err.Error(), "first") {
+		t.Errorf("overlap error %q does not name the kind and the prior generator", err)
+	}
+}
+
+func TestRegisterGeneratorInstancesShareKinds(t *testing.T) {
+	rt := New(state.NewMemoryStore())
+	genType := &mockGeneratorType{name: "per-ns", kinds: []string{api.KindRoute}}
+	if err := rt.RegisterGeneratorInstance("a", genType); err != nil {
+		t.Fatalf("RegisterGeneratorInstance a: %v", err)
+	}
+	if err := rt.RegisterGeneratorInstance("b", genType); err != nil {
+		t.Errorf("RegisterGeneratorInstance b: %v", err)
+	}
+	got := rt.ManagersOf(api.KindRoute)
+	want := []string{"per-ns/a", "per-ns/b"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ManagersOf = %v, want %v", got, want)
+	}
+}
+
+func TestManagersOfUnmanagedKind(t *testing.T) {
+	rt := New(state.NewMemoryStore())
+	if got := rt.ManagersOf(api.KindRoute); len(got) != 0 {
+		t.Errorf("ManagersOf on empty runtime = %v, want none", got)
+	}
+}
+
+func TestRegisterGeneratorTolerantContinues(t *testing.T) {
+	store := state.NewMemoryStore()
+	rt := New(store)
+
+	broken := &mockGeneratorType{name: "broken", kinds: []string{api.KindRoute},
+		newErr: fmt.Errorf("no backend")}
+	if err := rt.RegisterGeneratorTolerant(broken); err != nil {
+		t.Fatalf("RegisterGeneratorTolerant: %v", err)
+	}
+	healthy := &mockGeneratorType{name: "healthy", kinds: []string{api.KindNetworkInterface}}
+	if err := rt.RegisterGeneratorTolerant(healthy); err != nil {
+		t.Fatalf("RegisterGeneratorTolerant healthy: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := rt.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer rt.Stop(context.Background())
+	deadline := time.Now().Add(2 * time.Second)
+	for !rt.Ready() {
+		if time.Now().After(deadline) {
+			t.Fatal("runtime never became ready")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	failed := rt.FailedGenerators()
+	if err, ok := failed["broken"]; !ok || !strings.Contains(err.Error(), "no backend") {
+		t.Errorf("FailedGenerators = %v, want broken with its New error", failed)
+	}
+	res, err := store.Get(ctx, api.KindGeneratorStatus, state.DefaultNamespace, "broken")
+	if err != nil {
+		t.Fatalf("Get broken status: %v", err)
+	}
+	if got := res.GetGeneratorStatus().GetState(); got != generatorFailed {
+		t.Errorf("broken generator state = %q, want %q", got, generatorFailed)
+	}
+}
+
+func TestRegisterGeneratorStillFailsHard(t *testing.T) {
+	rt := New(state.NewMemoryStore())
+	broken := &mockGeneratorType{name: "broken", kinds: []string{api.KindRoute},
+		newErr: fmt.Errorf("no backend")}
+	if err := rt.RegisterGenerator(broken); err == nil {
+		t.Error("RegisterGenerator swallowed a New failure")
+	}
+	if len(rt.FailedGenerators()) != 0 {
+		t.Error("strict registration recorded a tolerated failure")
+	}
+}
@@ -0,0 +1,58 @@
+package runtime
+
+import (
+	"context"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/state"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Lifecycle states reported in GeneratorStatus resources.
+const (
+	generatorRegistered = "registered"
+	generatorRunning    = "running"
+	generatorFailed     = "failed"
+)
+
+// publishGeneratorStatus records the runtime's view of one generator as a
+// GeneratorStatus resource, so generator health is observable through the
+// store like any other resource. Publish failures are logged, never
+// fatal: status reporting must not take a generator down with it.
+func (r *Runtime) publishGeneratorStatus(reg *registration, phase string, restarts int, lastError string) {
+	ctx := context.Background()
+	status := &pb.GeneratorStatus{
+		Generator:    reg.name,
+		ManagedKinds: reg.genType.ManagedKinds(),
+		State:        phase,
+		Restarts:     uint32(restarts),
+		LastError:    lastError,
+	}
+	res := &pb.Resource{
+		Metadata: &pb.ResourceMetadata{Name: reg.name, Kind: api.KindGeneratorStatus},
+		Spec:     &pb.Resource_GeneratorStatus{GeneratorStatus: status},
+	}
+
+	existing, err := r.store.Get(ctx, api.KindGeneratorStatus, state.DefaultNamespace, reg.name)
+	if err != nil {
+		if status.GetState() == generatorRunning {
+			status.StartedAt = timestamppb.Now()
+		}
+		if err := r.store.Create(ctx, res); err != nil {
+			r.logger.Error("publishing generator status", "generator", reg.name, "error", err)
+		}
+		return
+	}
+	// A fresh run stamps a new start time; failures keep the one from
+	// the run that failed.
+	if phase == generatorRunning {
+		status.StartedAt = timestamppb.Now()
+	} else {
+		status.StartedAt = existing.GetGeneratorStatus().GetStartedAt()
+	}
+	res.Metadata = existing.GetMetadata()
+	if err := r.store.Update(ctx, res); err != nil {
+		r.logger.Error("publishing generator status", "generator", reg.name, "error", err)
+	}
+}
@@ -0,0 +1,88 @@
+// Package server exposes the resource store over gRPC so external tools
+// such as microrunctl can inspect and mutate state without linking the
+// store directly.
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/state"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StoreService implements pb.StoreServiceServer on top of a state.Store.
+type StoreService struct {
+	pb.UnimplementedStoreServiceServer
+	store state.Store
+}
+
+// NewStoreService returns a gRPC store service backed by store.
+func NewStoreService(store state.Store) *StoreService {
+	return &StoreService{store: store}
+}
+
+// Create implements pb.StoreServiceServer.
+func (s *StoreService) Create(ctx context.Context, req *pb.CreateRequest) (*pb.Resource, error) {
+	if err := s.store.Create(ctx, req.GetResource()); err != nil {
+		return nil, grpcError(err)
+	}
+	md := req.GetResource().GetMetadata()
+	return s.get(ctx, md.GetKind(), md.GetNamespace(), md.GetName())
+}
+
+// Get implements pb.StoreServiceServer.
+func (s *StoreService) Get(ctx context.Context, req *pb.GetRequest) (*pb.Resource, error) {
+	return s.get(ctx, req.GetKind(), req.GetNamespace(), req.GetName())
+}
+
+// Update implements pb.StoreServiceServer.
+func (s *StoreService) Update(ctx context.Context, req *pb.UpdateRequest) (*pb.Resource, error) {
+	if err := s.store.Update(ctx, req.GetResource()); err != nil {
+		return nil, grpcError(err)
+	}
+	md := req.GetResource().GetMetadata()
+	return s.get(ctx, md.GetKind(), md.GetNamespace(), md.GetName())
+}
+
+// Delete implements pb.StoreServiceServer.
+func (s *StoreService) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResponse, error) {
+	if err := s.store.Delete(ctx, req.GetKind(), req.GetNamespace(), req.GetName()); err != nil {
+		return nil, grpcError(err)
+	}
+	return &pb.DeleteResponse{}, nil
+}
+
+// List implements pb.StoreServiceServer.
+func (s *StoreService) List(ctx context.Context, req *pb.ListRequest) (*pb.ListResponse, error) {
+	resources, err := s.store.List(ctx, req.GetKind(), req.GetNamespace())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &pb.ListResponse{Resources: resources}, nil
+}
+
+func (s *StoreService) get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	res, err := s.store.Get(ctx, kind, namespace, name)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return res, nil
+}
+
+// grpcError maps the store's typed errors onto gRPC status codes so
+// clients can distinguish them without parsing messages.
+func grpcError(err error) error {
+	var conflict *state.ConflictError
+	switch {
+	case state.IsNotFound(err):
+		return status.Error(codes.NotFound, err.Error())
+	case state.IsAlreadyExists(err):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.As(err, &conflict):
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,23 @@
+package state
+
+import (
+	"context"
+
+	"github.com/microrun/microrun/api/pb"
+)
+
+// AdmissionOp identifies the operation an admission hook observes.
+type AdmissionOp string
+
+const (
+	AdmissionCreate AdmissionOp = "create"
+	AdmissionUpdate AdmissionOp = "update"
+	AdmissionDelete AdmissionOp = "delete"
+)
+
+// AdmissionFunc is a pluggable admission hook. For creates old is nil;
+// for deletes new is nil. The hook may mutate new in place — the store
+// persists what the hooks leave behind — and returning an error rejects
+// the operation outright. Hooks run before the store's write lock is
+// taken and must not call back into the store.
+type AdmissionFunc func(ctx context.Context, op AdmissionOp, old, new *pb.Resource) error
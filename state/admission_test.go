@@ -0,0 +1,125 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+)
+
+func TestAdmissionRejectsCreate(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	store.RegisterAdmission(api.KindNetworkInterface, func(ctx context.Context, op AdmissionOp, old, new *pb.Resource) error {
+		if op == AdmissionCreate && new.GetNetworkInterface().GetMtu() > 9000 {
+			return fmt.Errorf("mtu above jumbo frame limit")
+		}
+		return nil
+	})
+
+	res := testInterface("eth0", "aa:bb:cc:dd:ee:00")
+	res.GetNetworkInterface().Mtu = 65535
+	if err := store.Create(ctx, res); err == nil {
+		t.Error("rejecting hook did not block creation")
+	}
+	if _, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); !IsNotFound(err) {
+		t.Errorf("rejected resource was stored anyway, Get = %v", err)
+	}
+
+	res.GetNetworkInterface().Mtu = 1500
+	if err := store.Create(ctx, res); err != nil {
+		t.Errorf("Create of an admitted resource: %v", err)
+	}
+}
+
+func TestAdmissionMutationsPersist(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	store.RegisterAdmission(api.KindNetworkInterface, func(ctx context.Context, op AdmissionOp, old, new *pb.Resource) error {
+		if new == nil {
+			return nil
+		}
+		if new.Metadata.Annotations == nil {
+			new.Metadata.Annotations = make(map[string]string)
+		}
+		new.Metadata.Annotations["admitted"] = string(op)
+		return nil
+	})
+
+	caller := testInterface("eth0", "aa:bb:cc:dd:ee:00")
+	if err := store.Create(ctx, caller); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if caller.GetMetadata().GetAnnotations() != nil {
+		t.Error("hook mutation leaked into the caller's resource")
+	}
+	stored, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := stored.GetMetadata().GetAnnotations()["admitted"]; got != "create" {
+		t.Errorf("annotation = %q, want create", got)
+	}
+
+	stored.GetNetworkInterface().Mtu = 9000
+	if err := store.Update(ctx, stored); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	stored, err = store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := stored.GetMetadata().GetAnnotations()["admitted"]; got != "update" {
+		t.Errorf("annotation after update = %q, want update", got)
+	}
+}
+
+func TestAdmissionHooksRunInOrder(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	var order []string
+	for _, name := range []string{"first", "second"} {
+		name := name
+		store.RegisterAdmission(api.KindNetworkInterface, func(ctx context.Context, op AdmissionOp, old, new *pb.Resource) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+	store.RegisterAdmission(api.KindNetworkInterface, func(ctx context.Context, op AdmissionOp, old, new *pb.Resource) error {
+		return fmt.Errorf("no deletes today")
+	})
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err == nil {
+		t.Error("final rejecting hook did not block the create")
+	}
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Errorf("hook order = %v, want [first second]", order)
+	}
+}
+
+func TestAdmissionBlocksDelete(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	store.RegisterAdmission(api.KindNetworkInterface, func(ctx context.Context, op AdmissionOp, old, new *pb.Resource) error {
+		if op == AdmissionDelete {
+			return fmt.Errorf("protected")
+		}
+		return nil
+	})
+
+	if err := store.Delete(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); err == nil {
+		t.Error("rejecting hook did not block the delete")
+	}
+	if _, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); err != nil {
+		t.Errorf("resource vanished despite the rejected delete: %v", err)
+	}
+}
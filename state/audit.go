@@ -0,0 +1,239 @@
+package state
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/microrun/microrun/api/pb"
+)
+
+// defaultAuditSize is the per-kind history length used when no size is
+// given to NewAuditStore.
+const defaultAuditSize = 100
+
+// AuditEntry records a single store mutation.
+type AuditEntry struct {
+	Time   time.Time
+	Action EventType
+	Kind   string
+	Name   string
+	// Generation is the resource generation after the mutation (for a
+	// delete, the generation of the removed resource).
+	Generation int64
+	// Diff is the prototext line diff the mutation produced.
+	Diff string
+}
+
+// AuditStore wraps a Store and keeps a bounded in-memory history of the
+// mutations that went through it, per kind. It is meant for debugging
+// reconcile loops, not as a durable audit trail.
+type AuditStore struct {
+	store Store
+
+	mu      sync.Mutex
+	size    int
+	entries map[string][]AuditEntry
+}
+
+// NewAuditStore wraps store with a mutation history of up to size entries
+// per kind. A non-positive size uses the default of 100.
+func NewAuditStore(store Store, size int) *AuditStore {
+	if size <= 0 {
+		size = defaultAuditSize
+	}
+	return &AuditStore{
+		store:   store,
+		size:    size,
+		entries: make(map[string][]AuditEntry),
+	}
+}
+
+// Audit returns the recorded mutations for a kind, oldest first.
+func (s *AuditStore) Audit(kind string) []AuditEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]AuditEntry, len(s.entries[kind]))
+	copy(out, s.entries[kind])
+	return out
+}
+
+func (s *AuditStore) record(entry AuditEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ring := s.entries[entry.Kind]
+	if len(ring) == s.size {
+		ring = ring[1:]
+	}
+	s.entries[entry.Kind] = append(ring, entry)
+}
+
+func (s *AuditStore) Create(ctx context.Context, res *pb.Resource) error {
+	if err := s.store.Create(ctx, res); err != nil {
+		return err
+	}
+	kind := res.GetMetadata().GetKind()
+	namespace := res.GetMetadata().GetNamespace()
+	name := res.GetMetadata().GetName()
+	stored, err := s.store.Get(ctx, kind, namespace, name)
+	if err != nil {
+		stored = res
+	}
+	s.record(AuditEntry{
+		Time:       time.Now(),
+		Action:     EventCreated,
+		Kind:       kind,
+		Name:       name,
+		Generation: stored.GetMetadata().GetGeneration(),
+		Diff:       DiffResources(&pb.Resource{}, stored),
+	})
+	return nil
+}
+
+func (s *AuditStore) Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	return s.store.Get(ctx, kind, namespace, name)
+}
+
+func (s *AuditStore) Exists(ctx context.Context, kind, namespace, name string) (bool, error) {
+	return s.store.Exists(ctx, kind, namespace, name)
+}
+
+func (s *AuditStore) Update(ctx context.Context, res *pb.Resource) error {
+	kind := res.GetMetadata().GetKind()
+	namespace := res.GetMetadata().GetNamespace()
+	name := res.GetMetadata().GetName()
+	old, _ := s.store.Get(ctx, kind, namespace, name)
+	if err := s.store.Update(ctx, res); err != nil {
+		return err
+	}
+	updated, err := s.store.Get(ctx, kind, namespace, name)
+	if err != nil {
+		return nil
+	}
+	// A no-op update leaves the generation alone and is not recorded.
+	if old != nil && old.GetMetadata().GetGeneration() == updated.GetMetadata().GetGeneration() {
+		return nil
+	}
+	s.record(AuditEntry{
+		Time:       time.Now(),
+		Action:     EventUpdated,
+		Kind:       kind,
+		Name:       name,
+		Generation: updated.GetMetadata().GetGeneration(),
+		Diff:       DiffResources(old, updated),
+	})
+	return nil
+}
+
+func (s *AuditStore) Delete(ctx context.Context, kind, namespace, name string, opts ...DeleteOption) error {
+	old, _ := s.store.Get(ctx, kind, namespace, name)
+	if err := s.store.Delete(ctx, kind, namespace, name, opts...); err != nil {
+		return err
+	}
+	s.record(AuditEntry{
+		Time:       time.Now(),
+		Action:     EventDeleted,
+		Kind:       kind,
+		Name:       name,
+		Generation: old.GetMetadata().GetGeneration(),
+		Diff:       DiffResources(old, &pb.Resource{}),
+	})
+	return nil
+}
+
+func (s *AuditStore) List(ctx context.Context, kind, namespace string) ([]*pb.Resource, error) {
+	return s.store.List(ctx, kind, namespace)
+}
+
+func (s *AuditStore) Count(ctx context.Context, kind, namespace string) (int, error) {
+	return s.store.Count(ctx, kind, namespace)
+}
+
+func (s *AuditStore) Kinds(ctx context.Context) ([]string, error) {
+	return s.store.Kinds(ctx)
+}
+
+func (s *AuditStore) ListAll(ctx context.Context) (map[string][]*pb.Resource, error) {
+	return s.store.ListAll(ctx)
+}
+
+func (s *AuditStore) Replace(ctx context.Context, kind string, desired []*pb.Resource) error {
+	return s.Transaction(ctx, func(tx Store) error {
+		return replaceAll(ctx, tx, kind, desired)
+	})
+}
+
+func (s *AuditStore) Close() error {
+	return s.store.Close()
+}
+
+func (s *AuditStore) Patch(ctx context.Context, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error) {
+	return s.store.Patch(ctx, kind, namespace, name, patch, patchType)
+}
+
+func (s *AuditStore) ListPage(ctx context.Context, kind, namespace string, opts PageOptions) (ListResult, error) {
+	return s.store.ListPage(ctx, kind, namespace, opts)
+}
+
+func (s *AuditStore) Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.Watch(ctx, kind, namespace, opts...)
+}
+
+func (s *AuditStore) WatchResource(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchResource(ctx, kind, namespace, name, opts...)
+}
+
+func (s *AuditStore) WatchKinds(ctx context.Context, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchKinds(ctx, kinds, namespace, opts...)
+}
+
+func (s *AuditStore) WatchAll(ctx context.Context, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchAll(ctx, opts...)
+}
+
+// Transaction audits the mutations made inside fn, recording them only if
+// the transaction commits.
+func (s *AuditStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.store.Transaction(ctx, func(tx Store) error {
+		txAudit := NewAuditStore(tx, s.size)
+		if err := fn(txAudit); err != nil {
+			return err
+		}
+		txAudit.mu.Lock()
+		defer txAudit.mu.Unlock()
+		for _, entries := range txAudit.entries {
+			for _, entry := range entries {
+				s.record(entry)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *AuditStore) AddIndex(kind, indexName string, fn func(*pb.Resource) []string) error {
+	return s.store.AddIndex(kind, indexName, fn)
+}
+
+func (s *AuditStore) RegisterAdmission(kind string, fn AdmissionFunc) {
+	s.store.RegisterAdmission(kind, fn)
+}
+
+func (s *AuditStore) BeginBatch() {
+	s.store.BeginBatch()
+}
+
+func (s *AuditStore) CommitBatch() {
+	s.store.CommitBatch()
+}
+
+func (s *AuditStore) GetByIndex(ctx context.Context, kind, indexName, value string) ([]*pb.Resource, error) {
+	return s.store.GetByIndex(ctx, kind, indexName, value)
+}
+
+func (s *AuditStore) SetObservedGeneration(ctx context.Context, kind, namespace, name string, gen int64) error {
+	return s.store.SetObservedGeneration(ctx, kind, namespace, name, gen)
+}
+
+func (s *AuditStore) TransferOwner(ctx context.Context, kind, namespace, name, owner string) error {
+	return s.store.TransferOwner(ctx, kind, namespace, name, owner)
+}
@@ -0,0 +1,81 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+)
+
+func TestAuditStoreRecordsMutations(t *testing.T) {
+	ctx := context.Background()
+	store := NewAuditStore(NewMemoryStore(), 10)
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.GetNetworkInterface().IpAddresses = []string{"192.0.2.1/24"}
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	// A no-op update must not produce an entry.
+	res, _ = store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("no-op Update: %v", err)
+	}
+	if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	entries := store.Audit(api.KindNetworkInterface)
+	if len(entries) != 3 {
+		t.Fatalf("audit has %d entries, want 3", len(entries))
+	}
+	wantActions := []EventType{EventCreated, EventUpdated, EventDeleted}
+	for i, entry := range entries {
+		if entry.Action != wantActions[i] {
+			t.Errorf("entry %d action = %v, want %v", i, entry.Action, wantActions[i])
+		}
+		if entry.Kind != api.KindNetworkInterface || entry.Name != "eth0" {
+			t.Errorf("entry %d identifies %s/%s", i, entry.Kind, entry.Name)
+		}
+		if entry.Time.IsZero() {
+			t.Errorf("entry %d has zero timestamp", i)
+		}
+		if entry.Diff == "" {
+			t.Errorf("entry %d has empty diff", i)
+		}
+	}
+	if entries[1].Generation != 2 {
+		t.Errorf("update entry generation = %d, want 2", entries[1].Generation)
+	}
+	if !strings.Contains(entries[1].Diff, "192.0.2.1/24") {
+		t.Errorf("update diff does not mention the new address:\n%s", entries[1].Diff)
+	}
+}
+
+func TestAuditStoreBoundsHistory(t *testing.T) {
+	ctx := context.Background()
+	store := NewAuditStore(NewMemoryStore(), 5)
+
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("eth%d", i)
+		if err := store.Create(ctx, testInterface(name, "aa:bb:cc:dd:ee:ff")); err != nil {
+			t.Fatalf("Create %s: %v", name, err)
+		}
+	}
+	entries := store.Audit(api.KindNetworkInterface)
+	if len(entries) != 5 {
+		t.Fatalf("audit has %d entries, want 5", len(entries))
+	}
+	// The oldest entries were evicted; the ring holds the last five.
+	if entries[0].Name != "eth3" || entries[4].Name != "eth7" {
+		t.Errorf("ring holds %s..%s, want eth3..eth7", entries[0].Name, entries[4].Name)
+	}
+}
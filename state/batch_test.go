@@ -0,0 +1,105 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/microrun/microrun/api"
+)
+
+func TestBatchHoldsAndCoalescesEvents(t *testing.T) {
+	store := NewMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := store.Watch(ctx, api.KindNetworkInterface, DefaultNamespace)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	store.BeginBatch()
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.GetNetworkInterface().Mtu = 9000
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	select {
+	case ev := <-events:
+		t.Fatalf("watcher saw a %v event while the batch was open", ev.Type)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	store.CommitBatch()
+	var batch []Event
+	for {
+		select {
+		case ev := <-events:
+			if ev.Type == EventBatchEnd {
+				if ev.Resource != nil {
+					t.Errorf("barrier event carries a resource: %v", ev.Resource)
+				}
+				goto done
+			}
+			batch = append(batch, ev)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no barrier after CommitBatch; got %d events", len(batch))
+		}
+	}
+done:
+	if len(batch) != 2 {
+		t.Fatalf("batch delivered %d events, want 2 (one per resource)", len(batch))
+	}
+	byName := make(map[string]Event, len(batch))
+	for _, ev := range batch {
+		byName[ev.Resource.GetMetadata().GetName()] = ev
+	}
+	eth0, ok := byName["eth0"]
+	if !ok {
+		t.Fatal("batch is missing eth0")
+	}
+	if eth0.Type != EventUpdated || eth0.Resource.GetNetworkInterface().GetMtu() != 9000 {
+		t.Errorf("eth0 event = %v mtu %d, want the final updated state with mtu 9000",
+			eth0.Type, eth0.Resource.GetNetworkInterface().GetMtu())
+	}
+	if ev, ok := byName["eth1"]; !ok || ev.Type != EventCreated {
+		t.Errorf("eth1 event = %v, want a single created event", ev.Type)
+	}
+}
+
+func TestBatchBarrierOnlyReachesTouchedWatchers(t *testing.T) {
+	store := NewMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bridges, err := store.Watch(ctx, api.KindBridge, DefaultNamespace)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	store.BeginBatch()
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	store.CommitBatch()
+	// An untouched kind's watcher gets neither the events nor a bare
+	// barrier.
+	select {
+	case ev := <-bridges:
+		t.Fatalf("bridge watcher saw a %v event from an interface batch", ev.Type)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// CommitBatch without an open batch is a no-op.
+	store.CommitBatch()
+}
@@ -0,0 +1,48 @@
+package state
+
+// StoreBuilder composes store decorators in a fixed, documented order so
+// callers do not have to remember which wrapping order is correct.
+// Decorators apply inside-out in the order the methods are called:
+// Chain(base).Restrict(managed, watched).Owned(name) yields an ownership
+// store around a kind-scoped store around base, which is the order the
+// runtime hands stores to generators.
+type StoreBuilder struct {
+	store Store
+}
+
+// Chain starts a decorator chain on base.
+func Chain(base Store) *StoreBuilder {
+	return &StoreBuilder{store: base}
+}
+
+// Restrict scopes the store to the given kinds: read-write access to the
+// managed kinds, read-only access to the watched ones.
+func (b *StoreBuilder) Restrict(managed, watched []string) *StoreBuilder {
+	b.store = NewKindScopedStore(b.store, managed, watched)
+	return b
+}
+
+// Owned stamps created resources with owner and refuses mutations of
+// resources owned by anyone else.
+func (b *StoreBuilder) Owned(owner string) *StoreBuilder {
+	b.store = NewOwnershipStore(b.store, owner)
+	return b
+}
+
+// Quota enforces a maximum resource count per kind on Create.
+func (b *StoreBuilder) Quota(limits map[string]int) *StoreBuilder {
+	b.store = NewQuotaStore(b.store, limits)
+	return b
+}
+
+// ReadOnly refuses every mutation. As it masks all write-side decorators
+// below it, it is normally the last layer added.
+func (b *StoreBuilder) ReadOnly() *StoreBuilder {
+	b.store = NewReadOnlyStore(b.store)
+	return b
+}
+
+// Build returns the composed store.
+func (b *StoreBuilder) Build() Store {
+	return b.store
+}
@@ -0,0 +1,82 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+)
+
+func TestChainComposesRestrictAndOwned(t *testing.T) {
+	ctx := context.Background()
+	base := NewMemoryStore()
+	store := Chain(base).
+		Restrict([]string{api.KindNetworkInterface}, nil).
+		Owned("gen-a").
+		Build()
+
+	// The ownership layer stamps the owner; the restriction layer
+	// rejects foreign kinds.
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := base.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if owner := res.GetMetadata().GetOwner(); owner != "gen-a" {
+		t.Errorf("owner = %q, want gen-a", owner)
+	}
+	if _, err := store.List(ctx, api.KindDHCPClient, ""); err == nil {
+		t.Error("List of restricted kind succeeded, want TypeRestrictedError")
+	} else if _, ok := IsTypeRestricted(err); !ok {
+		t.Errorf("List returned %v, want TypeRestrictedError", err)
+	}
+
+	// Another owner built over the same base cannot mutate gen-a's
+	// resource.
+	other := Chain(base).
+		Restrict([]string{api.KindNetworkInterface}, nil).
+		Owned("gen-b").
+		Build()
+	if err := other.Delete(ctx, api.KindNetworkInterface, "", "eth0"); err == nil {
+		t.Error("foreign Delete succeeded, want OwnershipError")
+	} else if _, ok := IsOwnership(err); !ok {
+		t.Errorf("foreign Delete returned %v, want OwnershipError", err)
+	}
+}
+
+func TestChainReadOnlyMasksWrites(t *testing.T) {
+	ctx := context.Background()
+	base := NewMemoryStore()
+	if err := base.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	store := Chain(base).
+		Restrict([]string{api.KindNetworkInterface}, nil).
+		ReadOnly().
+		Build()
+
+	if _, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0"); err != nil {
+		t.Errorf("Get through read-only chain: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:ff")); err == nil {
+		t.Error("Create through read-only chain succeeded, want ReadOnlyError")
+	}
+}
+
+func TestChainQuotaLayer(t *testing.T) {
+	ctx := context.Background()
+	store := Chain(NewMemoryStore()).
+		Quota(map[string]int{api.KindNetworkInterface: 1}).
+		Owned("gen-a").
+		Build()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:ff")); err == nil {
+		t.Error("Create past quota succeeded, want QuotaExceededError")
+	}
+}
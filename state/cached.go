@@ -0,0 +1,225 @@
+package state
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/microrun/microrun/api/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// cachedStore wraps a Store and serves Get and List for the configured
+// kinds from an in-memory mirror kept current by a watch on the backend.
+// Writes pass through unchanged and reach the mirror the same way any
+// other writer's do: through the watch. Reads of uncached kinds pass
+// through too.
+type cachedStore struct {
+	backend Store
+	cancel  context.CancelFunc
+
+	mu sync.RWMutex
+	// data mirrors the backend's resources for the cached kinds, keyed
+	// like the memory store: kind, then namespace/name. A kind key's
+	// presence marks the kind as cached even while it holds nothing.
+	data map[string]map[string]*pb.Resource
+}
+
+// NewCachedStore returns a store serving reads of the given kinds from a
+// local mirror of backend, cutting per-read cloning and lock traffic on
+// the backend to a single watch. The mirror is seeded synchronously, so
+// reads are served from a warm cache as soon as the constructor returns;
+// writes through any store handle show up in reads once the watch
+// delivers them. If the watch cannot be established, nothing is cached
+// and every call passes through.
+func NewCachedStore(backend Store, kinds []string) Store {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &cachedStore{backend: backend, cancel: cancel}
+
+	// Register the watch before seeding: every mutation after this point
+	// arrives as an event and replays over the seed, so the mirror never
+	// misses a write no matter how the List interleaves with it.
+	events, err := backend.WatchKinds(ctx, kinds, NamespaceAll)
+	if err != nil {
+		return s
+	}
+	s.data = make(map[string]map[string]*pb.Resource, len(kinds))
+	for _, kind := range kinds {
+		s.data[kind] = make(map[string]*pb.Resource)
+		resources, err := backend.List(ctx, kind, NamespaceAll)
+		if err != nil {
+			continue
+		}
+		for _, res := range resources {
+			s.data[kind][nsKey(res.GetMetadata().GetNamespace(), res.GetMetadata().GetName())] = res
+		}
+	}
+	go s.follow(events)
+	return s
+}
+
+// follow applies watch events to the mirror until the channel closes.
+func (s *cachedStore) follow(events <-chan Event) {
+	for ev := range events {
+		meta := ev.Resource.GetMetadata()
+		key := nsKey(meta.GetNamespace(), meta.GetName())
+		s.mu.Lock()
+		switch ev.Type {
+		case EventCreated, EventUpdated:
+			s.data[meta.GetKind()][key] = ev.Resource
+		case EventDeleted:
+			delete(s.data[meta.GetKind()], key)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// cached reports whether kind is served from the mirror.
+func (s *cachedStore) cached(kind string) bool {
+	_, ok := s.data[kind]
+	return ok
+}
+
+func (s *cachedStore) Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	if !s.cached(kind) {
+		return s.backend.Get(ctx, kind, namespace, name)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	res, ok := s.data[kind][nsKey(namespace, name)]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, &NotFoundError{Kind: kind, Name: name}
+	}
+	return proto.Clone(res).(*pb.Resource), nil
+}
+
+// Exists answers from the mirror for cached kinds, like Get.
+func (s *cachedStore) Exists(ctx context.Context, kind, namespace, name string) (bool, error) {
+	if !s.cached(kind) {
+		return s.backend.Exists(ctx, kind, namespace, name)
+	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	s.mu.RLock()
+	_, ok := s.data[kind][nsKey(namespace, name)]
+	s.mu.RUnlock()
+	return ok, nil
+}
+
+func (s *cachedStore) List(ctx context.Context, kind, namespace string) ([]*pb.Resource, error) {
+	if !s.cached(kind) {
+		return s.backend.List(ctx, kind, namespace)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	out := make([]*pb.Resource, 0, len(s.data[kind]))
+	for _, res := range s.data[kind] {
+		if namespace != NamespaceAll && res.GetMetadata().GetNamespace() != namespace {
+			continue
+		}
+		out = append(out, proto.Clone(res).(*pb.Resource))
+	}
+	s.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool {
+		if a, b := out[i].GetMetadata().GetNamespace(), out[j].GetMetadata().GetNamespace(); a != b {
+			return a < b
+		}
+		return out[i].GetMetadata().GetName() < out[j].GetMetadata().GetName()
+	})
+	return out, nil
+}
+
+func (s *cachedStore) Create(ctx context.Context, res *pb.Resource) error {
+	return s.backend.Create(ctx, res)
+}
+
+func (s *cachedStore) Update(ctx context.Context, res *pb.Resource) error {
+	return s.backend.Update(ctx, res)
+}
+
+func (s *cachedStore) Delete(ctx context.Context, kind, namespace, name string, opts ...DeleteOption) error {
+	return s.backend.Delete(ctx, kind, namespace, name, opts...)
+}
+
+func (s *cachedStore) ListPage(ctx context.Context, kind, namespace string, opts PageOptions) (ListResult, error) {
+	return s.backend.ListPage(ctx, kind, namespace, opts)
+}
+
+func (s *cachedStore) Count(ctx context.Context, kind, namespace string) (int, error) {
+	return s.backend.Count(ctx, kind, namespace)
+}
+
+func (s *cachedStore) Kinds(ctx context.Context) ([]string, error) {
+	return s.backend.Kinds(ctx)
+}
+
+func (s *cachedStore) ListAll(ctx context.Context) (map[string][]*pb.Resource, error) {
+	return s.backend.ListAll(ctx)
+}
+
+func (s *cachedStore) Patch(ctx context.Context, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error) {
+	return s.backend.Patch(ctx, kind, namespace, name, patch, patchType)
+}
+
+func (s *cachedStore) Replace(ctx context.Context, kind string, desired []*pb.Resource) error {
+	return s.backend.Replace(ctx, kind, desired)
+}
+
+func (s *cachedStore) Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.backend.Watch(ctx, kind, namespace, opts...)
+}
+
+func (s *cachedStore) WatchResource(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error) {
+	return s.backend.WatchResource(ctx, kind, namespace, name, opts...)
+}
+
+func (s *cachedStore) WatchKinds(ctx context.Context, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.backend.WatchKinds(ctx, kinds, namespace, opts...)
+}
+
+func (s *cachedStore) WatchAll(ctx context.Context, opts ...WatchOption) (<-chan Event, error) {
+	return s.backend.WatchAll(ctx, opts...)
+}
+
+func (s *cachedStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.backend.Transaction(ctx, fn)
+}
+
+func (s *cachedStore) AddIndex(kind, indexName string, fn func(*pb.Resource) []string) error {
+	return s.backend.AddIndex(kind, indexName, fn)
+}
+
+func (s *cachedStore) RegisterAdmission(kind string, fn AdmissionFunc) {
+	s.backend.RegisterAdmission(kind, fn)
+}
+
+func (s *cachedStore) BeginBatch() {
+	s.backend.BeginBatch()
+}
+
+func (s *cachedStore) CommitBatch() {
+	s.backend.CommitBatch()
+}
+
+func (s *cachedStore) GetByIndex(ctx context.Context, kind, indexName, value string) ([]*pb.Resource, error) {
+	return s.backend.GetByIndex(ctx, kind, indexName, value)
+}
+
+func (s *cachedStore) SetObservedGeneration(ctx context.Context, kind, namespace, name string, gen int64) error {
+	return s.backend.SetObservedGeneration(ctx, kind, namespace, name, gen)
+}
+
+func (s *cachedStore) TransferOwner(ctx context.Context, kind, namespace, name, owner string) error {
+	return s.backend.TransferOwner(ctx, kind, namespace, name, owner)
+}
+
+func (s *cachedStore) Close() error {
+	s.cancel()
+	return s.backend.Close()
+}
@@ -0,0 +1,97 @@
+package state
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+)
+
+// countingStore counts the reads forwarded to the wrapped store so tests
+// can tell cache hits from pass-throughs.
+type countingStore struct {
+	Store
+	gets atomic.Int64
+}
+
+func (s *countingStore) Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	s.gets.Add(1)
+	return s.Store.Get(ctx, kind, namespace, name)
+}
+
+func TestCachedStoreServesSnapshotWithoutBackendReads(t *testing.T) {
+	backend := NewMemoryStore()
+	ctx := context.Background()
+	if err := backend.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	counting := &countingStore{Store: backend}
+	cached := NewCachedStore(counting, []string{api.KindNetworkInterface})
+	defer cached.Close()
+	counting.gets.Store(0)
+
+	res, err := cached.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := res.GetNetworkInterface().GetInterfaceName(); got != "eth0" {
+		t.Errorf("cached spec names interface %q, want eth0", got)
+	}
+	if n := counting.gets.Load(); n != 0 {
+		t.Errorf("cached Get forwarded %d reads to the backend, want 0", n)
+	}
+	if _, err := cached.Get(ctx, api.KindBridge, DefaultNamespace, "br0"); !IsNotFound(err) {
+		t.Errorf("uncached kind Get = %v, want NotFoundError from backend", err)
+	}
+	if n := counting.gets.Load(); n != 1 {
+		t.Errorf("uncached Get forwarded %d reads to the backend, want 1", n)
+	}
+}
+
+func TestCachedStoreReflectsWritesAndDeletes(t *testing.T) {
+	backend := NewMemoryStore()
+	ctx := context.Background()
+	cached := NewCachedStore(backend, []string{api.KindNetworkInterface})
+	defer cached.Close()
+
+	if err := cached.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	waitForCached(t, cached, "eth0", true)
+
+	resources, err := cached.List(ctx, api.KindNetworkInterface, DefaultNamespace)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(resources) != 1 {
+		t.Fatalf("List returned %d resources, want 1", len(resources))
+	}
+
+	if err := cached.Delete(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	waitForCached(t, cached, "eth0", false)
+}
+
+// waitForCached polls the cache until the named interface is (or is no
+// longer) served, failing the test when the watch does not propagate in
+// time.
+func waitForCached(t *testing.T, store Store, name string, present bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		_, err := store.Get(context.Background(), api.KindNetworkInterface, DefaultNamespace, name)
+		if present && err == nil {
+			return
+		}
+		if !present && IsNotFound(err) {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("cache never converged on %s present=%v", name, present)
+}
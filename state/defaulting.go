@@ -0,0 +1,169 @@
+package state
+
+import (
+	"context"
+	"sync"
+
+	"github.com/microrun/microrun/api/pb"
+)
+
+// Defaulter fills unset fields of a resource with their defaults. It must
+// leave explicitly-set values alone.
+type Defaulter interface {
+	Default(res *pb.Resource)
+}
+
+// DefaulterFunc adapts a plain function to the Defaulter interface.
+type DefaulterFunc func(res *pb.Resource)
+
+// Default implements Defaulter.
+func (f DefaulterFunc) Default(res *pb.Resource) { f(res) }
+
+// DefaultingStore wraps a Store and applies registered per-kind defaulters
+// to resources before they are created or updated, so callers do not have
+// to repeat defaulting boilerplate.
+type DefaultingStore struct {
+	store Store
+
+	mu         sync.RWMutex
+	defaulters map[string][]Defaulter
+}
+
+// NewDefaultingStore returns a store that runs registered defaulters
+// before handing mutations to store.
+func NewDefaultingStore(store Store) *DefaultingStore {
+	return &DefaultingStore{
+		store:      store,
+		defaulters: make(map[string][]Defaulter),
+	}
+}
+
+// RegisterDefaulter adds a defaulter for the given kind. Defaulters run in
+// registration order.
+func (s *DefaultingStore) RegisterDefaulter(kind string, d Defaulter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.defaulters[kind] = append(s.defaulters[kind], d)
+}
+
+func (s *DefaultingStore) applyDefaults(res *pb.Resource) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, d := range s.defaulters[res.GetMetadata().GetKind()] {
+		d.Default(res)
+	}
+}
+
+func (s *DefaultingStore) Create(ctx context.Context, res *pb.Resource) error {
+	s.applyDefaults(res)
+	return s.store.Create(ctx, res)
+}
+
+func (s *DefaultingStore) Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	return s.store.Get(ctx, kind, namespace, name)
+}
+
+func (s *DefaultingStore) Exists(ctx context.Context, kind, namespace, name string) (bool, error) {
+	return s.store.Exists(ctx, kind, namespace, name)
+}
+
+func (s *DefaultingStore) Update(ctx context.Context, res *pb.Resource) error {
+	s.applyDefaults(res)
+	return s.store.Update(ctx, res)
+}
+
+func (s *DefaultingStore) Delete(ctx context.Context, kind, namespace, name string, opts ...DeleteOption) error {
+	return s.store.Delete(ctx, kind, namespace, name, opts...)
+}
+
+func (s *DefaultingStore) List(ctx context.Context, kind, namespace string) ([]*pb.Resource, error) {
+	return s.store.List(ctx, kind, namespace)
+}
+
+func (s *DefaultingStore) Count(ctx context.Context, kind, namespace string) (int, error) {
+	return s.store.Count(ctx, kind, namespace)
+}
+
+func (s *DefaultingStore) Kinds(ctx context.Context) ([]string, error) {
+	return s.store.Kinds(ctx)
+}
+
+func (s *DefaultingStore) ListAll(ctx context.Context) (map[string][]*pb.Resource, error) {
+	return s.store.ListAll(ctx)
+}
+
+func (s *DefaultingStore) Replace(ctx context.Context, kind string, desired []*pb.Resource) error {
+	return s.Transaction(ctx, func(tx Store) error {
+		return replaceAll(ctx, tx, kind, desired)
+	})
+}
+
+func (s *DefaultingStore) Close() error {
+	return s.store.Close()
+}
+
+func (s *DefaultingStore) Patch(ctx context.Context, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error) {
+	return s.store.Patch(ctx, kind, namespace, name, patch, patchType)
+}
+
+func (s *DefaultingStore) ListPage(ctx context.Context, kind, namespace string, opts PageOptions) (ListResult, error) {
+	return s.store.ListPage(ctx, kind, namespace, opts)
+}
+
+func (s *DefaultingStore) Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.Watch(ctx, kind, namespace, opts...)
+}
+
+func (s *DefaultingStore) WatchResource(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchResource(ctx, kind, namespace, name, opts...)
+}
+
+func (s *DefaultingStore) WatchKinds(ctx context.Context, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchKinds(ctx, kinds, namespace, opts...)
+}
+
+func (s *DefaultingStore) WatchAll(ctx context.Context, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchAll(ctx, opts...)
+}
+
+// Transaction applies the registered defaulters to mutations made inside
+// fn as well.
+func (s *DefaultingStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.store.Transaction(ctx, func(tx Store) error {
+		inner := NewDefaultingStore(tx)
+		s.mu.RLock()
+		for kind, ds := range s.defaulters {
+			inner.defaulters[kind] = ds
+		}
+		s.mu.RUnlock()
+		return fn(inner)
+	})
+}
+
+func (s *DefaultingStore) AddIndex(kind, indexName string, fn func(*pb.Resource) []string) error {
+	return s.store.AddIndex(kind, indexName, fn)
+}
+
+func (s *DefaultingStore) RegisterAdmission(kind string, fn AdmissionFunc) {
+	s.store.RegisterAdmission(kind, fn)
+}
+
+func (s *DefaultingStore) BeginBatch() {
+	s.store.BeginBatch()
+}
+
+func (s *DefaultingStore) CommitBatch() {
+	s.store.CommitBatch()
+}
+
+func (s *DefaultingStore) GetByIndex(ctx context.Context, kind, indexName, value string) ([]*pb.Resource, error) {
+	return s.store.GetByIndex(ctx, kind, indexName, value)
+}
+
+func (s *DefaultingStore) SetObservedGeneration(ctx context.Context, kind, namespace, name string, gen int64) error {
+	return s.store.SetObservedGeneration(ctx, kind, namespace, name, gen)
+}
+
+func (s *DefaultingStore) TransferOwner(ctx context.Context, kind, namespace, name, owner string) error {
+	return s.store.TransferOwner(ctx, kind, namespace, name, owner)
+}
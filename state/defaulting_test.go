@@ -0,0 +1,71 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+)
+
+func TestDefaultingStoreAppliesDefaultsOnCreate(t *testing.T) {
+	ctx := context.Background()
+	store := NewDefaultingStore(NewMemoryStore())
+	store.RegisterDefaulter(api.KindNetworkInterface, DefaulterFunc(func(res *pb.Resource) {
+		if res.GetNetworkInterface().GetMacAddress() == "" {
+			res.GetNetworkInterface().MacAddress = "00:00:00:00:00:00"
+		}
+	}))
+
+	if err := store.Create(ctx, testInterface("eth0", "")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := res.GetNetworkInterface().GetMacAddress(); got != "00:00:00:00:00:00" {
+		t.Errorf("mac = %q, want the default", got)
+	}
+
+	// Explicitly-set values are preserved.
+	if err := store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create eth1: %v", err)
+	}
+	res, err = store.Get(ctx, api.KindNetworkInterface, "", "eth1")
+	if err != nil {
+		t.Fatalf("Get eth1: %v", err)
+	}
+	if got := res.GetNetworkInterface().GetMacAddress(); got != "aa:bb:cc:dd:ee:ff" {
+		t.Errorf("mac = %q, want the explicit value", got)
+	}
+}
+
+func TestDefaultingStoreAppliesDefaultsOnUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := NewDefaultingStore(NewMemoryStore())
+	store.RegisterDefaulter(api.KindNetworkInterface, DefaulterFunc(func(res *pb.Resource) {
+		if res.GetNetworkInterface().GetMacAddress() == "" {
+			res.GetNetworkInterface().MacAddress = "00:00:00:00:00:00"
+		}
+	}))
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.GetNetworkInterface().MacAddress = ""
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	res, err = store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get after update: %v", err)
+	}
+	if got := res.GetNetworkInterface().GetMacAddress(); got != "00:00:00:00:00:00" {
+		t.Errorf("mac = %q, want the default", got)
+	}
+}
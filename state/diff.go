@@ -0,0 +1,58 @@
+package state
+
+import (
+	"strings"
+
+	"github.com/microrun/microrun/api/pb"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+// DiffResources renders a line-oriented diff of the prototext forms of
+// two resources: removed lines are prefixed "- ", added lines "+ ", and
+// unchanged lines are omitted. Lines are aligned with a longest common
+// subsequence so an inserted or removed field does not misalign every
+// line after it.
+func DiffResources(old, new *pb.Resource) string {
+	marshal := prototext.MarshalOptions{Multiline: true}
+	oldLines := strings.Split(strings.TrimSpace(marshal.Format(old)), "\n")
+	newLines := strings.Split(strings.TrimSpace(marshal.Format(new)), "\n")
+
+	// Standard LCS table: lcs[i][j] is the length of the longest common
+	// subsequence of oldLines[i:] and newLines[j:].
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var b strings.Builder
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			i++
+			j++
+		case j < len(newLines) && (i == len(oldLines) || lcs[i][j+1] >= lcs[i+1][j]):
+			b.WriteString("+ ")
+			b.WriteString(newLines[j])
+			b.WriteString("\n")
+			j++
+		default:
+			b.WriteString("- ")
+			b.WriteString(oldLines[i])
+			b.WriteString("\n")
+			i++
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,85 @@
+package state
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+)
+
+func diffInterface(name string, mutate func(*pb.NetworkInterface)) *pb.Resource {
+	res := &pb.Resource{
+		Metadata: &pb.ResourceMetadata{Name: name, Kind: api.KindNetworkInterface},
+		Spec: &pb.Resource_NetworkInterface{NetworkInterface: &pb.NetworkInterface{
+			InterfaceName: name,
+			MacAddress:    "aa:bb:cc:dd:ee:ff",
+		}},
+	}
+	if mutate != nil {
+		mutate(res.GetNetworkInterface())
+	}
+	return res
+}
+
+func TestDiffResourcesChangedScalar(t *testing.T) {
+	old := diffInterface("eth0", nil)
+	new := diffInterface("eth0", func(i *pb.NetworkInterface) { i.Mtu = 9000 })
+
+	diff := DiffResources(old, new)
+	if !strings.Contains(diff, "+") || !strings.Contains(diff, "mtu") {
+		t.Errorf("diff does not show the mtu change:\n%s", diff)
+	}
+	// Lines shared by both resources must not appear in the diff.
+	if strings.Contains(diff, "mac_address") {
+		t.Errorf("diff includes unchanged mac_address line:\n%s", diff)
+	}
+}
+
+func TestDiffResourcesAddedFieldDoesNotMisalign(t *testing.T) {
+	// Adding admin_state inserts a line in the middle of the prototext;
+	// an LCS alignment must not flag the lines after it.
+	old := diffInterface("eth0", func(i *pb.NetworkInterface) { i.Mtu = 1500 })
+	new := diffInterface("eth0", func(i *pb.NetworkInterface) {
+		i.Mtu = 1500
+		i.AdminState = "up"
+	})
+
+	diff := DiffResources(old, new)
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.Contains(line, "mtu") || strings.Contains(line, "mac_address") {
+			t.Errorf("unchanged line flagged by diff: %q\nfull diff:\n%s", line, diff)
+		}
+	}
+	if !strings.Contains(diff, "admin_state") {
+		t.Errorf("diff does not show the added admin_state:\n%s", diff)
+	}
+}
+
+func TestDiffResourcesRemovedField(t *testing.T) {
+	old := diffInterface("eth0", func(i *pb.NetworkInterface) {
+		i.IpAddresses = []string{"192.0.2.1/24"}
+	})
+	new := diffInterface("eth0", nil)
+
+	diff := DiffResources(old, new)
+	removed := false
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "- ") && strings.Contains(line, "ip_addresses") {
+			removed = true
+		}
+		if strings.Contains(line, "mac_address") {
+			t.Errorf("unchanged line flagged by diff: %q", line)
+		}
+	}
+	if !removed {
+		t.Errorf("diff does not show removed ip_addresses:\n%s", diff)
+	}
+}
+
+func TestDiffResourcesIdentical(t *testing.T) {
+	old := diffInterface("eth0", nil)
+	if diff := DiffResources(old, diffInterface("eth0", nil)); diff != "" {
+		t.Errorf("diff of identical resources = %q, want empty", diff)
+	}
+}
@@ -0,0 +1,274 @@
+package state
+
+import (
+	"context"
+	"sync"
+
+	"github.com/microrun/microrun/api/pb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DryRunResult describes what a mutation would have done.
+type DryRunResult struct {
+	// Action is the event the mutation would have emitted.
+	Action EventType
+	// Resource is the resource as it would have been stored (for a
+	// delete, the resource that would have been removed).
+	Resource *pb.Resource
+	// Diff is the prototext line diff the mutation would have produced.
+	Diff string
+}
+
+// DryRunStore wraps a Store and previews mutations instead of applying
+// them: Create, Update, and Delete run the same validation, conflict, and
+// generation logic as the real store against its current contents, but
+// nothing is persisted and no watch events fire. Reads pass through.
+// The would-be outcomes are collected for inspection via Results.
+type DryRunStore struct {
+	store Store
+
+	mu      sync.Mutex
+	results []DryRunResult
+}
+
+// NewDryRunStore returns a store that previews mutations against store
+// without applying them.
+func NewDryRunStore(store Store) *DryRunStore {
+	return &DryRunStore{store: store}
+}
+
+// Results returns the outcomes of the mutations previewed so far, in
+// order. No-op updates do not produce a result.
+func (s *DryRunStore) Results() []DryRunResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]DryRunResult, len(s.results))
+	copy(out, s.results)
+	return out
+}
+
+func (s *DryRunStore) record(r DryRunResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = append(s.results, r)
+}
+
+func (s *DryRunStore) Create(ctx context.Context, res *pb.Resource) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := validateMetadata(res); err != nil {
+		return err
+	}
+	if err := validateSpecKind(res); err != nil {
+		return err
+	}
+	kind := res.GetMetadata().GetKind()
+	namespace := res.GetMetadata().GetNamespace()
+	name := res.GetMetadata().GetName()
+	if _, err := s.store.Get(ctx, kind, namespace, name); err == nil {
+		return &AlreadyExistsError{Kind: kind, Name: name}
+	}
+	would := proto.Clone(res).(*pb.Resource)
+	would.Metadata.Generation = 1
+	s.record(DryRunResult{
+		Action:   EventCreated,
+		Resource: would,
+		Diff:     DiffResources(&pb.Resource{}, would),
+	})
+	return nil
+}
+
+func (s *DryRunStore) Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	return s.store.Get(ctx, kind, namespace, name)
+}
+
+func (s *DryRunStore) Exists(ctx context.Context, kind, namespace, name string) (bool, error) {
+	return s.store.Exists(ctx, kind, namespace, name)
+}
+
+func (s *DryRunStore) Update(ctx context.Context, res *pb.Resource) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := validateMetadata(res); err != nil {
+		return err
+	}
+	if err := validateSpecKind(res); err != nil {
+		return err
+	}
+	kind := res.GetMetadata().GetKind()
+	namespace := res.GetMetadata().GetNamespace()
+	name := res.GetMetadata().GetName()
+	existing, err := s.store.Get(ctx, kind, namespace, name)
+	if err != nil {
+		return err
+	}
+	if gen := res.GetMetadata().GetGeneration(); gen != 0 && gen != existing.GetMetadata().GetGeneration() {
+		return &ConflictError{Kind: kind, Name: name, Expected: existing.GetMetadata().GetGeneration(), Got: gen}
+	}
+	would := proto.Clone(res).(*pb.Resource)
+	would.Metadata.Owner = existing.GetMetadata().GetOwner()
+	would.Metadata.Generation = existing.GetMetadata().GetGeneration()
+	would.Metadata.DeletionTimestamp = existing.GetMetadata().GetDeletionTimestamp()
+	would.Metadata.ObservedGeneration = existing.GetMetadata().GetObservedGeneration()
+	if proto.Equal(existing, would) {
+		return nil
+	}
+	if would.GetMetadata().GetDeletionTimestamp() != nil && len(would.GetMetadata().GetFinalizers()) == 0 {
+		s.record(DryRunResult{
+			Action:   EventDeleted,
+			Resource: would,
+			Diff:     DiffResources(existing, &pb.Resource{}),
+		})
+		return nil
+	}
+	if specChanged(existing, would) {
+		would.Metadata.Generation++
+	}
+	s.record(DryRunResult{
+		Action:   EventUpdated,
+		Resource: would,
+		Diff:     DiffResources(existing, would),
+	})
+	return nil
+}
+
+func (s *DryRunStore) Delete(ctx context.Context, kind, namespace, name string, opts ...DeleteOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	options := resolveDeleteOptions(opts)
+	existing, err := s.store.Get(ctx, kind, namespace, name)
+	if err != nil {
+		return err
+	}
+	if options.policy == DeleteOrphan {
+		would := proto.Clone(existing).(*pb.Resource)
+		would.Metadata.Owner = ""
+		would.Metadata.Writers = nil
+		s.record(DryRunResult{
+			Action:   EventUpdated,
+			Resource: would,
+			Diff:     DiffResources(existing, would),
+		})
+		return nil
+	}
+	if options.policy != DeleteBackground && len(existing.GetMetadata().GetFinalizers()) > 0 {
+		if existing.GetMetadata().GetDeletionTimestamp() != nil {
+			return nil
+		}
+		would := proto.Clone(existing).(*pb.Resource)
+		would.Metadata.DeletionTimestamp = timestamppb.Now()
+		s.record(DryRunResult{
+			Action:   EventUpdated,
+			Resource: would,
+			Diff:     DiffResources(existing, would),
+		})
+		return nil
+	}
+	s.record(DryRunResult{
+		Action:   EventDeleted,
+		Resource: existing,
+		Diff:     DiffResources(existing, &pb.Resource{}),
+	})
+	return nil
+}
+
+func (s *DryRunStore) List(ctx context.Context, kind, namespace string) ([]*pb.Resource, error) {
+	return s.store.List(ctx, kind, namespace)
+}
+
+func (s *DryRunStore) Count(ctx context.Context, kind, namespace string) (int, error) {
+	return s.store.Count(ctx, kind, namespace)
+}
+
+func (s *DryRunStore) Kinds(ctx context.Context) ([]string, error) {
+	return s.store.Kinds(ctx)
+}
+
+func (s *DryRunStore) ListAll(ctx context.Context) (map[string][]*pb.Resource, error) {
+	return s.store.ListAll(ctx)
+}
+
+func (s *DryRunStore) Replace(ctx context.Context, kind string, desired []*pb.Resource) error {
+	return s.Transaction(ctx, func(tx Store) error {
+		return replaceAll(ctx, tx, kind, desired)
+	})
+}
+
+func (s *DryRunStore) Close() error {
+	return s.store.Close()
+}
+
+// Patch previews the merged resource: the would-be update is recorded
+// like any other dry-run mutation and nothing is stored.
+func (s *DryRunStore) Patch(ctx context.Context, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error) {
+	return patchResource(ctx, s, kind, namespace, name, patch, patchType)
+}
+
+func (s *DryRunStore) ListPage(ctx context.Context, kind, namespace string, opts PageOptions) (ListResult, error) {
+	return s.store.ListPage(ctx, kind, namespace, opts)
+}
+
+func (s *DryRunStore) Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.Watch(ctx, kind, namespace, opts...)
+}
+
+func (s *DryRunStore) WatchResource(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchResource(ctx, kind, namespace, name, opts...)
+}
+
+func (s *DryRunStore) WatchKinds(ctx context.Context, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchKinds(ctx, kinds, namespace, opts...)
+}
+
+func (s *DryRunStore) WatchAll(ctx context.Context, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchAll(ctx, opts...)
+}
+
+// Transaction previews the mutations made inside fn without touching the
+// underlying store; the would-be outcomes are merged into Results.
+func (s *DryRunStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	tx := NewDryRunStore(s.store)
+	if err := fn(tx); err != nil {
+		return err
+	}
+	for _, r := range tx.Results() {
+		s.record(r)
+	}
+	return nil
+}
+
+func (s *DryRunStore) AddIndex(kind, indexName string, fn func(*pb.Resource) []string) error {
+	return s.store.AddIndex(kind, indexName, fn)
+}
+
+func (s *DryRunStore) RegisterAdmission(kind string, fn AdmissionFunc) {
+	s.store.RegisterAdmission(kind, fn)
+}
+
+func (s *DryRunStore) BeginBatch() {
+	s.store.BeginBatch()
+}
+
+func (s *DryRunStore) CommitBatch() {
+	s.store.CommitBatch()
+}
+
+func (s *DryRunStore) GetByIndex(ctx context.Context, kind, indexName, value string) ([]*pb.Resource, error) {
+	return s.store.GetByIndex(ctx, kind, indexName, value)
+}
+
+// SetObservedGeneration validates that the resource exists but does not
+// record the observed generation.
+func (s *DryRunStore) SetObservedGeneration(ctx context.Context, kind, namespace, name string, gen int64) error {
+	_, err := s.store.Get(ctx, kind, namespace, name)
+	return err
+}
+
+func (s *DryRunStore) TransferOwner(ctx context.Context, kind, namespace, name, owner string) error {
+	_, err := s.store.Get(ctx, kind, namespace, name)
+	return err
+}
@@ -0,0 +1,97 @@
+package state
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+)
+
+func TestDryRunStoreCreateDoesNotPersist(t *testing.T) {
+	ctx := context.Background()
+	store := NewDryRunStore(NewMemoryStore())
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	results := store.Results()
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	would := results[0]
+	if would.Action != EventCreated {
+		t.Errorf("action = %v, want %v", would.Action, EventCreated)
+	}
+	if would.Resource.GetMetadata().GetGeneration() != 1 {
+		t.Errorf("would-be generation = %d, want 1", would.Resource.GetMetadata().GetGeneration())
+	}
+	if !strings.Contains(would.Diff, "eth0") {
+		t.Errorf("diff does not mention the resource:\n%s", would.Diff)
+	}
+
+	// Nothing was persisted.
+	if _, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0"); err == nil {
+		t.Fatal("Get succeeded after a dry-run Create")
+	}
+}
+
+func TestDryRunStoreValidates(t *testing.T) {
+	ctx := context.Background()
+	store := NewDryRunStore(NewMemoryStore())
+
+	// Validation failures surface exactly as they would on the real store.
+	if err := store.Create(ctx, &pb.Resource{Metadata: &pb.ResourceMetadata{Name: "eth0"}}); err == nil {
+		t.Error("Create accepted a resource without a kind")
+	}
+	if err := store.Update(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err == nil {
+		t.Error("Update accepted a resource that does not exist")
+	}
+	if len(store.Results()) != 0 {
+		t.Errorf("failed mutations recorded results: %v", store.Results())
+	}
+}
+
+func TestDryRunStoreUpdatePreviewsDiff(t *testing.T) {
+	ctx := context.Background()
+	mem := NewMemoryStore()
+	if err := mem.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	store := NewDryRunStore(mem)
+
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	// A no-op update produces no result.
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("no-op Update: %v", err)
+	}
+	if len(store.Results()) != 0 {
+		t.Fatalf("no-op update recorded a result")
+	}
+
+	res.GetNetworkInterface().IpAddresses = []string{"192.0.2.1/24"}
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	results := store.Results()
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	if results[0].Action != EventUpdated || !strings.Contains(results[0].Diff, "192.0.2.1/24") {
+		t.Errorf("result = %v %q", results[0].Action, results[0].Diff)
+	}
+
+	// The stored resource is untouched.
+	stored, err := mem.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get stored: %v", err)
+	}
+	if stored.GetMetadata().GetGeneration() != 1 || len(stored.GetNetworkInterface().GetIpAddresses()) != 0 {
+		t.Errorf("dry-run update leaked into the store: %v", stored)
+	}
+}
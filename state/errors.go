@@ -0,0 +1,71 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NotFoundError is returned when an operation names a resource that does
+// not exist.
+type NotFoundError struct {
+	Kind string
+	Name string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("resource %s/%s not found", e.Kind, e.Name)
+}
+
+// AlreadyExistsError is returned by Create when a resource with the same
+// kind and name is already stored.
+type AlreadyExistsError struct {
+	Kind string
+	Name string
+}
+
+func (e *AlreadyExistsError) Error() string {
+	return fmt.Sprintf("resource %s/%s already exists", e.Kind, e.Name)
+}
+
+// IsNotFound reports whether err is (or wraps) a NotFoundError.
+func IsNotFound(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
+}
+
+// IsAlreadyExists reports whether err is (or wraps) an AlreadyExistsError.
+func IsAlreadyExists(err error) bool {
+	var exists *AlreadyExistsError
+	return errors.As(err, &exists)
+}
+
+// ConflictError is returned by Update when the caller's resource carries a
+// generation that no longer matches the stored one, indicating a
+// concurrent modification.
+type ConflictError struct {
+	Kind     string
+	Name     string
+	Expected int64
+	Got      int64
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("conflicting update of %s/%s: stored generation %d, got %d",
+		e.Kind, e.Name, e.Expected, e.Got)
+}
+
+// ClosedError is returned for operations on a store whose Close has been
+// called.
+type ClosedError struct {
+	Op string
+}
+
+func (e *ClosedError) Error() string {
+	return fmt.Sprintf("store is closed: %s", e.Op)
+}
+
+// IsClosed reports whether err is (or wraps) a ClosedError.
+func IsClosed(err error) bool {
+	var closed *ClosedError
+	return errors.As(err, &closed)
+}
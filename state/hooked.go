@@ -0,0 +1,168 @@
+package state
+
+import (
+	"context"
+
+	"github.com/microrun/microrun/api/pb"
+)
+
+// Hooks bundles optional callbacks invoked around store operations. Each
+// Before hook runs ahead of the operation and each After hook runs once
+// it has returned, with the operation's error. Nil hooks are skipped, so
+// callers populate only the operations they care about. Hooks observe;
+// they cannot veto an operation — use admission for that.
+type Hooks struct {
+	BeforeCreate func(ctx context.Context, res *pb.Resource)
+	AfterCreate  func(ctx context.Context, res *pb.Resource, err error)
+	BeforeUpdate func(ctx context.Context, res *pb.Resource)
+	AfterUpdate  func(ctx context.Context, res *pb.Resource, err error)
+	BeforeDelete func(ctx context.Context, kind, namespace, name string)
+	AfterDelete  func(ctx context.Context, kind, namespace, name string, err error)
+	BeforeGet    func(ctx context.Context, kind, namespace, name string)
+	AfterGet     func(ctx context.Context, res *pb.Resource, err error)
+}
+
+// hookedStore wraps a Store and fires the configured hooks around each
+// operation. It is a generic extension point for cross-cutting concerns
+// that do not warrant a dedicated decorator.
+type hookedStore struct {
+	store Store
+	hooks Hooks
+}
+
+// NewHookedStore returns a store invoking hooks around the wrapped
+// store's operations. Transactions carry the hooks into the transaction
+// scope, so hooked operations inside fn fire them too.
+func NewHookedStore(store Store, hooks Hooks) Store {
+	return &hookedStore{store: store, hooks: hooks}
+}
+
+func (s *hookedStore) Create(ctx context.Context, res *pb.Resource) error {
+	if s.hooks.BeforeCreate != nil {
+		s.hooks.BeforeCreate(ctx, res)
+	}
+	err := s.store.Create(ctx, res)
+	if s.hooks.AfterCreate != nil {
+		s.hooks.AfterCreate(ctx, res, err)
+	}
+	return err
+}
+
+func (s *hookedStore) Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	if s.hooks.BeforeGet != nil {
+		s.hooks.BeforeGet(ctx, kind, namespace, name)
+	}
+	res, err := s.store.Get(ctx, kind, namespace, name)
+	if s.hooks.AfterGet != nil {
+		s.hooks.AfterGet(ctx, res, err)
+	}
+	return res, err
+}
+
+func (s *hookedStore) Exists(ctx context.Context, kind, namespace, name string) (bool, error) {
+	return s.store.Exists(ctx, kind, namespace, name)
+}
+
+func (s *hookedStore) Update(ctx context.Context, res *pb.Resource) error {
+	if s.hooks.BeforeUpdate != nil {
+		s.hooks.BeforeUpdate(ctx, res)
+	}
+	err := s.store.Update(ctx, res)
+	if s.hooks.AfterUpdate != nil {
+		s.hooks.AfterUpdate(ctx, res, err)
+	}
+	return err
+}
+
+func (s *hookedStore) Delete(ctx context.Context, kind, namespace, name string, opts ...DeleteOption) error {
+	if s.hooks.BeforeDelete != nil {
+		s.hooks.BeforeDelete(ctx, kind, namespace, name)
+	}
+	err := s.store.Delete(ctx, kind, namespace, name, opts...)
+	if s.hooks.AfterDelete != nil {
+		s.hooks.AfterDelete(ctx, kind, namespace, name, err)
+	}
+	return err
+}
+
+func (s *hookedStore) List(ctx context.Context, kind, namespace string) ([]*pb.Resource, error) {
+	return s.store.List(ctx, kind, namespace)
+}
+
+func (s *hookedStore) Count(ctx context.Context, kind, namespace string) (int, error) {
+	return s.store.Count(ctx, kind, namespace)
+}
+
+func (s *hookedStore) Kinds(ctx context.Context) ([]string, error) {
+	return s.store.Kinds(ctx)
+}
+
+func (s *hookedStore) ListAll(ctx context.Context) (map[string][]*pb.Resource, error) {
+	return s.store.ListAll(ctx)
+}
+
+func (s *hookedStore) Replace(ctx context.Context, kind string, desired []*pb.Resource) error {
+	return s.store.Replace(ctx, kind, desired)
+}
+
+func (s *hookedStore) Close() error {
+	return s.store.Close()
+}
+
+func (s *hookedStore) Patch(ctx context.Context, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error) {
+	return s.store.Patch(ctx, kind, namespace, name, patch, patchType)
+}
+
+func (s *hookedStore) ListPage(ctx context.Context, kind, namespace string, opts PageOptions) (ListResult, error) {
+	return s.store.ListPage(ctx, kind, namespace, opts)
+}
+
+func (s *hookedStore) Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.Watch(ctx, kind, namespace, opts...)
+}
+
+func (s *hookedStore) WatchResource(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchResource(ctx, kind, namespace, name, opts...)
+}
+
+func (s *hookedStore) WatchKinds(ctx context.Context, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchKinds(ctx, kinds, namespace, opts...)
+}
+
+func (s *hookedStore) WatchAll(ctx context.Context, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchAll(ctx, opts...)
+}
+
+func (s *hookedStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.store.Transaction(ctx, func(tx Store) error {
+		return fn(NewHookedStore(tx, s.hooks))
+	})
+}
+
+func (s *hookedStore) AddIndex(kind, indexName string, fn func(*pb.Resource) []string) error {
+	return s.store.AddIndex(kind, indexName, fn)
+}
+
+func (s *hookedStore) RegisterAdmission(kind string, fn AdmissionFunc) {
+	s.store.RegisterAdmission(kind, fn)
+}
+
+func (s *hookedStore) BeginBatch() {
+	s.store.BeginBatch()
+}
+
+func (s *hookedStore) CommitBatch() {
+	s.store.CommitBatch()
+}
+
+func (s *hookedStore) GetByIndex(ctx context.Context, kind, indexName, value string) ([]*pb.Resource, error) {
+	return s.store.GetByIndex(ctx, kind, indexName, value)
+}
+
+func (s *hookedStore) SetObservedGeneration(ctx context.Context, kind, namespace, name string, gen int64) error {
+	return s.store.SetObservedGeneration(ctx, kind, namespace, name, gen)
+}
+
+func (s *hookedStore) TransferOwner(ctx context.Context, kind, namespace, name, owner string) error {
+	return s.store.TransferOwner(ctx, kind, namespace, name, owner)
+}
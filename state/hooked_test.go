@@ -0,0 +1,104 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+)
+
+func TestHookedStoreFiresHooks(t *testing.T) {
+	ctx := context.Background()
+	var calls []string
+	var lastErr error
+	store := NewHookedStore(NewMemoryStore(), Hooks{
+		BeforeCreate: func(ctx context.Context, res *pb.Resource) {
+			calls = append(calls, "before-create "+res.GetMetadata().GetName())
+		},
+		AfterCreate: func(ctx context.Context, res *pb.Resource, err error) {
+			calls = append(calls, "after-create "+res.GetMetadata().GetName())
+			lastErr = err
+		},
+		BeforeUpdate: func(ctx context.Context, res *pb.Resource) {
+			calls = append(calls, "before-update "+res.GetMetadata().GetName())
+		},
+		AfterUpdate: func(ctx context.Context, res *pb.Resource, err error) {
+			calls = append(calls, "after-update "+res.GetMetadata().GetName())
+			lastErr = err
+		},
+		BeforeDelete: func(ctx context.Context, kind, namespace, name string) {
+			calls = append(calls, "before-delete "+name)
+		},
+		AfterDelete: func(ctx context.Context, kind, namespace, name string, err error) {
+			calls = append(calls, "after-delete "+name)
+			lastErr = err
+		},
+		BeforeGet: func(ctx context.Context, kind, namespace, name string) {
+			calls = append(calls, "before-get "+name)
+		},
+		AfterGet: func(ctx context.Context, res *pb.Resource, err error) {
+			calls = append(calls, "after-get "+res.GetMetadata().GetName())
+			lastErr = err
+		},
+	})
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.GetNetworkInterface().Mtu = 9000
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if lastErr != nil {
+		t.Errorf("after hook saw error %v for successful operations", lastErr)
+	}
+
+	want := []string{
+		"before-create eth0", "after-create eth0",
+		"before-get eth0", "after-get eth0",
+		"before-update eth0", "after-update eth0",
+		"before-delete eth0", "after-delete eth0",
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("hooks fired %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("call %d = %q, want %q", i, calls[i], want[i])
+		}
+	}
+}
+
+func TestHookedStorePassesErrorsToAfterHooks(t *testing.T) {
+	ctx := context.Background()
+	var createErr, deleteErr error
+	store := NewHookedStore(NewMemoryStore(), Hooks{
+		AfterCreate: func(ctx context.Context, res *pb.Resource, err error) { createErr = err },
+		AfterDelete: func(ctx context.Context, kind, namespace, name string, err error) { deleteErr = err },
+	})
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); !IsAlreadyExists(err) {
+		t.Fatalf("duplicate Create: %v", err)
+	}
+	if !IsAlreadyExists(createErr) {
+		t.Errorf("AfterCreate saw %v, want AlreadyExists", createErr)
+	}
+
+	if err := store.Delete(ctx, api.KindNetworkInterface, "", "missing"); !IsNotFound(err) {
+		t.Fatalf("Delete missing: %v", err)
+	}
+	if !IsNotFound(deleteErr) {
+		t.Errorf("AfterDelete saw %v, want NotFound", deleteErr)
+	}
+}
@@ -0,0 +1,88 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ImmutableFieldError is returned when an update changes a spec field
+// registered as immutable for its kind.
+type ImmutableFieldError struct {
+	Kind  string
+	Name  string
+	Field string
+}
+
+func (e *ImmutableFieldError) Error() string {
+	return fmt.Sprintf("field %s of %s/%s is immutable", e.Field, e.Kind, e.Name)
+}
+
+// IsImmutableField reports whether err is (or wraps) an
+// ImmutableFieldError, returning the typed error when it does.
+func IsImmutableField(err error) (*ImmutableFieldError, bool) {
+	var immutable *ImmutableFieldError
+	if errors.As(err, &immutable) {
+		return immutable, true
+	}
+	return nil, false
+}
+
+// RegisterImmutableFields registers an admission hook enforcing that the
+// given spec field paths of kind never change after creation. Paths are
+// proto field names relative to the kind's spec message, dot-separated
+// for nested messages, e.g. "interface_name".
+func RegisterImmutableFields(store Store, kind string, paths ...string) {
+	store.RegisterAdmission(kind, func(ctx context.Context, op AdmissionOp, old, new *pb.Resource) error {
+		if op != AdmissionUpdate || old == nil || new == nil {
+			return nil
+		}
+		oldSpec, err := api.ExtractSpec(old)
+		if err != nil {
+			return nil
+		}
+		newSpec, err := api.ExtractSpec(new)
+		if err != nil {
+			return nil
+		}
+		for _, path := range paths {
+			before, okBefore := fieldByPath(oldSpec.ProtoReflect(), path)
+			after, okAfter := fieldByPath(newSpec.ProtoReflect(), path)
+			if okBefore != okAfter || (okBefore && !before.Equal(after)) {
+				return &ImmutableFieldError{
+					Kind:  kind,
+					Name:  new.GetMetadata().GetName(),
+					Field: path,
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// fieldByPath resolves a dot-separated proto field path against a
+// message, reporting false for paths that do not exist or descend
+// through a list or map.
+func fieldByPath(msg protoreflect.Message, path string) (protoreflect.Value, bool) {
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		field := msg.Descriptor().Fields().ByName(protoreflect.Name(segment))
+		if field == nil {
+			return protoreflect.Value{}, false
+		}
+		value := msg.Get(field)
+		if i == len(segments)-1 {
+			return value, true
+		}
+		if field.Message() == nil || field.IsList() || field.IsMap() {
+			return protoreflect.Value{}, false
+		}
+		msg = value.Message()
+	}
+	return protoreflect.Value{}, false
+}
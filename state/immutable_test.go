@@ -0,0 +1,53 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+)
+
+func TestImmutableFieldRejectsChange(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	RegisterImmutableFields(store, api.KindNetworkInterface, "interface_name")
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	res.GetNetworkInterface().InterfaceName = "renamed0"
+	err = store.Update(ctx, res)
+	if err == nil {
+		t.Fatal("Update changing an immutable field succeeded")
+	}
+	immutable, ok := IsImmutableField(err)
+	if !ok {
+		t.Fatalf("Update = %v, want ImmutableFieldError", err)
+	}
+	if immutable.Field != "interface_name" || immutable.Name != "eth0" {
+		t.Errorf("error = %+v, want interface_name on eth0", immutable)
+	}
+}
+
+func TestImmutableFieldAllowsOtherUpdates(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	RegisterImmutableFields(store, api.KindNetworkInterface, "interface_name")
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.GetNetworkInterface().Mtu = 9000
+	if err := store.Update(ctx, res); err != nil {
+		t.Errorf("Update of a mutable field: %v", err)
+	}
+}
@@ -0,0 +1,1228 @@
+package state
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/microrun/microrun/clock"
+	"github.com/microrun/microrun/logging"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// watchBufferSize is the capacity of the channel handed to each watcher.
+// A watcher that falls this far behind will block mutations.
+const watchBufferSize = 100
+
+// eventHistorySize bounds the ring of recent events kept for
+// WithResumeFrom replay.
+const eventHistorySize = 1024
+
+// memoryStore is the in-memory Store implementation. All state is held in
+// nested maps keyed by kind and namespaced name and guarded by a single
+// mutex.
+type memoryStore struct {
+	mu          sync.RWMutex
+	data        map[string]map[string]*pb.Resource
+	watchers    map[string][]*watcher
+	allWatchers []*watcher
+	logger      *slog.Logger
+
+	// clock supplies the store's timestamps; tests swap in a fake.
+	clock clock.Clock
+
+	// seq numbers committed events; history is a bounded ring of recent
+	// events kept for WithResumeFrom replay.
+	seq     uint64
+	history []Event
+
+	// watcherSeq hands out watcher IDs for monitoring; coalesced counts
+	// events collapsed away by coalescing watchers.
+	watcherSeq uint64
+	coalesced  atomic.Uint64
+
+	// indexes holds the registered index functions per kind and index
+	// name; index holds the materialized values, mapping kind, index
+	// name, and value to the names of the matching resources.
+	indexes map[string]map[string]func(*pb.Resource) []string
+	index   map[string]map[string]map[string]map[string]bool
+
+	// admissions holds the registered admission hooks per kind, run in
+	// registration order on every create, update, and delete.
+	admissions map[string][]AdmissionFunc
+
+	// wal, when non-nil, records every committed mutation so state can
+	// be rebuilt after a restart. See NewMemoryStoreWithWAL.
+	wal *walLog
+
+	// closed flips when Close is called; mutations and watches fail with
+	// a ClosedError from then on.
+	closed bool
+
+	// batching holds back watch deliveries while a batch opened with
+	// BeginBatch is in flight; batched collects the held events until
+	// CommitBatch flushes them.
+	batching bool
+	batched  []Event
+
+	// pending, when non-nil, marks this store as a transaction scratch
+	// copy: notify collects events here instead of delivering them, so
+	// they can be replayed on the real store after commit.
+	pending *[]Event
+}
+
+// A watcher with an empty kind observes every kind (registered through
+// WatchAll); its namespace selects which namespace it observes, with
+// NamespaceAll matching every namespace.
+type watcher struct {
+	id        uint64
+	kind      string
+	namespace string
+	// name, when non-empty, narrows the watcher to a single resource.
+	name string
+	ch   chan Event
+}
+
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		data:       make(map[string]map[string]*pb.Resource),
+		watchers:   make(map[string][]*watcher),
+		indexes:    make(map[string]map[string]func(*pb.Resource) []string),
+		index:      make(map[string]map[string]map[string]map[string]bool),
+		admissions: make(map[string][]AdmissionFunc),
+		clock:      clock.Real{},
+		logger:     logging.NewLogger("store"),
+	}
+}
+
+func (s *memoryStore) Create(ctx context.Context, res *pb.Resource) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := validateMetadata(res); err != nil {
+		return err
+	}
+	if err := validateSpecKind(res); err != nil {
+		return err
+	}
+	kind := res.GetMetadata().GetKind()
+	res, err := s.admit(ctx, AdmissionCreate, kind, nil, res)
+	if err != nil {
+		return err
+	}
+	name := res.GetMetadata().GetName()
+	key := nsKey(res.GetMetadata().GetNamespace(), name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return &ClosedError{Op: "create"}
+	}
+
+	if _, ok := s.data[kind][key]; ok {
+		return &AlreadyExistsError{Kind: kind, Name: name}
+	}
+	stored := proto.Clone(res).(*pb.Resource)
+	stored.Metadata.Generation = 1
+	stored.Metadata.Uid = newUID()
+	stored.Metadata.SpecHash = specHash(stored)
+	if s.data[kind] == nil {
+		s.data[kind] = make(map[string]*pb.Resource)
+	}
+	s.data[kind][key] = stored
+	s.indexAdd(stored)
+	s.logger.Debug("created resource", "kind", kind, "name", name)
+	s.notify(Event{Type: EventCreated, Resource: proto.Clone(stored).(*pb.Resource)})
+	return nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res, ok := s.data[kind][nsKey(namespace, name)]
+	if !ok {
+		return nil, &NotFoundError{Kind: kind, Name: name}
+	}
+	return proto.Clone(res).(*pb.Resource), nil
+}
+
+// Exists checks for the named resource without cloning it.
+func (s *memoryStore) Exists(ctx context.Context, kind, namespace, name string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.data[kind][nsKey(namespace, name)]
+	return ok, nil
+}
+
+// GetRef implements RefGetter: it returns the stored resource itself,
+// without the defensive clone Get makes. The result must not be mutated.
+// A later write to the same name replaces the stored pointer rather than
+// changing it in place, so the view stays internally consistent.
+func (s *memoryStore) GetRef(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res, ok := s.data[kind][nsKey(namespace, name)]
+	if !ok {
+		return nil, &NotFoundError{Kind: kind, Name: name}
+	}
+	return res, nil
+}
+
+func (s *memoryStore) Update(ctx context.Context, res *pb.Resource) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := validateMetadata(res); err != nil {
+		return err
+	}
+	if err := validateSpecKind(res); err != nil {
+		return err
+	}
+	kind := res.GetMetadata().GetKind()
+	if old, err := s.Get(ctx, kind, res.GetMetadata().GetNamespace(), res.GetMetadata().GetName()); err == nil {
+		res, err = s.admit(ctx, AdmissionUpdate, kind, old, res)
+		if err != nil {
+			return err
+		}
+	}
+	name := res.GetMetadata().GetName()
+	key := nsKey(res.GetMetadata().GetNamespace(), name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return &ClosedError{Op: "update"}
+	}
+
+	// Guard the kind map explicitly: a kind that has never seen a Create
+	// has no inner map, and the write below must not assume one exists.
+	kindData, ok := s.data[kind]
+	if !ok {
+		return &NotFoundError{Kind: kind, Name: name}
+	}
+	existing, ok := kindData[key]
+	if !ok {
+		return &NotFoundError{Kind: kind, Name: name}
+	}
+	if gen := res.GetMetadata().GetGeneration(); gen != 0 && gen != existing.GetMetadata().GetGeneration() {
+		return &ConflictError{Kind: kind, Name: name, Expected: existing.GetMetadata().GetGeneration(), Got: gen}
+	}
+	updated := proto.Clone(res).(*pb.Resource)
+	updated.Metadata.Owner = existing.GetMetadata().GetOwner()
+	updated.Metadata.Generation = existing.GetMetadata().GetGeneration()
+	// The deletion timestamp and observed generation are owned by the
+	// store; callers cannot change them through Update.
+	updated.Metadata.DeletionTimestamp = existing.GetMetadata().GetDeletionTimestamp()
+	updated.Metadata.ObservedGeneration = existing.GetMetadata().GetObservedGeneration()
+	updated.Metadata.Uid = existing.GetMetadata().GetUid()
+	updated.Metadata.SpecHash = specHash(updated)
+	if proto.Equal(existing, updated) {
+		return nil
+	}
+	// Clearing the last finalizer of a soft-deleted resource completes
+	// the pending delete instead of storing the update.
+	if updated.GetMetadata().GetDeletionTimestamp() != nil && len(updated.GetMetadata().GetFinalizers()) == 0 {
+		delete(kindData, key)
+		s.indexRemove(existing)
+		s.logger.Debug("deleted resource after finalizers cleared", "kind", kind, "name", name)
+		s.notify(Event{Type: EventDeleted, Resource: proto.Clone(updated).(*pb.Resource)})
+		return nil
+	}
+	s.logger.Debug("updating resource", "kind", kind, "name", name,
+		"diff", DiffResources(existing, updated))
+	// Generation tracks the spec: an update that only touches metadata
+	// bookkeeping or the status subresource is stored and notified but
+	// does not bump it.
+	if specChanged(existing, updated) {
+		updated.Metadata.Generation++
+	}
+	kindData[key] = updated
+	s.indexRemove(existing)
+	s.indexAdd(updated)
+	s.notify(Event{Type: EventUpdated, Resource: proto.Clone(updated).(*pb.Resource)})
+	return nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, kind, namespace, name string, opts ...DeleteOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	options := resolveDeleteOptions(opts)
+	if old, err := s.Get(ctx, kind, namespace, name); err == nil {
+		if _, err := s.admit(ctx, AdmissionDelete, kind, old, nil); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return &ClosedError{Op: "delete"}
+	}
+
+	key := nsKey(namespace, name)
+	res, ok := s.data[kind][key]
+	if !ok {
+		return &NotFoundError{Kind: kind, Name: name}
+	}
+	// An orphaning delete keeps the resource: it only severs ownership,
+	// so the resource outlives its managing generator and another owner
+	// can adopt it.
+	if options.policy == DeleteOrphan {
+		res.Metadata.Owner = ""
+		res.Metadata.Writers = nil
+		s.logger.Debug("orphaned resource", "kind", kind, "name", name)
+		s.notify(Event{Type: EventUpdated, Resource: proto.Clone(res).(*pb.Resource)})
+		return nil
+	}
+	// A finalized resource is only marked for deletion: the store sets
+	// the deletion timestamp and emits an update, and the resource is
+	// removed once its controllers clear the finalizers. A background
+	// delete skips that and removes the resource right away.
+	if options.policy != DeleteBackground && len(res.GetMetadata().GetFinalizers()) > 0 {
+		if res.GetMetadata().GetDeletionTimestamp() != nil {
+			return nil
+		}
+		res.Metadata.DeletionTimestamp = timestamppb.New(s.clock.Now())
+		s.logger.Debug("marked resource for deletion", "kind", kind, "name", name,
+			"finalizers", res.GetMetadata().GetFinalizers())
+		s.notify(Event{Type: EventUpdated, Resource: proto.Clone(res).(*pb.Resource)})
+		return nil
+	}
+	delete(s.data[kind], key)
+	s.indexRemove(res)
+	s.logger.Debug("deleted resource", "kind", kind, "name", name)
+	s.notify(Event{Type: EventDeleted, Resource: proto.Clone(res).(*pb.Resource)})
+	return nil
+}
+
+func (s *memoryStore) List(ctx context.Context, kind, namespace string) ([]*pb.Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*pb.Resource, 0, len(s.data[kind]))
+	for _, res := range s.data[kind] {
+		if namespace != NamespaceAll && res.GetMetadata().GetNamespace() != namespace {
+			continue
+		}
+		out = append(out, proto.Clone(res).(*pb.Resource))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if a, b := out[i].GetMetadata().GetNamespace(), out[j].GetMetadata().GetNamespace(); a != b {
+			return a < b
+		}
+		return out[i].GetMetadata().GetName() < out[j].GetMetadata().GetName()
+	})
+	return out, nil
+}
+
+func (s *memoryStore) Count(ctx context.Context, kind, namespace string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if namespace == NamespaceAll {
+		return len(s.data[kind]), nil
+	}
+	n := 0
+	for _, res := range s.data[kind] {
+		if res.GetMetadata().GetNamespace() == namespace {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *memoryStore) Kinds(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	kinds := make([]string, 0, len(s.data))
+	for kind, byName := range s.data {
+		if len(byName) == 0 {
+			continue
+		}
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds, nil
+}
+
+func (s *memoryStore) ListAll(ctx context.Context) (map[string][]*pb.Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[string][]*pb.Resource, len(s.data))
+	for kind, byName := range s.data {
+		if len(byName) == 0 {
+			continue
+		}
+		resources := make([]*pb.Resource, 0, len(byName))
+		for _, res := range byName {
+			resources = append(resources, proto.Clone(res).(*pb.Resource))
+		}
+		sort.Slice(resources, func(i, j int) bool {
+			if a, b := resources[i].GetMetadata().GetNamespace(), resources[j].GetMetadata().GetNamespace(); a != b {
+				return a < b
+			}
+			return resources[i].GetMetadata().GetName() < resources[j].GetMetadata().GetName()
+		})
+		out[kind] = resources
+	}
+	return out, nil
+}
+
+func (s *memoryStore) Replace(ctx context.Context, kind string, desired []*pb.Resource) error {
+	return s.Transaction(ctx, func(tx Store) error {
+		return replaceAll(ctx, tx, kind, desired)
+	})
+}
+
+func (s *memoryStore) Patch(ctx context.Context, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error) {
+	var out *pb.Resource
+	err := s.Transaction(ctx, func(tx Store) error {
+		var err error
+		out, err = patchResource(ctx, tx, kind, namespace, name, patch, patchType)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *memoryStore) ListPage(ctx context.Context, kind, namespace string, opts PageOptions) (ListResult, error) {
+	if err := ctx.Err(); err != nil {
+		return ListResult{}, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.data[kind]))
+	for key, res := range s.data[kind] {
+		if namespace != NamespaceAll && res.GetMetadata().GetNamespace() != namespace {
+			continue
+		}
+		// The continue token is the last key of the previous page; keys
+		// at or before it have already been returned.
+		if opts.Continue == "" || key > opts.Continue {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var result ListResult
+	for i, key := range keys {
+		if opts.Limit > 0 && i == opts.Limit {
+			result.Continue = keys[i-1]
+			break
+		}
+		result.Resources = append(result.Resources, proto.Clone(s.data[kind][key]).(*pb.Resource))
+	}
+	return result, nil
+}
+
+func (s *memoryStore) Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.watch(ctx, kind, namespace, "", opts...)
+}
+
+func (s *memoryStore) WatchResource(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error) {
+	return s.watch(ctx, kind, namespace, name, opts...)
+}
+
+// watch implements Watch and WatchResource; a non-empty name narrows
+// delivery, the snapshot, and any resume replay to that one resource.
+func (s *memoryStore) watch(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var options watchOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	buffer := watchBufferSize
+	if options.bufferSet {
+		if options.bufferSize <= 0 {
+			return nil, fmt.Errorf("watch buffer size must be positive, got %d", options.bufferSize)
+		}
+		buffer = options.bufferSize
+	}
+	if options.rateLimit != 0 || options.rateBurst != 0 {
+		if options.rateLimit <= 0 || options.rateBurst <= 0 {
+			return nil, fmt.Errorf("watch rate limit and burst must be positive, got %g and %d",
+				options.rateLimit, options.rateBurst)
+		}
+	}
+	if options.resync < 0 {
+		return nil, fmt.Errorf("watch resync interval must be positive, got %v", options.resync)
+	}
+
+	// Mutations hold s.mu across both the write and the notify, so
+	// taking it here makes the snapshot and the registration atomic: a
+	// concurrent Create either lands in the snapshot below or is
+	// delivered as an event once the watcher is in s.watchers. There is
+	// no window in which it could be missed or seen twice.
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, &ClosedError{Op: "watch"}
+	}
+	s.watcherSeq++
+	w := &watcher{id: s.watcherSeq, kind: kind, namespace: namespace, name: name, ch: make(chan Event, buffer)}
+	// The snapshot is captured into a slice rather than sent on w.ch so
+	// a snapshot larger than the buffer cannot block while the lock is
+	// held; it is delivered ahead of the live events below.
+	var snapshot []Event
+	if options.initialState {
+		for _, res := range s.data[kind] {
+			if namespace != NamespaceAll && res.GetMetadata().GetNamespace() != namespace {
+				continue
+			}
+			if name != "" && res.GetMetadata().GetName() != name {
+				continue
+			}
+			snapshot = append(snapshot, Event{Type: EventCreated, Resource: proto.Clone(res).(*pb.Resource)})
+		}
+	}
+	if options.resumeSet {
+		for _, ev := range s.history {
+			if ev.Seq <= options.resumeFrom {
+				continue
+			}
+			md := ev.Resource.GetMetadata()
+			if md.GetKind() != kind {
+				continue
+			}
+			if namespace != NamespaceAll && md.GetNamespace() != namespace {
+				continue
+			}
+			if name != "" && md.GetName() != name {
+				continue
+			}
+			snapshot = append(snapshot, ev)
+		}
+	}
+	s.watchers[kind] = append(s.watchers[kind], w)
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.removeWatcher(w)
+	}()
+	ch := (<-chan Event)(w.ch)
+	if snapshot != nil {
+		out := make(chan Event, buffer)
+		go func() {
+			defer close(out)
+			for _, ev := range snapshot {
+				if !sendEvent(ctx, out, ev) {
+					return
+				}
+			}
+			// w.ch closes once the watch is cancelled or the store shuts
+			// down; events queued before the close drain through first.
+			for ev := range w.ch {
+				if !sendEvent(ctx, out, ev) {
+					return
+				}
+			}
+		}()
+		ch = out
+	}
+	if options.resync > 0 {
+		ch = s.resyncEvents(ctx, ch, kind, namespace, name, options.resync)
+	}
+	if options.predicate != nil {
+		ch = filterEvents(ctx, ch, options.predicate)
+	}
+	if options.coalesce {
+		ch = coalesceEvents(ctx, ch, &s.coalesced)
+	}
+	if options.rateLimit > 0 {
+		ch = paceEvents(ctx, ch, options.rateLimit, options.rateBurst)
+	}
+	return ch, nil
+}
+
+func (s *memoryStore) WatchKinds(ctx context.Context, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return watchKinds(ctx, s, kinds, namespace, opts...)
+}
+
+func (s *memoryStore) WatchAll(ctx context.Context, opts ...WatchOption) (<-chan Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var options watchOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	buffer := watchBufferSize
+	if options.bufferSet {
+		if options.bufferSize <= 0 {
+			return nil, fmt.Errorf("watch buffer size must be positive, got %d", options.bufferSize)
+		}
+		buffer = options.bufferSize
+	}
+	if options.rateLimit != 0 || options.rateBurst != 0 {
+		if options.rateLimit <= 0 || options.rateBurst <= 0 {
+			return nil, fmt.Errorf("watch rate limit and burst must be positive, got %g and %d",
+				options.rateLimit, options.rateBurst)
+		}
+	}
+	if options.resync < 0 {
+		return nil, fmt.Errorf("watch resync interval must be positive, got %v", options.resync)
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, &ClosedError{Op: "watch"}
+	}
+	s.watcherSeq++
+	w := &watcher{id: s.watcherSeq, namespace: NamespaceAll, ch: make(chan Event, buffer)}
+	var snapshot []Event
+	if options.initialState {
+		for _, byName := range s.data {
+			for _, res := range byName {
+				snapshot = append(snapshot, Event{Type: EventCreated, Resource: proto.Clone(res).(*pb.Resource)})
+			}
+		}
+	}
+	if options.resumeSet {
+		for _, ev := range s.history {
+			if ev.Seq <= options.resumeFrom {
+				continue
+			}
+			snapshot = append(snapshot, ev)
+		}
+	}
+	s.allWatchers = append(s.allWatchers, w)
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.removeWatcher(w)
+	}()
+	ch := (<-chan Event)(w.ch)
+	if snapshot != nil {
+		out := make(chan Event, buffer)
+		go func() {
+			defer close(out)
+			for _, ev := range snapshot {
+				if !sendEvent(ctx, out, ev) {
+					return
+				}
+			}
+			// w.ch closes once the watch is cancelled or the store shuts
+			// down; events queued before the close drain through first.
+			for ev := range w.ch {
+				if !sendEvent(ctx, out, ev) {
+					return
+				}
+			}
+		}()
+		ch = out
+	}
+	if options.resync > 0 {
+		ch = s.resyncEvents(ctx, ch, "", NamespaceAll, "", options.resync)
+	}
+	if options.predicate != nil {
+		ch = filterEvents(ctx, ch, options.predicate)
+	}
+	if options.coalesce {
+		ch = coalesceEvents(ctx, ch, &s.coalesced)
+	}
+	if options.rateLimit > 0 {
+		ch = paceEvents(ctx, ch, options.rateLimit, options.rateBurst)
+	}
+	return ch, nil
+}
+
+func (s *memoryStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return &ClosedError{Op: "transaction"}
+	}
+
+	// Run fn against a scratch copy of the data so a failure leaves the
+	// store untouched. The store's lock is held for the whole
+	// transaction, which is what makes the commit atomic.
+	var events []Event
+	scratch := &memoryStore{
+		data:       make(map[string]map[string]*pb.Resource, len(s.data)),
+		watchers:   make(map[string][]*watcher),
+		indexes:    make(map[string]map[string]func(*pb.Resource) []string),
+		index:      make(map[string]map[string]map[string]map[string]bool),
+		admissions: s.admissions,
+		clock:      s.clock,
+		logger:     s.logger,
+		pending:    &events,
+	}
+	for kind, byName := range s.data {
+		scratch.data[kind] = make(map[string]*pb.Resource, len(byName))
+		for name, res := range byName {
+			scratch.data[kind][name] = proto.Clone(res).(*pb.Resource)
+		}
+	}
+
+	if err := fn(scratch); err != nil {
+		return err
+	}
+	s.data = scratch.data
+	s.rebuildIndexes()
+	for _, ev := range events {
+		s.notify(ev)
+	}
+	return nil
+}
+
+func (s *memoryStore) AddIndex(kind, indexName string, fn func(*pb.Resource) []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.indexes[kind][indexName] != nil {
+		return fmt.Errorf("index %s already exists for kind %s", indexName, kind)
+	}
+	if s.indexes[kind] == nil {
+		s.indexes[kind] = make(map[string]func(*pb.Resource) []string)
+	}
+	s.indexes[kind][indexName] = fn
+	// Index the resources that already exist.
+	for _, res := range s.data[kind] {
+		s.indexAdd(res)
+	}
+	return nil
+}
+
+func (s *memoryStore) RegisterAdmission(kind string, fn AdmissionFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.admissions[kind] = append(s.admissions[kind], fn)
+}
+
+// admit runs the kind's admission hooks in registration order. The
+// resource handed to the hooks is a clone of res, so in-place mutations
+// stay invisible to the caller; the possibly mutated clone is returned
+// for the store to persist.
+func (s *memoryStore) admit(ctx context.Context, op AdmissionOp, kind string, old, res *pb.Resource) (*pb.Resource, error) {
+	s.mu.RLock()
+	hooks := s.admissions[kind]
+	s.mu.RUnlock()
+	if len(hooks) == 0 {
+		return res, nil
+	}
+	if res != nil {
+		res = proto.Clone(res).(*pb.Resource)
+	}
+	for _, fn := range hooks {
+		if err := fn(ctx, op, old, res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (s *memoryStore) GetByIndex(ctx context.Context, kind, indexName, value string) ([]*pb.Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.indexes[kind][indexName] == nil {
+		return nil, fmt.Errorf("no index %s for kind %s", indexName, kind)
+	}
+	keys := s.index[kind][indexName][value]
+	out := make([]*pb.Resource, 0, len(keys))
+	for key := range keys {
+		out = append(out, proto.Clone(s.data[kind][key]).(*pb.Resource))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].GetMetadata().GetName() < out[j].GetMetadata().GetName()
+	})
+	return out, nil
+}
+
+func (s *memoryStore) SetObservedGeneration(ctx context.Context, kind, namespace, name string, gen int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return &ClosedError{Op: "set observed generation"}
+	}
+
+	res, ok := s.data[kind][nsKey(namespace, name)]
+	if !ok {
+		return &NotFoundError{Kind: kind, Name: name}
+	}
+	if res.GetMetadata().GetObservedGeneration() == gen {
+		return nil
+	}
+	// Observed generation is bookkeeping, not a spec change: it does not
+	// bump the generation, but watchers still see the update so a
+	// dashboard can track convergence.
+	res.Metadata.ObservedGeneration = gen
+	s.notify(Event{Type: EventUpdated, Resource: proto.Clone(res).(*pb.Resource)})
+	return nil
+}
+
+// PreviousOwnerAnnotation records the owner a resource was reassigned
+// away from by TransferOwner, so forced takeovers stay auditable.
+const PreviousOwnerAnnotation = "microrun.io/previous-owner"
+
+func (s *memoryStore) TransferOwner(ctx context.Context, kind, namespace, name, owner string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return &ClosedError{Op: "transfer owner"}
+	}
+
+	res, ok := s.data[kind][nsKey(namespace, name)]
+	if !ok {
+		return &NotFoundError{Kind: kind, Name: name}
+	}
+	prev := res.GetMetadata().GetOwner()
+	if prev == owner {
+		return nil
+	}
+	if res.Metadata.Annotations == nil {
+		res.Metadata.Annotations = make(map[string]string)
+	}
+	res.Metadata.Annotations[PreviousOwnerAnnotation] = prev
+	res.Metadata.Owner = owner
+	s.logger.Debug("transferred resource ownership", "kind", kind, "name", name,
+		"from", prev, "to", owner)
+	s.notify(Event{Type: EventUpdated, Resource: proto.Clone(res).(*pb.Resource)})
+	return nil
+}
+
+// Close shuts the store down. All watcher channels close, and further
+// mutations and watches fail with a ClosedError. Close is idempotent.
+func (s *memoryStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	for _, watchers := range s.watchers {
+		for _, w := range watchers {
+			close(w.ch)
+		}
+	}
+	for _, w := range s.allWatchers {
+		close(w.ch)
+	}
+	s.watchers = make(map[string][]*watcher)
+	s.allWatchers = nil
+	if s.wal != nil {
+		return s.wal.file.Close()
+	}
+	return nil
+}
+
+// indexAdd records res under every registered index of its kind. The
+// caller must hold s.mu.
+func (s *memoryStore) indexAdd(res *pb.Resource) {
+	kind := res.GetMetadata().GetKind()
+	key := nsKey(res.GetMetadata().GetNamespace(), res.GetMetadata().GetName())
+	for indexName, fn := range s.indexes[kind] {
+		for _, value := range fn(res) {
+			if s.index[kind] == nil {
+				s.index[kind] = make(map[string]map[string]map[string]bool)
+			}
+			if s.index[kind][indexName] == nil {
+				s.index[kind][indexName] = make(map[string]map[string]bool)
+			}
+			if s.index[kind][indexName][value] == nil {
+				s.index[kind][indexName][value] = make(map[string]bool)
+			}
+			s.index[kind][indexName][value][key] = true
+		}
+	}
+}
+
+// indexRemove drops res from every registered index of its kind. The
+// caller must hold s.mu.
+func (s *memoryStore) indexRemove(res *pb.Resource) {
+	kind := res.GetMetadata().GetKind()
+	key := nsKey(res.GetMetadata().GetNamespace(), res.GetMetadata().GetName())
+	for indexName, fn := range s.indexes[kind] {
+		for _, value := range fn(res) {
+			delete(s.index[kind][indexName][value], key)
+			if len(s.index[kind][indexName][value]) == 0 {
+				delete(s.index[kind][indexName], value)
+			}
+		}
+	}
+}
+
+// rebuildIndexes re-derives every index from the current data, used after
+// a transaction replaces the data wholesale. The caller must hold s.mu.
+func (s *memoryStore) rebuildIndexes() {
+	s.index = make(map[string]map[string]map[string]map[string]bool)
+	for kind := range s.indexes {
+		for _, res := range s.data[kind] {
+			s.indexAdd(res)
+		}
+	}
+}
+
+func (s *memoryStore) removeWatcher(w *watcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ws := s.watchers[w.kind]
+	if w.kind == "" {
+		ws = s.allWatchers
+	}
+	for i, cand := range ws {
+		if cand == w {
+			if w.kind == "" {
+				s.allWatchers = append(ws[:i], ws[i+1:]...)
+			} else {
+				s.watchers[w.kind] = append(ws[:i], ws[i+1:]...)
+			}
+			close(w.ch)
+			return
+		}
+	}
+}
+
+// notify delivers an event to every watcher of the resource's kind and to
+// every all-kinds watcher. The caller must hold s.mu.
+// WatchStats implements WatchStatsProvider, snapshotting every active
+// watcher's queue occupancy.
+func (s *memoryStore) WatchStats() []WatcherStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var stats []WatcherStats
+	for kind, ws := range s.watchers {
+		for _, w := range ws {
+			stats = append(stats, WatcherStats{
+				ID: w.id, Kind: kind, Namespace: w.namespace,
+				QueueLen: len(w.ch), QueueCap: cap(w.ch),
+			})
+		}
+	}
+	for _, w := range s.allWatchers {
+		stats = append(stats, WatcherStats{
+			ID: w.id, Kind: allKindsLabel, Namespace: w.namespace,
+			QueueLen: len(w.ch), QueueCap: cap(w.ch),
+		})
+	}
+	return stats
+}
+
+// CoalescedEvents implements WatchStatsProvider.
+func (s *memoryStore) CoalescedEvents() uint64 {
+	return s.coalesced.Load()
+}
+
+func (s *memoryStore) notify(ev Event) {
+	if s.pending != nil {
+		*s.pending = append(*s.pending, ev)
+		return
+	}
+	s.seq++
+	ev.Seq = s.seq
+	if s.wal != nil {
+		if err := s.wal.record(ev); err != nil {
+			s.logger.Error("appending to write-ahead log", "error", err)
+		}
+		if s.wal.appends >= walCompactThreshold {
+			if err := s.wal.compact(s.data); err != nil {
+				s.logger.Error("compacting write-ahead log", "error", err)
+			}
+		}
+	}
+	s.history = append(s.history, ev)
+	if len(s.history) > eventHistorySize {
+		s.history = s.history[len(s.history)-eventHistorySize:]
+	}
+	if s.batching {
+		s.batched = append(s.batched, ev)
+		return
+	}
+	s.deliver(ev, nil)
+}
+
+// deliver sends ev to every watcher it matches, recording the recipients
+// in seen when non-nil. The caller must hold s.mu.
+func (s *memoryStore) deliver(ev Event, seen map[*watcher]bool) {
+	kind := ev.Resource.GetMetadata().GetKind()
+	namespace := ev.Resource.GetMetadata().GetNamespace()
+	for _, w := range s.watchers[kind] {
+		if w.namespace != NamespaceAll && w.namespace != namespace {
+			continue
+		}
+		if w.name != "" && w.name != ev.Resource.GetMetadata().GetName() {
+			continue
+		}
+		w.ch <- ev
+		if seen != nil {
+			seen[w] = true
+		}
+	}
+	for _, w := range s.allWatchers {
+		w.ch <- ev
+		if seen != nil {
+			seen[w] = true
+		}
+	}
+}
+
+// BeginBatch implements Store. Mutations keep committing (and keep
+// hitting the WAL, history, and sequence counter) while the batch is
+// open; only watcher delivery waits for CommitBatch.
+func (s *memoryStore) BeginBatch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batching = true
+}
+
+// CommitBatch implements Store. The held events collapse to the last one
+// per resource before delivery, so watchers never see the intermediate
+// states; the barrier carries the batch's final sequence number and no
+// resource.
+func (s *memoryStore) CommitBatch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.batching {
+		return
+	}
+	s.batching = false
+	events := coalesceBatch(s.batched)
+	s.batched = nil
+	if s.closed {
+		return
+	}
+	if len(events) == 0 {
+		return
+	}
+	seen := make(map[*watcher]bool)
+	for _, ev := range events {
+		s.deliver(ev, seen)
+	}
+	barrier := Event{Type: EventBatchEnd, Seq: events[len(events)-1].Seq}
+	for w := range seen {
+		w.ch <- barrier
+	}
+}
+
+// resyncEvents merges periodic synthetic updates into a watch stream:
+// every interval, the current state of each matching resource is
+// delivered as an EventUpdated with a zero sequence number. The ticker
+// comes from the store's clock, so tests drive resyncs with a fake.
+func (s *memoryStore) resyncEvents(ctx context.Context, in <-chan Event, kind, namespace, name string, interval time.Duration) <-chan Event {
+	out := make(chan Event, watchBufferSize)
+	ticker := s.clock.Ticker(interval)
+	go func() {
+		defer close(out)
+		defer ticker.Stop()
+		for {
+			select {
+			case ev, ok := <-in:
+				if !ok {
+					return
+				}
+				if !sendEvent(ctx, out, ev) {
+					return
+				}
+			case <-ticker.C():
+				for _, ev := range s.resyncSnapshot(kind, namespace, name) {
+					if !sendEvent(ctx, out, ev) {
+						return
+					}
+				}
+			case <-ctx.Done():
+				// in closes shortly after cancellation; drain what is
+				// already queued before closing out.
+				for ev := range in {
+					if !sendEvent(ctx, out, ev) {
+						return
+					}
+				}
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// resyncSnapshot clones the resources a watch on kind, namespace, and
+// name currently matches, as synthetic update events. An empty kind
+// matches every kind.
+func (s *memoryStore) resyncSnapshot(kind, namespace, name string) []Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []Event
+	for k, byName := range s.data {
+		if kind != "" && k != kind {
+			continue
+		}
+		for _, res := range byName {
+			if namespace != NamespaceAll && res.GetMetadata().GetNamespace() != namespace {
+				continue
+			}
+			if name != "" && res.GetMetadata().GetName() != name {
+				continue
+			}
+			out = append(out, Event{Type: EventUpdated, Resource: proto.Clone(res).(*pb.Resource)})
+		}
+	}
+	return out
+}
+
+// coalesceBatch keeps only the last event per resource, in the order of
+// each resource's last occurrence.
+func coalesceBatch(events []Event) []Event {
+	last := make(map[string]int, len(events))
+	for i, ev := range events {
+		meta := ev.Resource.GetMetadata()
+		last[meta.GetKind()+"/"+nsKey(meta.GetNamespace(), meta.GetName())] = i
+	}
+	out := make([]Event, 0, len(last))
+	for i, ev := range events {
+		meta := ev.Resource.GetMetadata()
+		if last[meta.GetKind()+"/"+nsKey(meta.GetNamespace(), meta.GetName())] == i {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+// nsKey builds the map key a resource is stored under: names are only
+// unique within a namespace.
+func nsKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// newUID returns a random RFC 4122 version 4 UUID. crypto/rand failing
+// indicates a broken platform, so it panics rather than returning an
+// error every caller would have to ignore.
+func newUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("reading random bytes for UID: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func validateMetadata(res *pb.Resource) error {
+	if res.GetMetadata() == nil {
+		return fmt.Errorf("resource has no metadata")
+	}
+	if res.GetMetadata().GetName() == "" {
+		return fmt.Errorf("resource has no name")
+	}
+	if res.GetMetadata().GetKind() == "" {
+		return fmt.Errorf("resource has no kind")
+	}
+	return nil
+}
+
+// validateSpecKind checks that the resource's declared kind agrees with
+// the spec oneof that is actually populated, so a resource can never be
+// stored in a form its kind's ExtractSpec cannot read back.
+func validateSpecKind(res *pb.Resource) error {
+	spec, err := api.ExtractSpec(res)
+	if err != nil {
+		return fmt.Errorf("resource %s/%s: %w",
+			res.GetMetadata().GetKind(), res.GetMetadata().GetName(), err)
+	}
+	kind, err := api.KindFor(spec)
+	if err != nil {
+		return err
+	}
+	if declared := res.GetMetadata().GetKind(); declared != kind {
+		return fmt.Errorf("resource %s/%s has a %s spec; kind and spec must agree",
+			declared, res.GetMetadata().GetName(), kind)
+	}
+	return nil
+}
+
+// specHash returns a content hash of the resource's spec, ignoring
+// metadata and status. The store sets it on every write so two resources
+// with equal hashes are guaranteed to carry the same desired state.
+func specHash(res *pb.Resource) string {
+	stripped := proto.Clone(res).(*pb.Resource)
+	stripped.Metadata = nil
+	stripped.Status = nil
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(stripped)
+	if err != nil {
+		// Marshaling a cloned, validated resource cannot fail; an empty
+		// hash here would only mask a proto library bug.
+		panic(err)
+	}
+	h := fnv.New64a()
+	h.Write(data)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// specChanged reports whether the spec oneof differs between the two
+// resources, ignoring metadata and status. Generation tracks the spec, so
+// bookkeeping changes such as finalizers or observed status must not look
+// like new desired state.
+func specChanged(old, new *pb.Resource) bool {
+	oldStripped := proto.Clone(old).(*pb.Resource)
+	newStripped := proto.Clone(new).(*pb.Resource)
+	oldStripped.Metadata = nil
+	newStripped.Metadata = nil
+	oldStripped.Status = nil
+	newStripped.Status = nil
+	return !proto.Equal(oldStripped, newStripped)
+}
@@ -0,0 +1,2004 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"github.com/microrun/microrun/clock"
+	"google.golang.org/protobuf/proto"
+)
+
+func testInterface(name, mac string) *pb.Resource {
+	return &pb.Resource{
+		Metadata: &pb.ResourceMetadata{Name: name, Kind: api.KindNetworkInterface},
+		Spec: &pb.Resource_NetworkInterface{NetworkInterface: &pb.NetworkInterface{
+			InterfaceName: name,
+			MacAddress:    mac,
+		}},
+	}
+}
+
+func TestMemoryStoreCreateGet(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	got, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.GetMetadata().GetGeneration() != 1 {
+		t.Errorf("generation = %d, want 1", got.GetMetadata().GetGeneration())
+	}
+	if mac := got.GetNetworkInterface().GetMacAddress(); mac != "aa:bb:cc:dd:ee:ff" {
+		t.Errorf("mac = %q", mac)
+	}
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err == nil {
+		t.Error("duplicate Create succeeded, want error")
+	}
+	if _, err := store.Get(ctx, api.KindNetworkInterface, "", "missing"); err == nil {
+		t.Error("Get of missing resource succeeded, want error")
+	}
+}
+
+func TestMemoryStoreCreateValidatesSpecKind(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	// Kind says NetworkInterface but the spec is a DHCPClient.
+	mismatched := &pb.Resource{
+		Metadata: &pb.ResourceMetadata{Name: "eth0", Kind: api.KindNetworkInterface},
+		Spec:     &pb.Resource_DhcpClient{DhcpClient: &pb.DHCPClient{InterfaceRef: "eth0"}},
+	}
+	if err := store.Create(ctx, mismatched); err == nil {
+		t.Error("Create with mismatched kind and spec succeeded, want error")
+	}
+
+	// No spec at all.
+	missing := &pb.Resource{
+		Metadata: &pb.ResourceMetadata{Name: "eth0", Kind: api.KindNetworkInterface},
+	}
+	if err := store.Create(ctx, missing); err == nil {
+		t.Error("Create without a spec succeeded, want error")
+	}
+
+	// A consistent resource is accepted, and Update enforces the same check.
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.Spec = &pb.Resource_DhcpClient{DhcpClient: &pb.DHCPClient{InterfaceRef: "eth0"}}
+	if err := store.Update(ctx, res); err == nil {
+		t.Error("Update with mismatched kind and spec succeeded, want error")
+	}
+}
+
+func TestMemoryStoreUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.GetNetworkInterface().IpAddresses = []string{"192.0.2.1/24"}
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.GetMetadata().GetGeneration() != 2 {
+		t.Errorf("generation = %d, want 2", got.GetMetadata().GetGeneration())
+	}
+
+	// An identical update is a no-op and must not bump the generation.
+	if err := store.Update(ctx, got); err != nil {
+		t.Fatalf("no-op Update: %v", err)
+	}
+	again, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if again.GetMetadata().GetGeneration() != 2 {
+		t.Errorf("generation after no-op = %d, want 2", again.GetMetadata().GetGeneration())
+	}
+}
+
+func TestMemoryStoreUpdateConflict(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	stale, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	fresh := proto.Clone(stale).(*pb.Resource)
+	fresh.GetNetworkInterface().MacAddress = "11:22:33:44:55:66"
+	if err := store.Update(ctx, fresh); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	stale.GetNetworkInterface().MacAddress = "66:55:44:33:22:11"
+	err = store.Update(ctx, stale)
+	if _, ok := err.(*ConflictError); !ok {
+		t.Errorf("stale Update returned %v, want *ConflictError", err)
+	}
+}
+
+func TestMemoryStoreDelete(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0"); err == nil {
+		t.Error("Get after Delete succeeded, want error")
+	}
+	if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0"); err == nil {
+		t.Error("second Delete succeeded, want error")
+	}
+}
+
+func TestMemoryStoreSoftDeleteWithFinalizers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	res := testInterface("eth0", "aa:bb:cc:dd:ee:ff")
+	res.Metadata.Finalizers = []string{"network-interfaces/cleanup"}
+	if err := store.Create(ctx, res); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	ch, err := store.Watch(ctx, api.KindNetworkInterface, "")
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	// Deleting a finalized resource marks it instead of removing it.
+	if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if ev := <-ch; ev.Type != EventUpdated {
+		t.Errorf("soft delete emitted %v, want updated", ev.Type)
+	}
+	got, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get after soft delete: %v", err)
+	}
+	if got.GetMetadata().GetDeletionTimestamp() == nil {
+		t.Fatal("deletion timestamp not set on finalized resource")
+	}
+	list, err := store.List(ctx, api.KindNetworkInterface, "")
+	if err != nil || len(list) != 1 {
+		t.Fatalf("soft-deleted resource not listable: %v (%d)", err, len(list))
+	}
+
+	// A second Delete is a no-op.
+	if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0"); err != nil {
+		t.Fatalf("second Delete: %v", err)
+	}
+
+	// Clearing the finalizers completes the delete.
+	got.Metadata.Finalizers = nil
+	if err := store.Update(ctx, got); err != nil {
+		t.Fatalf("Update clearing finalizers: %v", err)
+	}
+	if ev := <-ch; ev.Type != EventDeleted {
+		t.Errorf("finalizer clear emitted %v, want deleted", ev.Type)
+	}
+	if _, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0"); err == nil {
+		t.Error("resource still present after finalizers cleared")
+	}
+}
+
+func TestMemoryStoreList(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	for _, name := range []string{"eth0", "eth1"} {
+		if err := store.Create(ctx, testInterface(name, "aa:bb:cc:dd:ee:ff")); err != nil {
+			t.Fatalf("Create %s: %v", name, err)
+		}
+	}
+	list, err := store.List(ctx, api.KindNetworkInterface, "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("List returned %d resources, want 2", len(list))
+	}
+
+	// The returned resources are deep copies.
+	list[0].GetNetworkInterface().MacAddress = "mutated"
+	got, err := store.Get(ctx, api.KindNetworkInterface, "", list[0].GetMetadata().GetName())
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.GetNetworkInterface().GetMacAddress() == "mutated" {
+		t.Error("mutating a listed resource changed the store")
+	}
+}
+
+func TestMemoryStoreListSorted(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	for _, name := range []string{"eth2", "eth0", "wg0", "eth1"} {
+		if err := store.Create(ctx, testInterface(name, "aa:bb:cc:dd:ee:ff")); err != nil {
+			t.Fatalf("Create %s: %v", name, err)
+		}
+	}
+	list, err := store.List(ctx, api.KindNetworkInterface, "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	want := []string{"eth0", "eth1", "eth2", "wg0"}
+	for i, res := range list {
+		if got := res.GetMetadata().GetName(); got != want[i] {
+			t.Errorf("list[%d] = %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestMemoryStoreListPage(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	want := map[string]bool{}
+	for i := 0; i < 25; i++ {
+		name := fmt.Sprintf("eth%02d", i)
+		want[name] = true
+		if err := store.Create(ctx, testInterface(name, "aa:bb:cc:dd:ee:ff")); err != nil {
+			t.Fatalf("Create %s: %v", name, err)
+		}
+	}
+
+	got := map[string]bool{}
+	token := ""
+	pages := 0
+	for {
+		page, err := store.ListPage(ctx, api.KindNetworkInterface, "", PageOptions{Limit: 10, Continue: token})
+		if err != nil {
+			t.Fatalf("ListPage: %v", err)
+		}
+		pages++
+		prev := ""
+		for _, res := range page.Resources {
+			name := res.GetMetadata().GetName()
+			if got[name] {
+				t.Errorf("resource %s returned twice", name)
+			}
+			if name <= prev {
+				t.Errorf("page not sorted: %s after %s", name, prev)
+			}
+			prev = name
+			got[name] = true
+		}
+		if page.Continue == "" {
+			break
+		}
+		token = page.Continue
+	}
+	if pages != 3 {
+		t.Errorf("paged through in %d pages, want 3", pages)
+	}
+	if len(got) != len(want) {
+		t.Errorf("paging returned %d resources, want %d", len(got), len(want))
+	}
+}
+
+func TestMemoryStoreCancelledContext(t *testing.T) {
+	store := NewMemoryStore()
+	if err := store.Create(context.Background(), testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := store.Create(cancelled, testInterface("eth1", "aa:bb:cc:dd:ee:ff")); err != context.Canceled {
+		t.Errorf("Create = %v, want context.Canceled", err)
+	}
+	if _, err := store.Get(cancelled, api.KindNetworkInterface, "", "eth0"); err != context.Canceled {
+		t.Errorf("Get = %v, want context.Canceled", err)
+	}
+	res, _ := store.Get(context.Background(), api.KindNetworkInterface, "", "eth0")
+	res.GetNetworkInterface().MacAddress = "11:22:33:44:55:66"
+	if err := store.Update(cancelled, res); err != context.Canceled {
+		t.Errorf("Update = %v, want context.Canceled", err)
+	}
+	if err := store.Delete(cancelled, api.KindNetworkInterface, "", "eth0"); err != context.Canceled {
+		t.Errorf("Delete = %v, want context.Canceled", err)
+	}
+	if _, err := store.List(cancelled, api.KindNetworkInterface, ""); err != context.Canceled {
+		t.Errorf("List = %v, want context.Canceled", err)
+	}
+	if _, err := store.Watch(cancelled, api.KindNetworkInterface, ""); err != context.Canceled {
+		t.Errorf("Watch = %v, want context.Canceled", err)
+	}
+
+	// Nothing was mutated by the cancelled calls.
+	got, err := store.Get(context.Background(), api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.GetMetadata().GetGeneration() != 1 || got.GetNetworkInterface().GetMacAddress() != "aa:bb:cc:dd:ee:ff" {
+		t.Error("cancelled operations mutated state")
+	}
+	if _, err := store.Get(context.Background(), api.KindNetworkInterface, "", "eth1"); err == nil {
+		t.Error("cancelled Create persisted a resource")
+	}
+}
+
+func TestMemoryStoreWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	ch, err := store.Watch(ctx, api.KindNetworkInterface, "")
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	ev := <-ch
+	if ev.Type != EventCreated || ev.Resource.GetMetadata().GetName() != "eth0" {
+		t.Errorf("got event %v %s", ev.Type, ev.Resource.GetMetadata().GetName())
+	}
+
+	res, _ := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	res.GetNetworkInterface().IpAddresses = []string{"192.0.2.1/24"}
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if ev := <-ch; ev.Type != EventUpdated {
+		t.Errorf("got event type %v, want updated", ev.Type)
+	}
+
+	if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if ev := <-ch; ev.Type != EventDeleted {
+		t.Errorf("got event type %v, want deleted", ev.Type)
+	}
+}
+
+func TestMemoryStoreWatchKinds(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	ch, err := store.WatchKinds(ctx, []string{api.KindNetworkInterface, api.KindDHCPClient}, "")
+	if err != nil {
+		t.Fatalf("WatchKinds: %v", err)
+	}
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create interface: %v", err)
+	}
+	dhcp := &pb.Resource{
+		Metadata: &pb.ResourceMetadata{Name: "eth0-dhcp", Kind: api.KindDHCPClient},
+		Spec:     &pb.Resource_DhcpClient{DhcpClient: &pb.DHCPClient{InterfaceRef: "eth0"}},
+	}
+	if err := store.Create(ctx, dhcp); err != nil {
+		t.Fatalf("Create dhcp client: %v", err)
+	}
+
+	got := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		ev := <-ch
+		got[ev.Resource.GetMetadata().GetKind()] = true
+	}
+	if !got[api.KindNetworkInterface] || !got[api.KindDHCPClient] {
+		t.Errorf("merged watch saw kinds %v, want both", got)
+	}
+
+	// Cancellation closes the merged channel.
+	cancel()
+	for range ch {
+	}
+}
+
+func TestMemoryStoreTypedErrors(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	var notFound *NotFoundError
+	if _, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0"); !errors.As(err, &notFound) {
+		t.Errorf("Get error = %v, want NotFoundError", err)
+	} else if notFound.Kind != api.KindNetworkInterface || notFound.Name != "eth0" {
+		t.Errorf("NotFoundError identifies %s/%s", notFound.Kind, notFound.Name)
+	}
+	if err := store.Update(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); !IsNotFound(err) {
+		t.Errorf("Update error = %v, want not found", err)
+	}
+	if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0"); !IsNotFound(err) {
+		t.Errorf("Delete error = %v, want not found", err)
+	}
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff"))
+	if !IsAlreadyExists(err) {
+		t.Errorf("duplicate Create error = %v, want already exists", err)
+	}
+	// The messages are unchanged for callers that still match on them.
+	if got := err.Error(); got != "resource NetworkInterface/eth0 already exists" {
+		t.Errorf("already-exists message = %q", got)
+	}
+}
+
+func TestMemoryStoreSetObservedGeneration(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.SetObservedGeneration(ctx, api.KindNetworkInterface, "", "eth0", 1); err != nil {
+		t.Fatalf("SetObservedGeneration: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	// Recording the observed generation does not bump the generation.
+	if res.GetMetadata().GetGeneration() != 1 || res.GetMetadata().GetObservedGeneration() != 1 {
+		t.Errorf("generation = %d observed = %d, want 1 and 1",
+			res.GetMetadata().GetGeneration(), res.GetMetadata().GetObservedGeneration())
+	}
+
+	// A spec change bumps the generation but leaves the observed
+	// generation where the reconciler last set it.
+	res.GetNetworkInterface().IpAddresses = []string{"192.0.2.1/24"}
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	res, err = store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if res.GetMetadata().GetGeneration() != 2 || res.GetMetadata().GetObservedGeneration() != 1 {
+		t.Errorf("generation = %d observed = %d, want 2 and 1",
+			res.GetMetadata().GetGeneration(), res.GetMetadata().GetObservedGeneration())
+	}
+
+	// Callers cannot change the observed generation through Update.
+	res.Metadata.ObservedGeneration = 99
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update (observed generation): %v", err)
+	}
+	res, _ = store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if res.GetMetadata().GetObservedGeneration() != 1 {
+		t.Errorf("observed generation = %d after Update, want 1", res.GetMetadata().GetObservedGeneration())
+	}
+
+	if err := store.SetObservedGeneration(ctx, api.KindNetworkInterface, "", "missing", 1); err == nil {
+		t.Error("SetObservedGeneration succeeded for a missing resource")
+	}
+}
+
+func TestMemoryStoreIndex(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create eth0: %v", err)
+	}
+	// Resources existing before AddIndex are indexed too.
+	err := store.AddIndex(api.KindNetworkInterface, "mac", func(res *pb.Resource) []string {
+		return []string{res.GetNetworkInterface().GetMacAddress()}
+	})
+	if err != nil {
+		t.Fatalf("AddIndex: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create eth1: %v", err)
+	}
+
+	got, err := store.GetByIndex(ctx, api.KindNetworkInterface, "mac", "aa:bb:cc:dd:ee:ff")
+	if err != nil {
+		t.Fatalf("GetByIndex: %v", err)
+	}
+	if len(got) != 1 || got[0].GetMetadata().GetName() != "eth0" {
+		t.Fatalf("GetByIndex returned %v, want eth0", got)
+	}
+
+	// Updates move the resource to its new index value.
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.GetNetworkInterface().MacAddress = "11:22:33:44:55:66"
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got, _ := store.GetByIndex(ctx, api.KindNetworkInterface, "mac", "aa:bb:cc:dd:ee:ff"); len(got) != 0 {
+		t.Errorf("old index value still matches %v", got)
+	}
+	got, err = store.GetByIndex(ctx, api.KindNetworkInterface, "mac", "11:22:33:44:55:66")
+	if err != nil {
+		t.Fatalf("GetByIndex after update: %v", err)
+	}
+	if len(got) != 1 || got[0].GetMetadata().GetName() != "eth0" {
+		t.Errorf("GetByIndex after update returned %v, want eth0", got)
+	}
+
+	// Deletes drop the resource from the index.
+	if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if got, _ := store.GetByIndex(ctx, api.KindNetworkInterface, "mac", "aa:bb:cc:dd:ee:00"); len(got) != 0 {
+		t.Errorf("deleted resource still indexed: %v", got)
+	}
+
+	// Unknown indexes are an error.
+	if _, err := store.GetByIndex(ctx, api.KindNetworkInterface, "missing", "x"); err == nil {
+		t.Error("GetByIndex succeeded for an unregistered index")
+	}
+}
+
+func TestMemoryStoreTransactionCommit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	ch, err := store.WatchAll(ctx)
+	if err != nil {
+		t.Fatalf("WatchAll: %v", err)
+	}
+
+	err = store.Transaction(ctx, func(tx Store) error {
+		if err := tx.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+			return err
+		}
+		// Events must not be visible before the transaction commits.
+		select {
+		case ev := <-ch:
+			t.Errorf("event %v delivered before commit", ev.Type)
+		default:
+		}
+		return tx.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:00"))
+	})
+	if err != nil {
+		t.Fatalf("Transaction: %v", err)
+	}
+
+	for _, name := range []string{"eth0", "eth1"} {
+		if _, err := store.Get(ctx, api.KindNetworkInterface, "", name); err != nil {
+			t.Errorf("Get %s after commit: %v", name, err)
+		}
+		ev := <-ch
+		if ev.Type != EventCreated {
+			t.Errorf("event type = %v, want %v", ev.Type, EventCreated)
+		}
+	}
+}
+
+func TestMemoryStoreTransactionRollback(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	err := store.Transaction(ctx, func(tx Store) error {
+		if err := tx.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+			return err
+		}
+		// Creating the same resource twice fails and rolls everything
+		// back, including the first create.
+		return tx.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff"))
+	})
+	if err == nil {
+		t.Fatal("Transaction succeeded despite a failed create")
+	}
+	if _, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0"); err == nil {
+		t.Error("Get succeeded after rollback")
+	}
+}
+
+func TestMemoryStoreWatchAll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	ch, err := store.WatchAll(ctx)
+	if err != nil {
+		t.Fatalf("WatchAll: %v", err)
+	}
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create interface: %v", err)
+	}
+	dhcp := &pb.Resource{
+		Metadata: &pb.ResourceMetadata{Name: "eth0-dhcp", Kind: api.KindDHCPClient},
+		Spec:     &pb.Resource_DhcpClient{DhcpClient: &pb.DHCPClient{InterfaceRef: "eth0"}},
+	}
+	if err := store.Create(ctx, dhcp); err != nil {
+		t.Fatalf("Create dhcp client: %v", err)
+	}
+
+	got := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		ev := <-ch
+		if ev.Type != EventCreated {
+			t.Errorf("event %d type = %v, want %v", i, ev.Type, EventCreated)
+		}
+		got[ev.Resource.GetMetadata().GetKind()] = true
+	}
+	if !got[api.KindNetworkInterface] || !got[api.KindDHCPClient] {
+		t.Errorf("WatchAll saw kinds %v, want both", got)
+	}
+
+	// Cancellation closes the channel.
+	cancel()
+	for range ch {
+	}
+}
+
+func TestMemoryStoreWatchCoalescing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	ch, err := store.Watch(ctx, api.KindNetworkInterface, "", WithCoalescing())
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	// Update the resource many times without the watcher reading; the
+	// intermediate states must be collapsed into the latest one.
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		res.GetNetworkInterface().IpAddresses = []string{fmt.Sprintf("192.0.2.%d/24", i+1)}
+		if err := store.Update(ctx, res); err != nil {
+			t.Fatalf("Update %d: %v", i, err)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	ev := <-ch
+	if got := ev.Resource.GetNetworkInterface().GetIpAddresses(); len(got) != 1 || got[0] != "192.0.2.20/24" {
+		t.Errorf("coalesced event has addresses %v, want the final state", got)
+	}
+	select {
+	case extra := <-ch:
+		t.Errorf("unexpected extra event %v for %s", extra.Type, extra.Resource.GetMetadata().GetName())
+	default:
+	}
+
+	// A queued delete wins over later... there are no later events, but it
+	// must also not be replaced by anything queued before it was read.
+	res, _ := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	res.GetNetworkInterface().IpAddresses = []string{"198.51.100.1/24"}
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	ev = <-ch
+	if ev.Type != EventDeleted {
+		t.Errorf("event type = %v, want %v", ev.Type, EventDeleted)
+	}
+}
+
+func TestMemoryStoreWatchInitialState(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	ch, err := store.Watch(ctx, api.KindNetworkInterface, "", WithInitialState())
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	ev := <-ch
+	if ev.Type != EventCreated || ev.Resource.GetMetadata().GetName() != "eth0" {
+		t.Errorf("initial state event = %v %s", ev.Type, ev.Resource.GetMetadata().GetName())
+	}
+}
+
+func TestMemoryStoreStatusOnlyUpdateKeepsGeneration(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	// Writing only observed status must not look like new desired state.
+	res.Status = &pb.Resource_NetworkInterfaceStatus{
+		NetworkInterfaceStatus: &pb.NetworkInterfaceStatus{OperState: "up", RxBytes: 1000},
+	}
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if gen := got.GetMetadata().GetGeneration(); gen != 1 {
+		t.Errorf("generation after status-only update = %d, want 1", gen)
+	}
+	if got.GetNetworkInterfaceStatus().GetRxBytes() != 1000 {
+		t.Error("status update not stored")
+	}
+
+	// Touching the spec still bumps the generation.
+	got.GetNetworkInterface().MacAddress = "11:22:33:44:55:66"
+	if err := store.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err = store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if gen := got.GetMetadata().GetGeneration(); gen != 2 {
+		t.Errorf("generation after spec update = %d, want 2", gen)
+	}
+}
+
+func TestMemoryStoreWatchPredicate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	// Only watch interfaces whose name starts with "wg".
+	ch, err := store.Watch(ctx, api.KindNetworkInterface, "", WithPredicate(func(res *pb.Resource) bool {
+		return strings.HasPrefix(res.GetMetadata().GetName(), "wg")
+	}))
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create eth0: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("wg0", "11:22:33:44:55:66")); err != nil {
+		t.Fatalf("Create wg0: %v", err)
+	}
+
+	ev := <-ch
+	if ev.Type != EventCreated || ev.Resource.GetMetadata().GetName() != "wg0" {
+		t.Fatalf("event = %v %s, want created wg0", ev.Type, ev.Resource.GetMetadata().GetName())
+	}
+	select {
+	case ev := <-ch:
+		t.Fatalf("unexpected event for %s", ev.Resource.GetMetadata().GetName())
+	default:
+	}
+}
+
+func TestMemoryStoreWatchPredicateLeavingProducesDelete(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Watch only interfaces carrying an address.
+	ch, err := store.Watch(ctx, api.KindNetworkInterface, "",
+		WithInitialState(),
+		WithPredicate(func(res *pb.Resource) bool {
+			return len(res.GetNetworkInterface().GetIpAddresses()) > 0
+		}))
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.GetNetworkInterface().IpAddresses = []string{"192.0.2.1/24"}
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	// Entering the predicate delivers the update.
+	ev := <-ch
+	if ev.Type != EventUpdated {
+		t.Fatalf("event after entering = %v, want updated", ev.Type)
+	}
+
+	res, err = store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.GetNetworkInterface().IpAddresses = nil
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	// Leaving the predicate is delivered as a delete.
+	ev = <-ch
+	if ev.Type != EventDeleted {
+		t.Errorf("event after leaving = %v, want deleted", ev.Type)
+	}
+	if ev.Resource.GetMetadata().GetName() != "eth0" {
+		t.Errorf("deleted resource = %s, want eth0", ev.Resource.GetMetadata().GetName())
+	}
+}
+
+func TestMemoryStoreFinalizerOnlyUpdateKeepsGeneration(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	res.Metadata.Finalizers = []string{"network/addr-cleanup"}
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if gen := got.GetMetadata().GetGeneration(); gen != 1 {
+		t.Errorf("generation after finalizer-only update = %d, want 1", gen)
+	}
+	if len(got.GetMetadata().GetFinalizers()) != 1 {
+		t.Error("finalizer not stored")
+	}
+
+	got.GetNetworkInterface().Mtu = 9000
+	if err := store.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err = store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if gen := got.GetMetadata().GetGeneration(); gen != 2 {
+		t.Errorf("generation after spec update = %d, want 2", gen)
+	}
+}
+
+func namespacedInterface(namespace, name, mac string) *pb.Resource {
+	res := testInterface(name, mac)
+	res.Metadata.Namespace = namespace
+	return res
+}
+
+func TestMemoryStoreNamespacesCoexist(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create default: %v", err)
+	}
+	if err := store.Create(ctx, namespacedInterface("tenant-a", "eth0", "11:22:33:44:55:66")); err != nil {
+		t.Fatalf("Create tenant-a: %v", err)
+	}
+
+	got, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get default: %v", err)
+	}
+	if mac := got.GetNetworkInterface().GetMacAddress(); mac != "aa:bb:cc:dd:ee:ff" {
+		t.Errorf("default namespace MAC = %q, want aa:bb:cc:dd:ee:ff", mac)
+	}
+	got, err = store.Get(ctx, api.KindNetworkInterface, "tenant-a", "eth0")
+	if err != nil {
+		t.Fatalf("Get tenant-a: %v", err)
+	}
+	if mac := got.GetNetworkInterface().GetMacAddress(); mac != "11:22:33:44:55:66" {
+		t.Errorf("tenant-a MAC = %q, want 11:22:33:44:55:66", mac)
+	}
+
+	// Deleting in one namespace leaves the same name in the other alone.
+	if err := store.Delete(ctx, api.KindNetworkInterface, "tenant-a", "eth0"); err != nil {
+		t.Fatalf("Delete tenant-a: %v", err)
+	}
+	if _, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0"); err != nil {
+		t.Errorf("default namespace resource gone after tenant delete: %v", err)
+	}
+}
+
+func TestMemoryStoreListIsolatesNamespaces(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create default: %v", err)
+	}
+	if err := store.Create(ctx, namespacedInterface("tenant-a", "eth0", "11:22:33:44:55:66")); err != nil {
+		t.Fatalf("Create tenant-a: %v", err)
+	}
+	if err := store.Create(ctx, namespacedInterface("tenant-a", "eth1", "11:22:33:44:55:67")); err != nil {
+		t.Fatalf("Create tenant-a eth1: %v", err)
+	}
+
+	list, err := store.List(ctx, api.KindNetworkInterface, "tenant-a")
+	if err != nil {
+		t.Fatalf("List tenant-a: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("tenant-a has %d resources, want 2", len(list))
+	}
+	for _, res := range list {
+		if ns := res.GetMetadata().GetNamespace(); ns != "tenant-a" {
+			t.Errorf("listed resource in namespace %q, want tenant-a", ns)
+		}
+	}
+
+	list, err = store.List(ctx, api.KindNetworkInterface, "")
+	if err != nil {
+		t.Fatalf("List default: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("default namespace has %d resources, want 1", len(list))
+	}
+
+	list, err = store.List(ctx, api.KindNetworkInterface, NamespaceAll)
+	if err != nil {
+		t.Fatalf("List all: %v", err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("NamespaceAll lists %d resources, want 3", len(list))
+	}
+}
+
+func TestMemoryStoreWatchIsNamespaced(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	ch, err := store.Watch(ctx, api.KindNetworkInterface, "tenant-a")
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create default: %v", err)
+	}
+	if err := store.Create(ctx, namespacedInterface("tenant-a", "eth0", "11:22:33:44:55:66")); err != nil {
+		t.Fatalf("Create tenant-a: %v", err)
+	}
+
+	ev := <-ch
+	if ns := ev.Resource.GetMetadata().GetNamespace(); ns != "tenant-a" {
+		t.Errorf("watch delivered namespace %q, want tenant-a", ns)
+	}
+	select {
+	case ev := <-ch:
+		t.Errorf("unexpected extra event for %s", ev.Resource.GetMetadata().GetName())
+	default:
+	}
+}
+
+func TestMemoryStoreAnnotationsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	res := testInterface("eth0", "aa:bb:cc:dd:ee:ff")
+	res.Metadata.Annotations = map[string]string{"managed-by": "microrunctl"}
+	if err := store.Create(ctx, res); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if v := got.GetMetadata().GetAnnotations()["managed-by"]; v != "microrunctl" {
+		t.Errorf("annotation managed-by = %q, want microrunctl", v)
+	}
+}
+
+func TestMemoryStoreAnnotationOnlyUpdateKeepsGeneration(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.Metadata.Annotations = map[string]string{"last-applied": "v1"}
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	got, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get after update: %v", err)
+	}
+	if v := got.GetMetadata().GetAnnotations()["last-applied"]; v != "v1" {
+		t.Errorf("annotation last-applied = %q, want v1", v)
+	}
+	if gen := got.GetMetadata().GetGeneration(); gen != 1 {
+		t.Errorf("generation after annotation-only update = %d, want 1", gen)
+	}
+}
+
+func TestMemoryStoreWatchRegistrationNoGap(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	// A writer creates resources while watchers register at arbitrary
+	// points. Every watcher must see each resource exactly once: either
+	// in the WithInitialState snapshot or as a later create event.
+	const total = 50
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < total; i++ {
+			name := fmt.Sprintf("eth%d", i)
+			if err := store.Create(ctx, testInterface(name, "aa:bb:cc:dd:ee:ff")); err != nil {
+				t.Errorf("Create %s: %v", name, err)
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ch, err := store.Watch(ctx, api.KindNetworkInterface, "", WithInitialState())
+			if err != nil {
+				t.Errorf("Watch: %v", err)
+				return
+			}
+			seen := make(map[string]int)
+			for ev := range ch {
+				if ev.Type != EventCreated {
+					continue
+				}
+				seen[ev.Resource.GetMetadata().GetName()]++
+				if len(seen) == total {
+					break
+				}
+			}
+			for name, n := range seen {
+				if n != 1 {
+					t.Errorf("watcher saw %s %d times, want exactly once", name, n)
+				}
+			}
+		}()
+	}
+
+	<-done
+	wg.Wait()
+}
+
+func TestMemoryStoreUIDStableAcrossUpdates(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	uid := res.GetMetadata().GetUid()
+	if uid == "" {
+		t.Fatal("Create did not assign a UID")
+	}
+
+	res.GetNetworkInterface().AdminState = "up"
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get after update: %v", err)
+	}
+	if got.GetMetadata().GetUid() != uid {
+		t.Errorf("UID changed across update: %q -> %q", uid, got.GetMetadata().GetUid())
+	}
+}
+
+func TestMemoryStoreUIDChangesOnRecreate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	ch, err := store.Watch(ctx, api.KindNetworkInterface, "")
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	created := <-ch
+	uid := created.Resource.GetMetadata().GetUid()
+
+	if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	deleted := <-ch
+	if deleted.Type != EventDeleted {
+		t.Fatalf("event after delete = %v, want EventDeleted", deleted.Type)
+	}
+	if got := deleted.Resource.GetMetadata().GetUid(); got != uid {
+		t.Errorf("delete event UID = %q, want %q", got, uid)
+	}
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("recreate: %v", err)
+	}
+	recreated := <-ch
+	if got := recreated.Resource.GetMetadata().GetUid(); got == uid || got == "" {
+		t.Errorf("recreated UID = %q, want a fresh non-empty UID (old %q)", got, uid)
+	}
+}
+
+func TestMemoryStoreWatchBufferSize(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	ch, err := store.Watch(ctx, api.KindNetworkInterface, "", WithBufferSize(1))
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if cap(ch) != 1 {
+		t.Errorf("watch channel capacity = %d, want 1", cap(ch))
+	}
+
+	// The first create fills the buffer; a second one blocks until the
+	// watcher drains an event.
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create eth0: %v", err)
+	}
+	second := make(chan error, 1)
+	go func() {
+		second <- store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:ff"))
+	}()
+	select {
+	case err := <-second:
+		t.Fatalf("Create past full buffer returned %v, want it to block", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	<-ch
+	if err := <-second; err != nil {
+		t.Fatalf("Create eth1 after drain: %v", err)
+	}
+}
+
+func TestMemoryStoreWatchBufferSizeValidation(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	for _, n := range []int{0, -1} {
+		if _, err := store.Watch(ctx, api.KindNetworkInterface, "", WithBufferSize(n)); err == nil {
+			t.Errorf("Watch with buffer size %d succeeded, want error", n)
+		}
+	}
+}
+
+func TestMemoryStoreWatchDefaultBufferSize(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	ch, err := store.Watch(ctx, api.KindNetworkInterface, "")
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if cap(ch) != watchBufferSize {
+		t.Errorf("default watch channel capacity = %d, want %d", cap(ch), watchBufferSize)
+	}
+}
+
+func TestMemoryStoreWatchRateLimitPacesDelivery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	// 20 events/s with a burst of 1: the first event is immediate, each
+	// further one costs ~50ms.
+	ch, err := store.Watch(ctx, api.KindNetworkInterface, "", WithRateLimit(20, 1))
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	const events = 5
+	for i := 0; i < events; i++ {
+		if err := store.Create(ctx, testInterface(fmt.Sprintf("eth%d", i), "aa:bb:cc:dd:ee:ff")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	start := time.Now()
+	for i := 0; i < events; i++ {
+		select {
+		case <-ch:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("event %d never delivered", i)
+		}
+	}
+	// 4 paced events at 50ms each; allow generous slack below the
+	// theoretical 200ms to keep the test robust.
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("5 events delivered in %v, want pacing to stretch past 150ms", elapsed)
+	}
+}
+
+func TestMemoryStoreWatchRateLimitValidation(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	cases := []struct {
+		rate  float64
+		burst int
+	}{{0, 1}, {-1, 1}, {10, 0}, {10, -1}}
+	for _, tc := range cases {
+		if _, err := store.Watch(ctx, api.KindNetworkInterface, "", WithRateLimit(tc.rate, tc.burst)); err == nil {
+			t.Errorf("Watch with rate %g burst %d succeeded, want error", tc.rate, tc.burst)
+		}
+	}
+}
+
+func TestMemoryStoreWatchWithoutRateLimitIsImmediate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	ch, err := store.Watch(ctx, api.KindNetworkInterface, "")
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	const events = 20
+	for i := 0; i < events; i++ {
+		if err := store.Create(ctx, testInterface(fmt.Sprintf("eth%d", i), "aa:bb:cc:dd:ee:ff")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	start := time.Now()
+	for i := 0; i < events; i++ {
+		<-ch
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("unpaced delivery of %d events took %v", events, elapsed)
+	}
+}
+
+func TestMemoryStoreEventSequenceIncreases(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	ch, err := store.Watch(ctx, api.KindNetworkInterface, "")
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.GetNetworkInterface().AdminState = "up"
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	var last uint64
+	for i := 0; i < 3; i++ {
+		ev := <-ch
+		if ev.Seq == 0 {
+			t.Fatalf("event %d has no sequence number", i)
+		}
+		if ev.Seq <= last {
+			t.Fatalf("sequence did not increase: %d after %d", ev.Seq, last)
+		}
+		last = ev.Seq
+	}
+}
+
+func TestMemoryStoreWatchResumeFrom(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+
+	// Record the sequence of the second event through a throwaway watch.
+	all, err := store.Watch(ctx, api.KindNetworkInterface, "")
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		name := fmt.Sprintf("eth%d", i)
+		if err := store.Create(ctx, testInterface(name, "aa:bb:cc:dd:ee:ff")); err != nil {
+			t.Fatalf("Create %s: %v", name, err)
+		}
+	}
+	<-all
+	second := <-all
+
+	// Resuming after the second event replays only eth2 and eth3.
+	resumed, err := store.Watch(ctx, api.KindNetworkInterface, "", WithResumeFrom(second.Seq))
+	if err != nil {
+		t.Fatalf("Watch with resume: %v", err)
+	}
+	for _, want := range []string{"eth2", "eth3"} {
+		select {
+		case ev := <-resumed:
+			if got := ev.Resource.GetMetadata().GetName(); got != want {
+				t.Errorf("replayed %s, want %s", got, want)
+			}
+			if ev.Seq <= second.Seq {
+				t.Errorf("replayed event seq %d not after %d", ev.Seq, second.Seq)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("replay of %s never arrived", want)
+		}
+	}
+	select {
+	case ev := <-resumed:
+		t.Errorf("unexpected extra replayed event for %s", ev.Resource.GetMetadata().GetName())
+	default:
+	}
+
+	// The resumed watch stays live for new events.
+	if err := store.Create(ctx, testInterface("eth4", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create eth4: %v", err)
+	}
+	select {
+	case ev := <-resumed:
+		if got := ev.Resource.GetMetadata().GetName(); got != "eth4" {
+			t.Errorf("live event for %s, want eth4", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("live event after resume never arrived")
+	}
+}
+
+func TestMemoryStoreCountTracksCreateDelete(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if n, err := store.Count(ctx, api.KindNetworkInterface, DefaultNamespace); err != nil || n != 0 {
+		t.Fatalf("Count on empty store = %d, %v, want 0, nil", n, err)
+	}
+	for i := 0; i < 3; i++ {
+		name := fmt.Sprintf("eth%d", i)
+		if err := store.Create(ctx, testInterface(name, "aa:bb:cc:dd:ee:ff")); err != nil {
+			t.Fatalf("Create %s: %v", name, err)
+		}
+	}
+	if n, _ := store.Count(ctx, api.KindNetworkInterface, DefaultNamespace); n != 3 {
+		t.Errorf("Count after creates = %d, want 3", n)
+	}
+	if err := store.Delete(ctx, api.KindNetworkInterface, DefaultNamespace, "eth1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if n, _ := store.Count(ctx, api.KindNetworkInterface, DefaultNamespace); n != 2 {
+		t.Errorf("Count after delete = %d, want 2", n)
+	}
+}
+
+func TestMemoryStoreCountUnknownKind(t *testing.T) {
+	store := NewMemoryStore()
+	if n, err := store.Count(context.Background(), "NoSuchKind", DefaultNamespace); err != nil || n != 0 {
+		t.Errorf("Count of unknown kind = %d, %v, want 0, nil", n, err)
+	}
+}
+
+func TestMemoryStoreCountIsNamespaced(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if err := store.Create(ctx, namespacedInterface("tenant-a", "eth0", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Create(ctx, namespacedInterface("tenant-b", "eth0", "aa:bb:cc:dd:ee:02")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if n, _ := store.Count(ctx, api.KindNetworkInterface, "tenant-a"); n != 1 {
+		t.Errorf("Count in tenant-a = %d, want 1", n)
+	}
+	if n, _ := store.Count(ctx, api.KindNetworkInterface, NamespaceAll); n != 2 {
+		t.Errorf("Count across namespaces = %d, want 2", n)
+	}
+}
+
+func TestMemoryStoreKindsReflectResources(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	kinds, err := store.Kinds(ctx)
+	if err != nil {
+		t.Fatalf("Kinds: %v", err)
+	}
+	if len(kinds) != 0 {
+		t.Errorf("Kinds on empty store = %v, want none", kinds)
+	}
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	route := &pb.Resource{
+		Metadata: &pb.ResourceMetadata{Name: "default", Kind: api.KindRoute},
+		Spec:     &pb.Resource_Route{Route: &pb.Route{Destination: "0.0.0.0/0", Gateway: "10.0.0.1"}},
+	}
+	if err := store.Create(ctx, route); err != nil {
+		t.Fatalf("Create route: %v", err)
+	}
+
+	kinds, err = store.Kinds(ctx)
+	if err != nil {
+		t.Fatalf("Kinds: %v", err)
+	}
+	want := []string{api.KindNetworkInterface, api.KindRoute}
+	if fmt.Sprint(kinds) != fmt.Sprint(want) {
+		t.Errorf("Kinds = %v, want %v", kinds, want)
+	}
+
+	if err := store.Delete(ctx, api.KindRoute, DefaultNamespace, "default"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	kinds, _ = store.Kinds(ctx)
+	if fmt.Sprint(kinds) != fmt.Sprint([]string{api.KindNetworkInterface}) {
+		t.Errorf("Kinds after delete = %v, want [NetworkInterface]", kinds)
+	}
+}
+
+func TestMemoryStoreListAllGroupsByKind(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:02")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	route := &pb.Resource{
+		Metadata: &pb.ResourceMetadata{Name: "default", Kind: api.KindRoute},
+		Spec:     &pb.Resource_Route{Route: &pb.Route{Destination: "0.0.0.0/0", Gateway: "10.0.0.1"}},
+	}
+	if err := store.Create(ctx, route); err != nil {
+		t.Fatalf("Create route: %v", err)
+	}
+
+	all, err := store.ListAll(ctx)
+	if err != nil {
+		t.Fatalf("ListAll: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("ListAll returned %d kinds, want 2", len(all))
+	}
+	if got := len(all[api.KindNetworkInterface]); got != 2 {
+		t.Errorf("ListAll returned %d interfaces, want 2", got)
+	}
+	if got := len(all[api.KindRoute]); got != 1 {
+		t.Errorf("ListAll returned %d routes, want 1", got)
+	}
+	if names := []string{
+		all[api.KindNetworkInterface][0].GetMetadata().GetName(),
+		all[api.KindNetworkInterface][1].GetMetadata().GetName(),
+	}; names[0] != "eth0" || names[1] != "eth1" {
+		t.Errorf("interfaces listed as %v, want sorted [eth0 eth1]", names)
+	}
+}
+
+func TestMemoryStoreListAllReturnsDeepCopies(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	all, err := store.ListAll(ctx)
+	if err != nil {
+		t.Fatalf("ListAll: %v", err)
+	}
+	all[api.KindNetworkInterface][0].GetNetworkInterface().MacAddress = "ff:ff:ff:ff:ff:ff"
+
+	res, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := res.GetNetworkInterface().GetMacAddress(); got != "aa:bb:cc:dd:ee:01" {
+		t.Errorf("stored mac = %q after mutating the ListAll result, want aa:bb:cc:dd:ee:01", got)
+	}
+}
+
+func TestMemoryStoreCloseClosesWatchers(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	kindWatch, err := store.Watch(ctx, api.KindNetworkInterface, DefaultNamespace)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	allWatch, err := store.WatchAll(ctx)
+	if err != nil {
+		t.Fatalf("WatchAll: %v", err)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	for name, ch := range map[string]<-chan Event{"kind watch": kindWatch, "all watch": allWatch} {
+		select {
+		case _, ok := <-ch:
+			if ok {
+				t.Errorf("%s delivered an event after Close", name)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("%s not closed after Close", name)
+		}
+	}
+}
+
+func TestMemoryStoreCloseRejectsMutations(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if err := store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:02")); !IsClosed(err) {
+		t.Errorf("Create after Close = %v, want ClosedError", err)
+	}
+	if err := store.Delete(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); !IsClosed(err) {
+		t.Errorf("Delete after Close = %v, want ClosedError", err)
+	}
+	if _, err := store.Watch(ctx, api.KindNetworkInterface, DefaultNamespace); !IsClosed(err) {
+		t.Errorf("Watch after Close = %v, want ClosedError", err)
+	}
+	// Reads still work so shutdown paths can drain state.
+	if _, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); err != nil {
+		t.Errorf("Get after Close: %v", err)
+	}
+}
+
+func TestMemoryStoreCloseIsIdempotent(t *testing.T) {
+	store := NewMemoryStore()
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+func TestSpecHashStableForIdenticalSpecs(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// Same spec under a different name: only metadata differs.
+	twin := testInterface("eth0-copy", "aa:bb:cc:dd:ee:00")
+	twin.GetNetworkInterface().InterfaceName = "eth0"
+	if err := store.Create(ctx, twin); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	first, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	second, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0-copy")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if first.GetMetadata().GetSpecHash() == "" {
+		t.Fatal("store did not set a spec hash on Create")
+	}
+	if first.GetMetadata().GetSpecHash() != second.GetMetadata().GetSpecHash() {
+		t.Errorf("identical specs hash differently: %q vs %q",
+			first.GetMetadata().GetSpecHash(), second.GetMetadata().GetSpecHash())
+	}
+}
+
+func TestSpecHashChangesOnlyWithSpec(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	created, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	original := created.GetMetadata().GetSpecHash()
+
+	// A metadata-only change keeps the hash.
+	annotated := proto.Clone(created).(*pb.Resource)
+	annotated.Metadata.Annotations = map[string]string{"note": "hands off"}
+	if err := store.Update(ctx, annotated); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := res.GetMetadata().GetSpecHash(); got != original {
+		t.Errorf("metadata-only update changed spec hash from %q to %q", original, got)
+	}
+
+	// A spec change produces a new hash.
+	changed := proto.Clone(res).(*pb.Resource)
+	changed.GetNetworkInterface().Mtu = 9000
+	if err := store.Update(ctx, changed); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	res, err = store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := res.GetMetadata().GetSpecHash(); got == original {
+		t.Error("spec change did not change the spec hash")
+	}
+}
+
+func TestWatchResourceDeliversOnlyNamedResource(t *testing.T) {
+	store := NewMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := store.WatchResource(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("WatchResource: %v", err)
+	}
+
+	if err := store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create eth1: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create eth0: %v", err)
+	}
+	if err := store.Delete(ctx, api.KindNetworkInterface, DefaultNamespace, "eth1"); err != nil {
+		t.Fatalf("Delete eth1: %v", err)
+	}
+	if err := store.Delete(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); err != nil {
+		t.Fatalf("Delete eth0: %v", err)
+	}
+
+	ev := <-events
+	if ev.Type != EventCreated || ev.Resource.GetMetadata().GetName() != "eth0" {
+		t.Errorf("first event = %v %s, want created eth0", ev.Type, ev.Resource.GetMetadata().GetName())
+	}
+	ev = <-events
+	if ev.Type != EventDeleted || ev.Resource.GetMetadata().GetName() != "eth0" {
+		t.Errorf("second event = %v %s, want deleted eth0", ev.Type, ev.Resource.GetMetadata().GetName())
+	}
+	select {
+	case ev := <-events:
+		t.Errorf("unexpected event %v for %s", ev.Type, ev.Resource.GetMetadata().GetName())
+	default:
+	}
+}
+
+func TestWatchResourceInitialStateIsScoped(t *testing.T) {
+	store := NewMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	events, err := store.WatchResource(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0", WithInitialState())
+	if err != nil {
+		t.Fatalf("WatchResource: %v", err)
+	}
+	ev := <-events
+	if ev.Resource.GetMetadata().GetName() != "eth0" {
+		t.Errorf("snapshot event for %s, want eth0", ev.Resource.GetMetadata().GetName())
+	}
+	select {
+	case ev := <-events:
+		t.Errorf("snapshot leaked %s into a scoped watch", ev.Resource.GetMetadata().GetName())
+	default:
+	}
+}
+
+func TestCancelledWatchDrainsQueuedEvents(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := store.Create(ctx, testInterface(fmt.Sprintf("eth%d", i), "aa:bb:cc:dd:ee:00")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	// WithInitialState routes delivery through the bridging goroutine,
+	// the path that used to race cancellation against the last sends.
+	events, err := store.Watch(watchCtx, api.KindNetworkInterface, DefaultNamespace, WithInitialState())
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	for i := 3; i < 5; i++ {
+		if err := store.Create(ctx, testInterface(fmt.Sprintf("eth%d", i), "aa:bb:cc:dd:ee:00")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	// Cancel before reading anything: the five queued events must still
+	// arrive, then the channel must close, with no send-on-closed panic.
+	cancel()
+	var got int
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				if got != 5 {
+					t.Fatalf("drained %d events before close, want 5", got)
+				}
+				return
+			}
+			got++
+		case <-deadline:
+			t.Fatalf("channel never closed; drained %d events", got)
+		}
+	}
+}
+
+func TestCancelledTypedWatchDrainsQueuedEvents(t *testing.T) {
+	store := NewMemoryStore()
+	typed, err := NewTypedStore[*pb.NetworkInterface](store)
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+	ctx := context.Background()
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	events, err := typed.Watch(watchCtx)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if err := store.Create(ctx, testInterface(fmt.Sprintf("eth%d", i), "aa:bb:cc:dd:ee:00")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	cancel()
+	var got int
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				if got != 4 {
+					t.Fatalf("drained %d typed events before close, want 4", got)
+				}
+				return
+			}
+			got++
+		case <-deadline:
+			t.Fatalf("typed channel never closed; drained %d events", got)
+		}
+	}
+}
+
+func TestWatchResyncDeliversSyntheticUpdates(t *testing.T) {
+	store := NewMemoryStore()
+	fake := clock.NewFake(time.Unix(0, 0))
+	store.(*memoryStore).clock = fake
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	events, err := store.Watch(ctx, api.KindNetworkInterface, DefaultNamespace, WithResyncInterval(time.Minute))
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("got a %v event before the first resync tick", ev.Type)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	fake.Advance(time.Minute)
+	select {
+	case ev := <-events:
+		if ev.Type != EventUpdated || ev.Seq != 0 {
+			t.Errorf("resync event = %v seq %d, want a synthetic update with seq 0", ev.Type, ev.Seq)
+		}
+		if ev.Resource.GetMetadata().GetName() != "eth0" {
+			t.Errorf("resync event names %q, want eth0", ev.Resource.GetMetadata().GetName())
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no resync event after advancing the clock")
+	}
+
+	// A real change shows up in the next tick's snapshot.
+	res, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.GetNetworkInterface().Mtu = 9000
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if ev := <-events; ev.Type != EventUpdated || ev.Seq == 0 {
+		t.Fatalf("real update event = %v seq %d, want a committed update", ev.Type, ev.Seq)
+	}
+	fake.Advance(time.Minute)
+	select {
+	case ev := <-events:
+		if got := ev.Resource.GetNetworkInterface().GetMtu(); got != 9000 {
+			t.Errorf("resync event carries mtu %d, want the current 9000", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no resync event after the second tick")
+	}
+}
+
+func TestWatchResyncRejectsNegativeInterval(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	if _, err := store.Watch(ctx, api.KindNetworkInterface, DefaultNamespace, WithResyncInterval(-time.Second)); err == nil {
+		t.Fatal("Watch with a negative resync interval succeeded")
+	}
+}
+
+func TestExistsReportsPresenceWithoutError(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	ok, err := store.Exists(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil || !ok {
+		t.Errorf("Exists(eth0) = %v, %v, want true", ok, err)
+	}
+	ok, err = store.Exists(ctx, api.KindNetworkInterface, DefaultNamespace, "eth1")
+	if err != nil || ok {
+		t.Errorf("Exists(eth1) = %v, %v, want false without error", ok, err)
+	}
+}
+
+func TestExistsPropagatesRealErrors(t *testing.T) {
+	restricted := NewTypeRestrictedStore(NewMemoryStore(), []string{api.KindBridge})
+	if _, err := restricted.Exists(context.Background(), api.KindNetworkInterface, DefaultNamespace, "eth0"); err == nil {
+		t.Fatal("Exists on a restricted kind succeeded")
+	}
+}
+
+func TestDeletePolicies(t *testing.T) {
+	newFinalized := func(t *testing.T, store Store) {
+		t.Helper()
+		res := testInterface("eth0", "aa:bb:cc:dd:ee:ff")
+		res.Metadata.Owner = "network-interfaces"
+		res.Metadata.Finalizers = []string{"network-interfaces/cleanup"}
+		if err := store.Create(context.Background(), res); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	t.Run("foreground soft-deletes", func(t *testing.T) {
+		ctx := context.Background()
+		store := NewMemoryStore()
+		newFinalized(t, store)
+		if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0",
+			WithDeletePolicy(DeleteForeground)); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		got, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if got.GetMetadata().GetDeletionTimestamp() == nil {
+			t.Error("foreground delete did not set the deletion timestamp")
+		}
+	})
+
+	t.Run("background ignores finalizers", func(t *testing.T) {
+		ctx := context.Background()
+		store := NewMemoryStore()
+		newFinalized(t, store)
+		if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0",
+			WithDeletePolicy(DeleteBackground)); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0"); !IsNotFound(err) {
+			t.Errorf("resource still present after background delete: %v", err)
+		}
+	})
+
+	t.Run("orphan clears ownership and keeps the resource", func(t *testing.T) {
+		ctx := context.Background()
+		store := NewMemoryStore()
+		newFinalized(t, store)
+		if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0",
+			WithDeletePolicy(DeleteOrphan)); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		got, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if owner := got.GetMetadata().GetOwner(); owner != "" {
+			t.Errorf("orphaned resource still owned by %q", owner)
+		}
+		if got.GetMetadata().GetDeletionTimestamp() != nil {
+			t.Error("orphaning delete set a deletion timestamp")
+		}
+	})
+}
+
+func TestUpdateUnknownKindReturnsNotFound(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	// No resource of this kind has ever been created, so the store has
+	// no map for it; Update must report NotFound rather than assume one.
+	err := store.Update(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff"))
+	if !IsNotFound(err) {
+		t.Fatalf("Update of never-seen kind = %v, want NotFound", err)
+	}
+}
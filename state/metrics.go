@@ -0,0 +1,285 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/microrun/microrun/api/pb"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+)
+
+// allKindsLabel is the kind label reported for WatchAll watchers, which
+// observe every kind at once.
+const allKindsLabel = "_all"
+
+// WatcherStats describes one active watcher for monitoring: who it
+// observes and how full its delivery queue is.
+type WatcherStats struct {
+	ID        uint64
+	Kind      string
+	Namespace string
+	QueueLen  int
+	QueueCap  int
+}
+
+// WatchStatsProvider is the optional interface of stores that expose
+// their watcher internals. MetricsStore exports them when the wrapped
+// store implements it.
+type WatchStatsProvider interface {
+	WatchStats() []WatcherStats
+	CoalescedEvents() uint64
+}
+
+// MetricsStore wraps a Store and exports per-kind operation counters, a
+// resource count gauge, and operation latency histograms. It should be
+// the outermost decorator so the gauge sees every mutation.
+type MetricsStore struct {
+	store Store
+
+	operations *prometheus.CounterVec
+	resources  *prometheus.GaugeVec
+	latency    *prometheus.HistogramVec
+}
+
+// NewMetricsStore wraps store and registers its metrics with reg.
+func NewMetricsStore(store Store, reg prometheus.Registerer) (*MetricsStore, error) {
+	s := &MetricsStore{
+		store: store,
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "microrun_store_operations_total",
+			Help: "Completed store operations by kind. No-op updates count as \"noop\", not \"update\".",
+		}, []string{"operation", "kind"}),
+		resources: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "microrun_store_resources",
+			Help: "Number of resources currently in the store, by kind.",
+		}, []string{"kind"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "microrun_store_operation_duration_seconds",
+			Help:    "Latency of store operations by kind.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation", "kind"}),
+	}
+	for _, c := range []prometheus.Collector{s.operations, s.resources, s.latency} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	if provider, ok := store.(WatchStatsProvider); ok {
+		if err := reg.Register(&watcherCollector{provider: provider}); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// watcherCollector exports watcher health from a WatchStatsProvider: the
+// number of active watchers per kind, each watcher's queue depth, and
+// how many events coalescing has collapsed away. A queue pinned at its
+// capacity marks a consumer that stopped draining.
+type watcherCollector struct {
+	provider WatchStatsProvider
+}
+
+var (
+	watchersDesc = prometheus.NewDesc("microrun_store_watchers",
+		"Active watchers by kind.", []string{"kind"}, nil)
+	watchQueueDesc = prometheus.NewDesc("microrun_store_watch_queue_depth",
+		"Queued events per watcher.", []string{"kind", "watcher"}, nil)
+	coalescedDesc = prometheus.NewDesc("microrun_store_watch_events_coalesced_total",
+		"Events collapsed away by coalescing watchers.", nil, nil)
+)
+
+func (c *watcherCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- watchersDesc
+	ch <- watchQueueDesc
+	ch <- coalescedDesc
+}
+
+func (c *watcherCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.provider.WatchStats()
+	byKind := make(map[string]int)
+	for _, stat := range stats {
+		byKind[stat.Kind]++
+		ch <- prometheus.MustNewConstMetric(watchQueueDesc, prometheus.GaugeValue,
+			float64(stat.QueueLen), stat.Kind, fmt.Sprintf("%d", stat.ID))
+	}
+	for kind, count := range byKind {
+		ch <- prometheus.MustNewConstMetric(watchersDesc, prometheus.GaugeValue,
+			float64(count), kind)
+	}
+	ch <- prometheus.MustNewConstMetric(coalescedDesc, prometheus.CounterValue,
+		float64(c.provider.CoalescedEvents()))
+}
+
+func (s *MetricsStore) observe(operation, kind string, start time.Time, err error) {
+	s.latency.WithLabelValues(operation, kind).Observe(time.Since(start).Seconds())
+	if err == nil {
+		s.operations.WithLabelValues(operation, kind).Inc()
+	}
+}
+
+func (s *MetricsStore) Create(ctx context.Context, res *pb.Resource) error {
+	kind := res.GetMetadata().GetKind()
+	start := time.Now()
+	err := s.store.Create(ctx, res)
+	s.observe("create", kind, start, err)
+	if err == nil {
+		s.resources.WithLabelValues(kind).Inc()
+	}
+	return err
+}
+
+func (s *MetricsStore) Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	start := time.Now()
+	res, err := s.store.Get(ctx, kind, namespace, name)
+	s.observe("get", kind, start, err)
+	return res, err
+}
+
+func (s *MetricsStore) Exists(ctx context.Context, kind, namespace, name string) (bool, error) {
+	start := time.Now()
+	ok, err := s.store.Exists(ctx, kind, namespace, name)
+	s.observe("exists", kind, start, err)
+	return ok, err
+}
+
+func (s *MetricsStore) Update(ctx context.Context, res *pb.Resource) error {
+	kind := res.GetMetadata().GetKind()
+	namespace := res.GetMetadata().GetNamespace()
+	name := res.GetMetadata().GetName()
+	start := time.Now()
+
+	// Distinguish no-op updates the same way the store does: a resource
+	// that matches the stored state (bookkeeping aside) changes nothing.
+	noop := false
+	if existing, err := s.store.Get(ctx, kind, namespace, name); err == nil {
+		incoming := proto.Clone(res).(*pb.Resource)
+		incoming.Metadata.Owner = existing.GetMetadata().GetOwner()
+		incoming.Metadata.Generation = existing.GetMetadata().GetGeneration()
+		noop = proto.Equal(existing, incoming)
+	}
+
+	err := s.store.Update(ctx, res)
+	if err == nil && noop {
+		s.latency.WithLabelValues("update", kind).Observe(time.Since(start).Seconds())
+		s.operations.WithLabelValues("noop", kind).Inc()
+		return nil
+	}
+	s.observe("update", kind, start, err)
+	return err
+}
+
+func (s *MetricsStore) Delete(ctx context.Context, kind, namespace, name string, opts ...DeleteOption) error {
+	start := time.Now()
+	err := s.store.Delete(ctx, kind, namespace, name, opts...)
+	s.observe("delete", kind, start, err)
+	if err == nil {
+		s.resources.WithLabelValues(kind).Dec()
+	}
+	return err
+}
+
+func (s *MetricsStore) List(ctx context.Context, kind, namespace string) ([]*pb.Resource, error) {
+	start := time.Now()
+	list, err := s.store.List(ctx, kind, namespace)
+	s.observe("list", kind, start, err)
+	return list, err
+}
+
+func (s *MetricsStore) Count(ctx context.Context, kind, namespace string) (int, error) {
+	start := time.Now()
+	n, err := s.store.Count(ctx, kind, namespace)
+	s.observe("count", kind, start, err)
+	return n, err
+}
+
+func (s *MetricsStore) Kinds(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	kinds, err := s.store.Kinds(ctx)
+	s.observe("kinds", "", start, err)
+	return kinds, err
+}
+
+func (s *MetricsStore) ListAll(ctx context.Context) (map[string][]*pb.Resource, error) {
+	start := time.Now()
+	all, err := s.store.ListAll(ctx)
+	s.observe("list_all", "", start, err)
+	return all, err
+}
+
+func (s *MetricsStore) Replace(ctx context.Context, kind string, desired []*pb.Resource) error {
+	start := time.Now()
+	err := s.store.Replace(ctx, kind, desired)
+	s.observe("replace", kind, start, err)
+	return err
+}
+
+func (s *MetricsStore) Close() error {
+	return s.store.Close()
+}
+
+func (s *MetricsStore) Patch(ctx context.Context, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error) {
+	start := time.Now()
+	res, err := s.store.Patch(ctx, kind, namespace, name, patch, patchType)
+	s.observe("patch", kind, start, err)
+	return res, err
+}
+
+func (s *MetricsStore) ListPage(ctx context.Context, kind, namespace string, opts PageOptions) (ListResult, error) {
+	start := time.Now()
+	result, err := s.store.ListPage(ctx, kind, namespace, opts)
+	s.observe("list", kind, start, err)
+	return result, err
+}
+
+func (s *MetricsStore) Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.Watch(ctx, kind, namespace, opts...)
+}
+
+func (s *MetricsStore) WatchResource(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchResource(ctx, kind, namespace, name, opts...)
+}
+
+func (s *MetricsStore) WatchKinds(ctx context.Context, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchKinds(ctx, kinds, namespace, opts...)
+}
+
+func (s *MetricsStore) WatchAll(ctx context.Context, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchAll(ctx, opts...)
+}
+
+// Transaction delegates to the wrapped store. Mutations made inside the
+// transaction are not broken out into per-operation metrics.
+func (s *MetricsStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.store.Transaction(ctx, fn)
+}
+
+func (s *MetricsStore) AddIndex(kind, indexName string, fn func(*pb.Resource) []string) error {
+	return s.store.AddIndex(kind, indexName, fn)
+}
+
+func (s *MetricsStore) RegisterAdmission(kind string, fn AdmissionFunc) {
+	s.store.RegisterAdmission(kind, fn)
+}
+
+func (s *MetricsStore) BeginBatch() {
+	s.store.BeginBatch()
+}
+
+func (s *MetricsStore) CommitBatch() {
+	s.store.CommitBatch()
+}
+
+func (s *MetricsStore) GetByIndex(ctx context.Context, kind, indexName, value string) ([]*pb.Resource, error) {
+	return s.store.GetByIndex(ctx, kind, indexName, value)
+}
+
+func (s *MetricsStore) SetObservedGeneration(ctx context.Context, kind, namespace, name string, gen int64) error {
+	return s.store.SetObservedGeneration(ctx, kind, namespace, name, gen)
+}
+
+func (s *MetricsStore) TransferOwner(ctx context.Context, kind, namespace, name, owner string) error {
+	return s.store.TransferOwner(ctx, kind, namespace, name, owner)
+}
@@ -0,0 +1,193 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/microrun/microrun/api"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsStoreCountsOperations(t *testing.T) {
+	ctx := context.Background()
+	reg := prometheus.NewPedanticRegistry()
+	store, err := NewMetricsStore(NewMemoryStore(), reg)
+	if err != nil {
+		t.Fatalf("NewMetricsStore: %v", err)
+	}
+
+	kind := api.KindNetworkInterface
+	counter := func(op string) float64 {
+		return testutil.ToFloat64(store.operations.WithLabelValues(op, kind))
+	}
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := counter("create"); got != 1 {
+		t.Errorf("create counter = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(store.resources.WithLabelValues(kind)); got != 1 {
+		t.Errorf("resource gauge = %v, want 1", got)
+	}
+
+	res, err := store.Get(ctx, kind, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := counter("get"); got != 1 {
+		t.Errorf("get counter = %v, want 1", got)
+	}
+
+	res.GetNetworkInterface().IpAddresses = []string{"192.0.2.1/24"}
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got := counter("update"); got != 1 {
+		t.Errorf("update counter = %v, want 1", got)
+	}
+
+	if _, err := store.List(ctx, kind, ""); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if got := counter("list"); got != 1 {
+		t.Errorf("list counter = %v, want 1", got)
+	}
+
+	if err := store.Delete(ctx, kind, "", "eth0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if got := counter("delete"); got != 1 {
+		t.Errorf("delete counter = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(store.resources.WithLabelValues(kind)); got != 0 {
+		t.Errorf("resource gauge after delete = %v, want 0", got)
+	}
+
+	// A failed delete must not advance the counter or the gauge.
+	if err := store.Delete(ctx, kind, "", "eth0"); err == nil {
+		t.Fatal("second Delete succeeded, want error")
+	}
+	if got := counter("delete"); got != 1 {
+		t.Errorf("delete counter after failed delete = %v, want 1", got)
+	}
+}
+
+func TestMetricsStoreNoopUpdate(t *testing.T) {
+	ctx := context.Background()
+	reg := prometheus.NewPedanticRegistry()
+	store, err := NewMetricsStore(NewMemoryStore(), reg)
+	if err != nil {
+		t.Fatalf("NewMetricsStore: %v", err)
+	}
+	kind := api.KindNetworkInterface
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := store.Get(ctx, kind, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := store.Update(ctx, res); err != nil {
+		t.Fatalf("no-op Update: %v", err)
+	}
+	if got := testutil.ToFloat64(store.operations.WithLabelValues("noop", kind)); got != 1 {
+		t.Errorf("noop counter = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(store.operations.WithLabelValues("update", kind)); got != 0 {
+		t.Errorf("update counter = %v, want 0 after no-op", got)
+	}
+}
+
+func gatherValue(t *testing.T, reg *prometheus.Registry, name string, labels map[string]string) (float64, bool) {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	sample:
+		for _, metric := range family.GetMetric() {
+			for key, want := range labels {
+				for _, pair := range metric.GetLabel() {
+					if pair.GetName() == key && pair.GetValue() != want {
+						continue sample
+					}
+				}
+			}
+			if g := metric.GetGauge(); g != nil {
+				return g.GetValue(), true
+			}
+			return metric.GetCounter().GetValue(), true
+		}
+	}
+	return 0, false
+}
+
+func TestMetricsStoreExportsWatcherGauges(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	store, err := NewMetricsStore(NewMemoryStore(), reg)
+	if err != nil {
+		t.Fatalf("NewMetricsStore: %v", err)
+	}
+	kind := api.KindNetworkInterface
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := store.Watch(ctx, kind, DefaultNamespace); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	watchCtx, cancelWatch := context.WithCancel(context.Background())
+	if _, err := store.Watch(watchCtx, kind, DefaultNamespace); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	if got, ok := gatherValue(t, reg, "microrun_store_watchers", map[string]string{"kind": kind}); !ok || got != 2 {
+		t.Errorf("watcher gauge = %v (found %v), want 2", got, ok)
+	}
+
+	cancelWatch()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if got, _ := gatherValue(t, reg, "microrun_store_watchers", map[string]string{"kind": kind}); got == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("watcher gauge never dropped after cancel")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMetricsStoreExportsQueueDepth(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	store, err := NewMetricsStore(NewMemoryStore(), reg)
+	if err != nil {
+		t.Fatalf("NewMetricsStore: %v", err)
+	}
+	kind := api.KindNetworkInterface
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The watcher never drains, so every mutation stays queued.
+	if _, err := store.Watch(ctx, kind, DefaultNamespace); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		res := testInterface(fmt.Sprintf("eth%d", i), "aa:bb:cc:dd:ee:00")
+		if err := store.Create(context.Background(), res); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	if got, ok := gatherValue(t, reg, "microrun_store_watch_queue_depth", map[string]string{"kind": kind}); !ok || got != 5 {
+		t.Errorf("queue depth = %v (found %v), want 5", got, ok)
+	}
+}
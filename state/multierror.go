@@ -0,0 +1,55 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MultiError aggregates per-resource errors from a batch operation, keyed
+// by "kind/name", so callers see every failed item instead of only the
+// first.
+type MultiError struct {
+	Errors map[string]error
+}
+
+// Add records err under kind/name, allocating the map on first use.
+func (e *MultiError) Add(kind, name string, err error) {
+	if e.Errors == nil {
+		e.Errors = make(map[string]error)
+	}
+	e.Errors[kind+"/"+name] = err
+}
+
+func (e *MultiError) Error() string {
+	keys := make([]string, 0, len(e.Errors))
+	for key := range e.Errors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s: %v", key, e.Errors[key]))
+	}
+	return fmt.Sprintf("%d resources failed: %s", len(keys), strings.Join(parts, "; "))
+}
+
+// ErrOrNil returns e when it holds any errors and nil otherwise, so batch
+// code can return it unconditionally.
+func (e *MultiError) ErrOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// IsMultiError reports whether err is (or wraps) a MultiError, returning
+// the typed error when it does.
+func IsMultiError(err error) (*MultiError, bool) {
+	var multi *MultiError
+	if errors.As(err, &multi) {
+		return multi, true
+	}
+	return nil, false
+}
@@ -0,0 +1,264 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/microrun/microrun/api/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// OwnershipError is returned when a store scoped to one owner attempts to
+// mutate a resource owned by another.
+type OwnershipError struct {
+	Kind  string
+	Name  string
+	Owner string
+	Actor string
+}
+
+func (e *OwnershipError) Error() string {
+	return fmt.Sprintf("resource %s/%s is owned by %q, not %q",
+		e.Kind, e.Name, e.Owner, e.Actor)
+}
+
+// IsOwnership reports whether err is (or wraps) an OwnershipError,
+// returning the typed error when it does.
+func IsOwnership(err error) (*OwnershipError, bool) {
+	var ownership *OwnershipError
+	if errors.As(err, &ownership) {
+		return ownership, true
+	}
+	return nil, false
+}
+
+// OwnershipStore wraps a Store and stamps every created resource with a
+// fixed owner, refusing to mutate resources owned by anyone else. The
+// runtime hands each generator an ownership store scoped to its name.
+type OwnershipStore struct {
+	store Store
+	owner string
+}
+
+// NewOwnershipStore returns a store whose mutations are scoped to owner.
+func NewOwnershipStore(store Store, owner string) *OwnershipStore {
+	return &OwnershipStore{store: store, owner: owner}
+}
+
+// Owner returns the owner this store acts as.
+func (s *OwnershipStore) Owner() string {
+	return s.owner
+}
+
+func (s *OwnershipStore) checkOwner(ctx context.Context, kind, namespace, name string) error {
+	existing, err := s.store.Get(ctx, kind, namespace, name)
+	if err != nil {
+		return err
+	}
+	if owner := existing.GetMetadata().GetOwner(); owner != s.owner {
+		return &OwnershipError{Kind: kind, Name: name, Owner: owner, Actor: s.owner}
+	}
+	return nil
+}
+
+// checkWriter returns the stored resource when this store's owner may
+// mutate it: either as the resource's owner or as a listed writer.
+func (s *OwnershipStore) checkWriter(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	existing, err := s.store.Get(ctx, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	md := existing.GetMetadata()
+	if md.GetOwner() == s.owner {
+		return existing, nil
+	}
+	for _, writer := range md.GetWriters() {
+		if writer == s.owner {
+			return existing, nil
+		}
+	}
+	return nil, &OwnershipError{Kind: kind, Name: name, Owner: md.GetOwner(), Actor: s.owner}
+}
+
+func (s *OwnershipStore) Create(ctx context.Context, res *pb.Resource) error {
+	if res.GetMetadata() == nil {
+		return fmt.Errorf("resource has no metadata")
+	}
+	res.Metadata.Owner = s.owner
+	return s.store.Create(ctx, res)
+}
+
+func (s *OwnershipStore) Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	return s.store.Get(ctx, kind, namespace, name)
+}
+
+func (s *OwnershipStore) Exists(ctx context.Context, kind, namespace, name string) (bool, error) {
+	return s.store.Exists(ctx, kind, namespace, name)
+}
+
+func (s *OwnershipStore) Update(ctx context.Context, res *pb.Resource) error {
+	md := res.GetMetadata()
+	existing, err := s.checkWriter(ctx, md.GetKind(), md.GetNamespace(), md.GetName())
+	if err != nil {
+		return err
+	}
+	// Writers may change the resource, but the writers list itself stays
+	// under the primary owner's control: a writer editing it could grant
+	// or revoke access it was never given authority over.
+	if existing.GetMetadata().GetOwner() != s.owner &&
+		!slices.Equal(existing.GetMetadata().GetWriters(), md.GetWriters()) {
+		return &OwnershipError{Kind: md.GetKind(), Name: md.GetName(),
+			Owner: existing.GetMetadata().GetOwner(), Actor: s.owner}
+	}
+	return s.store.Update(ctx, res)
+}
+
+func (s *OwnershipStore) Delete(ctx context.Context, kind, namespace, name string, opts ...DeleteOption) error {
+	if _, err := s.checkWriter(ctx, kind, namespace, name); err != nil {
+		return err
+	}
+	return s.store.Delete(ctx, kind, namespace, name, opts...)
+}
+
+func (s *OwnershipStore) List(ctx context.Context, kind, namespace string) ([]*pb.Resource, error) {
+	return s.store.List(ctx, kind, namespace)
+}
+
+// ListOwned returns the resources of kind owned by this store's owner,
+// skipping everything foreign. Reconcile loops use it to sweep their own
+// resources without filtering the full list themselves.
+func (s *OwnershipStore) ListOwned(ctx context.Context, kind string) ([]*pb.Resource, error) {
+	all, err := s.store.List(ctx, kind, DefaultNamespace)
+	if err != nil {
+		return nil, err
+	}
+	owned := all[:0]
+	for _, res := range all {
+		if res.GetMetadata().GetOwner() == s.owner {
+			owned = append(owned, res)
+		}
+	}
+	return owned, nil
+}
+
+func (s *OwnershipStore) Count(ctx context.Context, kind, namespace string) (int, error) {
+	return s.store.Count(ctx, kind, namespace)
+}
+
+func (s *OwnershipStore) Kinds(ctx context.Context) ([]string, error) {
+	return s.store.Kinds(ctx)
+}
+
+func (s *OwnershipStore) ListAll(ctx context.Context) (map[string][]*pb.Resource, error) {
+	return s.store.ListAll(ctx)
+}
+
+func (s *OwnershipStore) Replace(ctx context.Context, kind string, desired []*pb.Resource) error {
+	return s.Transaction(ctx, func(tx Store) error {
+		return replaceAll(ctx, tx, kind, desired)
+	})
+}
+
+func (s *OwnershipStore) Close() error {
+	return s.store.Close()
+}
+
+func (s *OwnershipStore) Patch(ctx context.Context, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error) {
+	if _, err := s.checkWriter(ctx, kind, namespace, name); err != nil {
+		return nil, err
+	}
+	return s.store.Patch(ctx, kind, namespace, name, patch, patchType)
+}
+
+func (s *OwnershipStore) ListPage(ctx context.Context, kind, namespace string, opts PageOptions) (ListResult, error) {
+	return s.store.ListPage(ctx, kind, namespace, opts)
+}
+
+func (s *OwnershipStore) Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.Watch(ctx, kind, namespace, opts...)
+}
+
+func (s *OwnershipStore) WatchResource(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchResource(ctx, kind, namespace, name, opts...)
+}
+
+func (s *OwnershipStore) WatchKinds(ctx context.Context, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchKinds(ctx, kinds, namespace, opts...)
+}
+
+func (s *OwnershipStore) WatchAll(ctx context.Context, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchAll(ctx, opts...)
+}
+
+func (s *OwnershipStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.store.Transaction(ctx, func(tx Store) error {
+		return fn(NewOwnershipStore(tx, s.owner))
+	})
+}
+
+func (s *OwnershipStore) AddIndex(kind, indexName string, fn func(*pb.Resource) []string) error {
+	return s.store.AddIndex(kind, indexName, fn)
+}
+
+func (s *OwnershipStore) RegisterAdmission(kind string, fn AdmissionFunc) {
+	s.store.RegisterAdmission(kind, fn)
+}
+
+func (s *OwnershipStore) BeginBatch() {
+	s.store.BeginBatch()
+}
+
+func (s *OwnershipStore) CommitBatch() {
+	s.store.CommitBatch()
+}
+
+func (s *OwnershipStore) GetByIndex(ctx context.Context, kind, indexName, value string) ([]*pb.Resource, error) {
+	return s.store.GetByIndex(ctx, kind, indexName, value)
+}
+
+func (s *OwnershipStore) SetObservedGeneration(ctx context.Context, kind, namespace, name string, gen int64) error {
+	return s.store.SetObservedGeneration(ctx, kind, namespace, name, gen)
+}
+
+// TransferOwner hands a resource this store owns over to another owner.
+// Giving a resource away is allowed; taking one requires ForceUpdate.
+func (s *OwnershipStore) TransferOwner(ctx context.Context, kind, namespace, name, owner string) error {
+	if err := s.checkOwner(ctx, kind, namespace, name); err != nil {
+		return err
+	}
+	return s.store.TransferOwner(ctx, kind, namespace, name, owner)
+}
+
+// ForceUpdate applies an update to a resource regardless of its current
+// owner, taking ownership in the process. The previous owner ends up in
+// the PreviousOwnerAnnotation annotation, so a takeover from a dead
+// generator leaves a trace. Update keeps its owner check; this is the
+// explicit, opt-in escape hatch.
+func (s *OwnershipStore) ForceUpdate(ctx context.Context, res *pb.Resource) error {
+	md := res.GetMetadata()
+	if md == nil {
+		return fmt.Errorf("resource has no metadata")
+	}
+	existing, err := s.store.Get(ctx, md.GetKind(), md.GetNamespace(), md.GetName())
+	if err != nil {
+		return err
+	}
+	prev := existing.GetMetadata().GetOwner()
+	if prev == s.owner {
+		return s.Update(ctx, res)
+	}
+	if err := s.store.TransferOwner(ctx, md.GetKind(), md.GetNamespace(), md.GetName(), s.owner); err != nil {
+		return err
+	}
+	// Carry the takeover annotation into the update: annotations are
+	// caller-owned, so an update without it would drop the trace the
+	// transfer just recorded.
+	forced := proto.Clone(res).(*pb.Resource)
+	if forced.Metadata.Annotations == nil {
+		forced.Metadata.Annotations = make(map[string]string)
+	}
+	forced.Metadata.Annotations[PreviousOwnerAnnotation] = prev
+	return s.Update(ctx, forced)
+}
@@ -0,0 +1,278 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+)
+
+func TestOwnershipStoreStampsOwner(t *testing.T) {
+	ctx := context.Background()
+	base := NewMemoryStore()
+	owned := NewOwnershipStore(base, "network-interfaces")
+
+	if err := owned.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	got, err := base.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if owner := got.GetMetadata().GetOwner(); owner != "network-interfaces" {
+		t.Errorf("owner = %q, want network-interfaces", owner)
+	}
+}
+
+func TestOwnershipStoreRejectsForeignMutation(t *testing.T) {
+	ctx := context.Background()
+	base := NewMemoryStore()
+	owner := NewOwnershipStore(base, "network-interfaces")
+	other := NewOwnershipStore(base, "dhcp")
+
+	if err := owner.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	res, err := other.Get(ctx, api.KindNetworkInterface, "", "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.GetNetworkInterface().MacAddress = "11:22:33:44:55:66"
+	if err := other.Update(ctx, res); err == nil {
+		t.Error("foreign Update succeeded, want OwnershipError")
+	} else if _, ok := err.(*OwnershipError); !ok {
+		t.Errorf("foreign Update returned %T, want *OwnershipError", err)
+	}
+	if err := other.Delete(ctx, api.KindNetworkInterface, "", "eth0"); err == nil {
+		t.Error("foreign Delete succeeded, want OwnershipError")
+	}
+
+	// The owner itself can still mutate.
+	if err := owner.Update(ctx, res); err != nil {
+		t.Errorf("owner Update: %v", err)
+	}
+}
+
+func TestTypeRestrictedStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewTypeRestrictedStore(NewMemoryStore(), []string{api.KindNetworkInterface})
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create of allowed kind: %v", err)
+	}
+	if _, err := store.List(ctx, api.KindDHCPClient, ""); err == nil {
+		t.Error("List of restricted kind succeeded, want TypeRestrictedError")
+	} else if _, ok := err.(*TypeRestrictedError); !ok {
+		t.Errorf("List returned %T, want *TypeRestrictedError", err)
+	}
+}
+
+func TestIsOwnershipFindsWrappedError(t *testing.T) {
+	err := fmt.Errorf("reconciling: %w",
+		&OwnershipError{Kind: api.KindNetworkInterface, Name: "eth0", Owner: "a", Actor: "b"})
+	ownership, ok := IsOwnership(err)
+	if !ok {
+		t.Fatalf("IsOwnership(%v) = false, want true", err)
+	}
+	if ownership.Owner != "a" || ownership.Actor != "b" {
+		t.Errorf("IsOwnership returned %+v, want owner a actor b", ownership)
+	}
+	if _, ok := IsOwnership(fmt.Errorf("plain error")); ok {
+		t.Error("IsOwnership matched a plain error")
+	}
+}
+
+func TestIsTypeRestrictedFindsWrappedError(t *testing.T) {
+	err := fmt.Errorf("listing: %w",
+		&TypeRestrictedError{Kind: api.KindDHCPClient, Allowed: []string{api.KindNetworkInterface}})
+	restricted, ok := IsTypeRestricted(err)
+	if !ok {
+		t.Fatalf("IsTypeRestricted(%v) = false, want true", err)
+	}
+	if restricted.Kind != api.KindDHCPClient {
+		t.Errorf("IsTypeRestricted returned kind %s, want %s", restricted.Kind, api.KindDHCPClient)
+	}
+	if _, ok := IsTypeRestricted(fmt.Errorf("plain error")); ok {
+		t.Error("IsTypeRestricted matched a plain error")
+	}
+}
+
+func TestForceUpdateTransfersOwnership(t *testing.T) {
+	base := NewMemoryStore()
+	ctx := context.Background()
+
+	dead := NewOwnershipStore(base, "dead-generator")
+	if err := dead.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	operator := NewOwnershipStore(base, "operator")
+	updated := testInterface("eth0", "aa:bb:cc:dd:ee:01")
+	updated.GetNetworkInterface().Mtu = 9000
+
+	if err := operator.Update(ctx, updated); err == nil {
+		t.Fatal("Update across owners succeeded without force")
+	} else if _, ok := IsOwnership(err); !ok {
+		t.Fatalf("Update across owners = %v, want OwnershipError", err)
+	}
+
+	if err := operator.ForceUpdate(ctx, updated); err != nil {
+		t.Fatalf("ForceUpdate: %v", err)
+	}
+	res, err := base.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := res.GetMetadata().GetOwner(); got != "operator" {
+		t.Errorf("owner after force = %q, want %q", got, "operator")
+	}
+	if got := res.GetMetadata().GetAnnotations()[PreviousOwnerAnnotation]; got != "dead-generator" {
+		t.Errorf("previous-owner annotation = %q, want %q", got, "dead-generator")
+	}
+	if got := res.GetNetworkInterface().GetMtu(); got != 9000 {
+		t.Errorf("mtu after force = %d, want 9000", got)
+	}
+
+	// Ownership has moved: the operator now updates normally and the old
+	// owner is locked out.
+	if err := operator.Update(ctx, updated); err != nil {
+		t.Errorf("Update by new owner: %v", err)
+	}
+	if err := dead.Update(ctx, updated); err == nil {
+		t.Error("Update by previous owner succeeded after transfer")
+	}
+}
+
+func TestTransferOwnerRequiresOwnership(t *testing.T) {
+	base := NewMemoryStore()
+	ctx := context.Background()
+
+	owner := NewOwnershipStore(base, "network-interfaces")
+	if err := owner.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	stranger := NewOwnershipStore(base, "stranger")
+	if err := stranger.TransferOwner(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0", "stranger"); err == nil {
+		t.Error("TransferOwner by non-owner succeeded")
+	}
+	if err := owner.TransferOwner(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0", "successor"); err != nil {
+		t.Fatalf("TransferOwner by owner: %v", err)
+	}
+	res, _ := base.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if got := res.GetMetadata().GetOwner(); got != "successor" {
+		t.Errorf("owner = %q, want %q", got, "successor")
+	}
+}
+
+func TestWritersMayUpdateAndDelete(t *testing.T) {
+	ctx := context.Background()
+	base := NewMemoryStore()
+	owner := NewOwnershipStore(base, "network-interfaces")
+	writer := NewOwnershipStore(base, "dhcp")
+	stranger := NewOwnershipStore(base, "routes")
+
+	res := testInterface("eth0", "aa:bb:cc:dd:ee:ff")
+	res.Metadata.Writers = []string{"dhcp"}
+	if err := owner.Create(ctx, res); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := writer.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got.GetNetworkInterface().Mtu = 9000
+	if err := writer.Update(ctx, got); err != nil {
+		t.Errorf("Update by listed writer: %v", err)
+	}
+
+	got, err = stranger.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got.GetNetworkInterface().Mtu = 1280
+	if err := stranger.Update(ctx, got); err == nil {
+		t.Error("Update by unlisted owner succeeded")
+	} else if _, ok := IsOwnership(err); !ok {
+		t.Errorf("Update by unlisted owner = %v, want OwnershipError", err)
+	}
+
+	if err := writer.Delete(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); err != nil {
+		t.Errorf("Delete by listed writer: %v", err)
+	}
+}
+
+func TestOnlyOwnerControlsWriters(t *testing.T) {
+	ctx := context.Background()
+	base := NewMemoryStore()
+	owner := NewOwnershipStore(base, "network-interfaces")
+	writer := NewOwnershipStore(base, "dhcp")
+
+	res := testInterface("eth0", "aa:bb:cc:dd:ee:ff")
+	res.Metadata.Writers = []string{"dhcp"}
+	if err := owner.Create(ctx, res); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := writer.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got.Metadata.Writers = append(got.Metadata.Writers, "routes")
+	if err := writer.Update(ctx, got); err == nil {
+		t.Error("writer extended the writers list")
+	} else if _, ok := IsOwnership(err); !ok {
+		t.Errorf("writer extending writers = %v, want OwnershipError", err)
+	}
+
+	got, err = owner.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got.Metadata.Writers = []string{"dhcp", "routes"}
+	if err := owner.Update(ctx, got); err != nil {
+		t.Errorf("owner changing the writers list: %v", err)
+	}
+}
+
+func TestListOwnedFiltersForeignResources(t *testing.T) {
+	ctx := context.Background()
+	base := NewMemoryStore()
+	mine := NewOwnershipStore(base, "network-interfaces")
+	other := NewOwnershipStore(base, "dhcp")
+
+	if err := mine.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create eth0: %v", err)
+	}
+	if err := mine.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:02")); err != nil {
+		t.Fatalf("Create eth1: %v", err)
+	}
+	if err := other.Create(ctx, testInterface("wg0", "aa:bb:cc:dd:ee:03")); err != nil {
+		t.Fatalf("Create wg0: %v", err)
+	}
+
+	owned, err := mine.ListOwned(ctx, api.KindNetworkInterface)
+	if err != nil {
+		t.Fatalf("ListOwned: %v", err)
+	}
+	if len(owned) != 2 {
+		t.Fatalf("ListOwned returned %d resources, want 2", len(owned))
+	}
+	for _, res := range owned {
+		if owner := res.GetMetadata().GetOwner(); owner != "network-interfaces" {
+			t.Errorf("ListOwned returned %s owned by %q", res.GetMetadata().GetName(), owner)
+		}
+	}
+
+	// List is unchanged: everything of the kind, regardless of owner.
+	all, err := mine.List(ctx, api.KindNetworkInterface, DefaultNamespace)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("List returned %d resources, want 3", len(all))
+	}
+}
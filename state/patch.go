@@ -0,0 +1,87 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// PatchType selects the patch format accepted by Store.Patch.
+type PatchType string
+
+// PatchTypeMerge is an RFC 7386 JSON merge patch applied to the
+// resource's protojson form: object fields are merged recursively, null
+// deletes a field, and everything else replaces it.
+const PatchTypeMerge PatchType = "merge"
+
+// applyMergePatch applies an RFC 7386 merge patch to the protojson form
+// of res and parses the result back into a resource. Unknown fields in
+// the patch surface as errors rather than silently disappearing.
+func applyMergePatch(res *pb.Resource, patch []byte) (*pb.Resource, error) {
+	original, err := protojson.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	var target, patchDoc any
+	if err := json.Unmarshal(original, &target); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &patchDoc); err != nil {
+		return nil, fmt.Errorf("parsing merge patch: %w", err)
+	}
+	merged, err := json.Marshal(mergeValue(target, patchDoc))
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalJSON(merged)
+}
+
+// mergeValue merges patch into target per RFC 7386: maps merge
+// recursively with null members deleting keys, and any other patch value
+// replaces the target outright.
+func mergeValue(target, patch any) any {
+	patchMap, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetMap, ok := target.(map[string]any)
+	if !ok {
+		targetMap = make(map[string]any)
+	}
+	for key, value := range patchMap {
+		if value == nil {
+			delete(targetMap, key)
+			continue
+		}
+		targetMap[key] = mergeValue(targetMap[key], value)
+	}
+	return targetMap
+}
+
+// patchResource runs the get-merge-update cycle behind Store.Patch
+// against the given store and returns the resource as stored afterwards.
+func patchResource(ctx context.Context, store Store, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error) {
+	if patchType != PatchTypeMerge {
+		return nil, fmt.Errorf("unsupported patch type %q", patchType)
+	}
+	res, err := store.Get(ctx, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := applyMergePatch(res, patch)
+	if err != nil {
+		return nil, err
+	}
+	md, pmd := res.GetMetadata(), patched.GetMetadata()
+	if pmd.GetKind() != md.GetKind() || pmd.GetName() != md.GetName() || pmd.GetNamespace() != md.GetNamespace() {
+		return nil, fmt.Errorf("merge patch must not change the resource identity")
+	}
+	if err := store.Update(ctx, patched); err != nil {
+		return nil, err
+	}
+	return store.Get(ctx, kind, namespace, name)
+}
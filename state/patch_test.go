@@ -0,0 +1,90 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+)
+
+func TestPatchMergesSpecField(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	res := testInterface("eth0", "aa:bb:cc:dd:ee:00")
+	res.GetNetworkInterface().Mtu = 1500
+	if err := store.Create(ctx, res); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	patch := []byte(`{"networkInterface": {"mtu": 9000}}`)
+	patched, err := store.Patch(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0", patch, PatchTypeMerge)
+	if err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	spec := patched.GetNetworkInterface()
+	if got := spec.GetMtu(); got != 9000 {
+		t.Errorf("mtu = %d, want 9000", got)
+	}
+	if got := spec.GetMacAddress(); got != "aa:bb:cc:dd:ee:00" {
+		t.Errorf("mac = %q, want the original preserved", got)
+	}
+	if got := patched.GetMetadata().GetGeneration(); got != 2 {
+		t.Errorf("generation = %d, want 2", got)
+	}
+}
+
+func TestPatchNoOpKeepsGeneration(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	patch := []byte(`{"networkInterface": {"macAddress": "aa:bb:cc:dd:ee:00"}}`)
+	patched, err := store.Patch(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0", patch, PatchTypeMerge)
+	if err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	if got := patched.GetMetadata().GetGeneration(); got != 1 {
+		t.Errorf("generation after no-op patch = %d, want 1", got)
+	}
+}
+
+func TestPatchNullDeletesField(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	res := testInterface("eth0", "aa:bb:cc:dd:ee:00")
+	res.GetNetworkInterface().Mtu = 1500
+	if err := store.Create(ctx, res); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	patch := []byte(`{"networkInterface": {"mtu": null}}`)
+	patched, err := store.Patch(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0", patch, PatchTypeMerge)
+	if err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	if got := patched.GetNetworkInterface().GetMtu(); got != 0 {
+		t.Errorf("mtu after null patch = %d, want cleared", got)
+	}
+}
+
+func TestPatchRejectsIdentityChange(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	patch := []byte(`{"metadata": {"name": "eth1"}}`)
+	if _, err := store.Patch(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0", patch, PatchTypeMerge); err == nil {
+		t.Error("Patch accepted a rename")
+	}
+	if _, err := store.Patch(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0", []byte(`{}`), PatchType("strategic")); err == nil {
+		t.Error("Patch accepted an unknown patch type")
+	}
+}
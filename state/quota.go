@@ -0,0 +1,156 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/microrun/microrun/api/pb"
+)
+
+// QuotaExceededError is returned by Create when a kind has reached its
+// configured resource limit.
+type QuotaExceededError struct {
+	Kind  string
+	Limit int
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("quota exceeded for kind %s: limit %d", e.Kind, e.Limit)
+}
+
+// quotaStore wraps a Store and refuses creates once a kind holds as many
+// resources as its configured limit. Kinds without a limit are unbounded.
+// A mutex serializes the count-and-create so concurrent creates cannot
+// race past the limit together.
+type quotaStore struct {
+	store  Store
+	limits map[string]int
+	mu     sync.Mutex
+}
+
+// NewQuotaStore returns a store that enforces a maximum resource count
+// per kind on Create. Updates and deletes pass through unaffected, and
+// deleting a resource frees capacity for that kind.
+func NewQuotaStore(store Store, limits map[string]int) Store {
+	return &quotaStore{store: store, limits: limits}
+}
+
+func (s *quotaStore) Create(ctx context.Context, res *pb.Resource) error {
+	kind := res.GetMetadata().GetKind()
+	limit, ok := s.limits[kind]
+	if !ok {
+		return s.store.Create(ctx, res)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, err := s.store.Count(ctx, kind, NamespaceAll)
+	if err != nil {
+		return err
+	}
+	if n >= limit {
+		return &QuotaExceededError{Kind: kind, Limit: limit}
+	}
+	return s.store.Create(ctx, res)
+}
+
+func (s *quotaStore) Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	return s.store.Get(ctx, kind, namespace, name)
+}
+
+func (s *quotaStore) Exists(ctx context.Context, kind, namespace, name string) (bool, error) {
+	return s.store.Exists(ctx, kind, namespace, name)
+}
+
+func (s *quotaStore) Update(ctx context.Context, res *pb.Resource) error {
+	return s.store.Update(ctx, res)
+}
+
+func (s *quotaStore) Delete(ctx context.Context, kind, namespace, name string, opts ...DeleteOption) error {
+	return s.store.Delete(ctx, kind, namespace, name, opts...)
+}
+
+func (s *quotaStore) List(ctx context.Context, kind, namespace string) ([]*pb.Resource, error) {
+	return s.store.List(ctx, kind, namespace)
+}
+
+func (s *quotaStore) Count(ctx context.Context, kind, namespace string) (int, error) {
+	return s.store.Count(ctx, kind, namespace)
+}
+
+func (s *quotaStore) Kinds(ctx context.Context) ([]string, error) {
+	return s.store.Kinds(ctx)
+}
+
+func (s *quotaStore) ListAll(ctx context.Context) (map[string][]*pb.Resource, error) {
+	return s.store.ListAll(ctx)
+}
+
+// Replace checks the size of the desired set against the kind's limit:
+// after the swap the kind holds exactly the desired resources.
+func (s *quotaStore) Replace(ctx context.Context, kind string, desired []*pb.Resource) error {
+	if limit, ok := s.limits[kind]; ok && len(desired) > limit {
+		return &QuotaExceededError{Kind: kind, Limit: limit}
+	}
+	return s.store.Replace(ctx, kind, desired)
+}
+
+func (s *quotaStore) Close() error {
+	return s.store.Close()
+}
+
+func (s *quotaStore) Patch(ctx context.Context, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error) {
+	return s.store.Patch(ctx, kind, namespace, name, patch, patchType)
+}
+
+func (s *quotaStore) ListPage(ctx context.Context, kind, namespace string, opts PageOptions) (ListResult, error) {
+	return s.store.ListPage(ctx, kind, namespace, opts)
+}
+
+func (s *quotaStore) Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.Watch(ctx, kind, namespace, opts...)
+}
+
+func (s *quotaStore) WatchResource(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchResource(ctx, kind, namespace, name, opts...)
+}
+
+func (s *quotaStore) WatchKinds(ctx context.Context, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchKinds(ctx, kinds, namespace, opts...)
+}
+
+func (s *quotaStore) WatchAll(ctx context.Context, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchAll(ctx, opts...)
+}
+
+func (s *quotaStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.store.Transaction(ctx, fn)
+}
+
+func (s *quotaStore) AddIndex(kind, indexName string, fn func(*pb.Resource) []string) error {
+	return s.store.AddIndex(kind, indexName, fn)
+}
+
+func (s *quotaStore) RegisterAdmission(kind string, fn AdmissionFunc) {
+	s.store.RegisterAdmission(kind, fn)
+}
+
+func (s *quotaStore) BeginBatch() {
+	s.store.BeginBatch()
+}
+
+func (s *quotaStore) CommitBatch() {
+	s.store.CommitBatch()
+}
+
+func (s *quotaStore) GetByIndex(ctx context.Context, kind, indexName, value string) ([]*pb.Resource, error) {
+	return s.store.GetByIndex(ctx, kind, indexName, value)
+}
+
+func (s *quotaStore) SetObservedGeneration(ctx context.Context, kind, namespace, name string, gen int64) error {
+	return s.store.SetObservedGeneration(ctx, kind, namespace, name, gen)
+}
+
+func (s *quotaStore) TransferOwner(ctx context.Context, kind, namespace, name, owner string) error {
+	return s.store.TransferOwner(ctx, kind, namespace, name, owner)
+}
@@ -0,0 +1,101 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+)
+
+func TestQuotaStoreEnforcesLimit(t *testing.T) {
+	ctx := context.Background()
+	store := NewQuotaStore(NewMemoryStore(), map[string]int{api.KindNetworkInterface: 2})
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create eth0: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create eth1: %v", err)
+	}
+
+	err := store.Create(ctx, testInterface("eth2", "aa:bb:cc:dd:ee:02"))
+	var quota *QuotaExceededError
+	if !errors.As(err, &quota) {
+		t.Fatalf("Create past limit = %v, want QuotaExceededError", err)
+	}
+	if quota.Kind != api.KindNetworkInterface || quota.Limit != 2 {
+		t.Errorf("QuotaExceededError = %+v, want kind %s limit 2", quota, api.KindNetworkInterface)
+	}
+}
+
+func TestQuotaStoreDeleteFreesCapacity(t *testing.T) {
+	ctx := context.Background()
+	store := NewQuotaStore(NewMemoryStore(), map[string]int{api.KindNetworkInterface: 1})
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create eth0: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:01")); err == nil {
+		t.Fatal("Create at limit succeeded, want QuotaExceededError")
+	}
+	if err := store.Delete(ctx, api.KindNetworkInterface, "", "eth0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Errorf("Create after delete: %v", err)
+	}
+}
+
+func TestQuotaStoreUnlimitedKind(t *testing.T) {
+	ctx := context.Background()
+	store := NewQuotaStore(NewMemoryStore(), map[string]int{})
+
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("eth%d", i)
+		if err := store.Create(ctx, testInterface(name, "aa:bb:cc:dd:ee:ff")); err != nil {
+			t.Fatalf("Create %s: %v", name, err)
+		}
+	}
+}
+
+func TestQuotaStoreConcurrentCreates(t *testing.T) {
+	ctx := context.Background()
+	const limit = 5
+	store := NewQuotaStore(NewMemoryStore(), map[string]int{api.KindNetworkInterface: limit})
+
+	var wg sync.WaitGroup
+	errs := make([]error, 20)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = store.Create(ctx, testInterface(fmt.Sprintf("eth%d", i), "aa:bb:cc:dd:ee:ff"))
+		}(i)
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		var quota *QuotaExceededError
+		if !errors.As(err, &quota) {
+			t.Errorf("unexpected create error: %v", err)
+		}
+	}
+	if created != limit {
+		t.Errorf("%d concurrent creates succeeded, want %d", created, limit)
+	}
+	list, err := store.List(ctx, api.KindNetworkInterface, "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != limit {
+		t.Errorf("store holds %d resources, want %d", len(list), limit)
+	}
+}
@@ -0,0 +1,134 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/microrun/microrun/api/pb"
+)
+
+// ReadOnlyError is returned when a mutation is attempted on a read-only
+// store.
+type ReadOnlyError struct {
+	// Op is the operation that was refused.
+	Op string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("store is read-only: %s not allowed", e.Op)
+}
+
+// readOnlyStore wraps a Store and refuses every mutation. Components that
+// should only observe state are handed one of these so the restriction
+// does not depend on their good behaviour.
+type readOnlyStore struct {
+	store Store
+}
+
+// NewReadOnlyStore returns a store whose reads pass through to store and
+// whose mutations fail with a ReadOnlyError.
+func NewReadOnlyStore(store Store) Store {
+	return &readOnlyStore{store: store}
+}
+
+func (s *readOnlyStore) Create(ctx context.Context, res *pb.Resource) error {
+	return &ReadOnlyError{Op: "create"}
+}
+
+func (s *readOnlyStore) Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	return s.store.Get(ctx, kind, namespace, name)
+}
+
+func (s *readOnlyStore) Exists(ctx context.Context, kind, namespace, name string) (bool, error) {
+	return s.store.Exists(ctx, kind, namespace, name)
+}
+
+func (s *readOnlyStore) Update(ctx context.Context, res *pb.Resource) error {
+	return &ReadOnlyError{Op: "update"}
+}
+
+func (s *readOnlyStore) Delete(ctx context.Context, kind, namespace, name string, opts ...DeleteOption) error {
+	return &ReadOnlyError{Op: "delete"}
+}
+
+func (s *readOnlyStore) List(ctx context.Context, kind, namespace string) ([]*pb.Resource, error) {
+	return s.store.List(ctx, kind, namespace)
+}
+
+func (s *readOnlyStore) Count(ctx context.Context, kind, namespace string) (int, error) {
+	return s.store.Count(ctx, kind, namespace)
+}
+
+func (s *readOnlyStore) Kinds(ctx context.Context) ([]string, error) {
+	return s.store.Kinds(ctx)
+}
+
+func (s *readOnlyStore) ListAll(ctx context.Context) (map[string][]*pb.Resource, error) {
+	return s.store.ListAll(ctx)
+}
+
+func (s *readOnlyStore) Replace(ctx context.Context, kind string, desired []*pb.Resource) error {
+	return &ReadOnlyError{Op: "replace"}
+}
+
+// Close on a read-only view is a write on the underlying store and is
+// refused like any other.
+func (s *readOnlyStore) Close() error {
+	return &ReadOnlyError{Op: "close"}
+}
+
+func (s *readOnlyStore) Patch(ctx context.Context, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error) {
+	return nil, &ReadOnlyError{Op: "patch"}
+}
+
+func (s *readOnlyStore) ListPage(ctx context.Context, kind, namespace string, opts PageOptions) (ListResult, error) {
+	return s.store.ListPage(ctx, kind, namespace, opts)
+}
+
+func (s *readOnlyStore) Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.Watch(ctx, kind, namespace, opts...)
+}
+
+func (s *readOnlyStore) WatchResource(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchResource(ctx, kind, namespace, name, opts...)
+}
+
+func (s *readOnlyStore) WatchKinds(ctx context.Context, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchKinds(ctx, kinds, namespace, opts...)
+}
+
+func (s *readOnlyStore) WatchAll(ctx context.Context, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchAll(ctx, opts...)
+}
+
+func (s *readOnlyStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return &ReadOnlyError{Op: "transaction"}
+}
+
+func (s *readOnlyStore) AddIndex(kind, indexName string, fn func(*pb.Resource) []string) error {
+	return s.store.AddIndex(kind, indexName, fn)
+}
+
+func (s *readOnlyStore) RegisterAdmission(kind string, fn AdmissionFunc) {
+	s.store.RegisterAdmission(kind, fn)
+}
+
+func (s *readOnlyStore) BeginBatch() {
+	s.store.BeginBatch()
+}
+
+func (s *readOnlyStore) CommitBatch() {
+	s.store.CommitBatch()
+}
+
+func (s *readOnlyStore) GetByIndex(ctx context.Context, kind, indexName, value string) ([]*pb.Resource, error) {
+	return s.store.GetByIndex(ctx, kind, indexName, value)
+}
+
+func (s *readOnlyStore) SetObservedGeneration(ctx context.Context, kind, namespace, name string, gen int64) error {
+	return &ReadOnlyError{Op: "set observed generation"}
+}
+
+func (s *readOnlyStore) TransferOwner(ctx context.Context, kind, namespace, name, owner string) error {
+	return &ReadOnlyError{Op: "transfer owner"}
+}
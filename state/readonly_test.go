@@ -0,0 +1,51 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+)
+
+func TestReadOnlyStore(t *testing.T) {
+	ctx := context.Background()
+	mem := NewMemoryStore()
+	if err := mem.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	store := NewReadOnlyStore(mem)
+
+	// Reads pass through.
+	if _, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0"); err != nil {
+		t.Errorf("Get: %v", err)
+	}
+	if list, err := store.List(ctx, api.KindNetworkInterface, ""); err != nil || len(list) != 1 {
+		t.Errorf("List = %v, %v", list, err)
+	}
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if _, err := store.Watch(watchCtx, api.KindNetworkInterface, ""); err != nil {
+		t.Errorf("Watch: %v", err)
+	}
+
+	// Every mutation fails with a ReadOnlyError.
+	writes := map[string]error{
+		"Create": store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:00")),
+		"Update": store.Update(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")),
+		"Delete": store.Delete(ctx, api.KindNetworkInterface, "", "eth0"),
+		"Transaction": store.Transaction(ctx, func(tx Store) error {
+			return nil
+		}),
+		"SetObservedGeneration": store.SetObservedGeneration(ctx, api.KindNetworkInterface, "", "eth0", 1),
+	}
+	for op, err := range writes {
+		var readOnly *ReadOnlyError
+		if !errors.As(err, &readOnly) {
+			t.Errorf("%s error = %v, want ReadOnlyError", op, err)
+		}
+	}
+	if _, err := mem.Get(ctx, api.KindNetworkInterface, "", "eth0"); err != nil {
+		t.Errorf("underlying resource disturbed: %v", err)
+	}
+}
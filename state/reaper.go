@@ -0,0 +1,104 @@
+package state
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/microrun/microrun/clock"
+	"github.com/microrun/microrun/logging"
+)
+
+// Reaper deletes resources whose metadata.expires_at has passed. It
+// learns expiry times from a WatchAll stream and checks them on a fixed
+// tick, deleting through the store's normal delete path so finalizers
+// still block removal. Resources without expires_at are never touched.
+type Reaper struct {
+	store    Store
+	interval time.Duration
+	clock    clock.Clock
+	logger   *slog.Logger
+
+	// expiries maps kind/namespace/name to the resource's expiry time.
+	// Only the Run goroutine touches it.
+	expiries map[string]time.Time
+}
+
+// NewReaper returns a reaper sweeping store for expired resources every
+// interval.
+func NewReaper(store Store, interval time.Duration) *Reaper {
+	return &Reaper{
+		store:    store,
+		interval: interval,
+		clock:    clock.Real{},
+		logger:   logging.NewLogger("reaper"),
+		expiries: make(map[string]time.Time),
+	}
+}
+
+// Run watches the store and sweeps expired resources until ctx is
+// cancelled.
+func (r *Reaper) Run(ctx context.Context) error {
+	events, err := r.store.WatchAll(ctx, WithInitialState())
+	if err != nil {
+		return err
+	}
+	ticker := r.clock.Ticker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			r.handleEvent(ev)
+		case <-ticker.C():
+			r.sweep(ctx, r.clock.Now())
+		}
+	}
+}
+
+func (r *Reaper) handleEvent(ev Event) {
+	md := ev.Resource.GetMetadata()
+	key := md.GetKind() + "/" + nsKey(md.GetNamespace(), md.GetName())
+	switch {
+	case ev.Type == EventDeleted:
+		delete(r.expiries, key)
+	case md.GetDeletionTimestamp() != nil:
+		// Deletion is already underway and blocked on finalizers; their
+		// controllers finish it, not the reaper.
+		delete(r.expiries, key)
+	case md.GetExpiresAt() != nil:
+		r.expiries[key] = md.GetExpiresAt().AsTime()
+	default:
+		delete(r.expiries, key)
+	}
+}
+
+// sweep deletes every tracked resource whose expiry is at or before now.
+func (r *Reaper) sweep(ctx context.Context, now time.Time) {
+	for key, expiry := range r.expiries {
+		if expiry.After(now) {
+			continue
+		}
+		kind, namespace, name := splitReaperKey(key)
+		err := r.store.Delete(ctx, kind, namespace, name)
+		if err != nil && !IsNotFound(err) {
+			r.logger.Error("deleting expired resource",
+				"kind", kind, "namespace", namespace, "name", name, "error", err)
+			continue
+		}
+		r.logger.Debug("reaped expired resource",
+			"kind", kind, "namespace", namespace, "name", name)
+		delete(r.expiries, key)
+	}
+}
+
+// splitReaperKey is the inverse of the key built in handleEvent.
+func splitReaperKey(key string) (kind, namespace, name string) {
+	parts := strings.SplitN(key, "/", 3)
+	return parts[0], parts[1], parts[2]
+}
@@ -0,0 +1,116 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func expiringInterface(name, mac string, expiresAt time.Time) *pb.Resource {
+	res := testInterface(name, mac)
+	res.Metadata.ExpiresAt = timestamppb.New(expiresAt)
+	return res
+}
+
+func TestReaperDeletesExpiredResource(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	if err := store.Create(ctx, expiringInterface("eth0", "aa:bb:cc:dd:ee:01", now.Add(-time.Minute))); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Create(ctx, expiringInterface("eth1", "aa:bb:cc:dd:ee:02", now.Add(time.Hour))); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("eth2", "aa:bb:cc:dd:ee:03")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	reaper := NewReaper(store, time.Hour)
+	events, err := store.WatchAll(ctx, WithInitialState())
+	if err != nil {
+		t.Fatalf("WatchAll: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		reaper.handleEvent(<-events)
+	}
+	reaper.sweep(ctx, now)
+
+	if _, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); !IsNotFound(err) {
+		t.Errorf("expired resource still present, Get = %v", err)
+	}
+	if _, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth1"); err != nil {
+		t.Errorf("unexpired resource reaped: %v", err)
+	}
+	if _, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth2"); err != nil {
+		t.Errorf("resource without expiry reaped: %v", err)
+	}
+}
+
+func TestReaperEmitsDeleteEvent(t *testing.T) {
+	store := NewMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := store.Create(ctx, expiringInterface("eth0", "aa:bb:cc:dd:ee:01", time.Now().Add(10*time.Millisecond))); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	events, err := store.Watch(ctx, api.KindNetworkInterface, DefaultNamespace)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	reaper := NewReaper(store, 5*time.Millisecond)
+	go reaper.Run(ctx)
+
+	select {
+	case ev := <-events:
+		if ev.Type != EventDeleted {
+			t.Errorf("event type = %v, want EventDeleted", ev.Type)
+		}
+		if got := ev.Resource.GetMetadata().GetName(); got != "eth0" {
+			t.Errorf("deleted resource = %q, want eth0", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no delete event from the reaper")
+	}
+}
+
+func TestReaperRespectsFinalizers(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	res := expiringInterface("eth0", "aa:bb:cc:dd:ee:01", now.Add(-time.Minute))
+	res.Metadata.Finalizers = []string{"dhcp-release"}
+	if err := store.Create(ctx, res); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	reaper := NewReaper(store, time.Hour)
+	events, err := store.WatchAll(ctx, WithInitialState())
+	if err != nil {
+		t.Fatalf("WatchAll: %v", err)
+	}
+	reaper.handleEvent(<-events)
+	reaper.sweep(ctx, now)
+
+	// The delete marks the resource for deletion but the finalizer keeps
+	// it around; a later sweep must not fight the finalizer's controller.
+	marked, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get after sweep: %v", err)
+	}
+	if marked.GetMetadata().GetDeletionTimestamp() == nil {
+		t.Error("expired resource not marked for deletion")
+	}
+	reaper.handleEvent(<-events)
+	if len(reaper.expiries) != 0 {
+		t.Errorf("reaper still tracks %d resources pending finalizers", len(reaper.expiries))
+	}
+}
@@ -0,0 +1,66 @@
+package state
+
+import (
+	"context"
+	"time"
+)
+
+// watchReconnectBackoff is how long WatchWithReconnect waits after a
+// failed attempt to reopen a watch.
+const watchReconnectBackoff = time.Second
+
+// WatchWithReconnect wraps store.Watch so a watch channel that closes
+// without the context being cancelled is transparently reopened, and
+// events keep flowing on the single returned channel. The first
+// reconnect after events were seen resumes from the last delivered
+// sequence number; a reconnect before any event falls back to
+// WithInitialState. Remote stores can drop a watch at any point, so
+// events may be delivered more than once across a reconnect; consumers
+// must be idempotent. The returned channel closes when ctx is done.
+func WatchWithReconnect(ctx context.Context, store Store, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	events, err := store.Watch(ctx, kind, namespace, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan Event, watchBufferSize)
+	go func() {
+		defer close(out)
+		var lastSeq uint64
+		for {
+			for ev := range events {
+				if ev.Seq > lastSeq {
+					lastSeq = ev.Seq
+				}
+				select {
+				case out <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+			// The underlying watch closed under us; reopen it unless the
+			// watch as a whole was cancelled.
+			if ctx.Err() != nil {
+				return
+			}
+			for {
+				reopened := make([]WatchOption, len(opts), len(opts)+1)
+				copy(reopened, opts)
+				if lastSeq > 0 {
+					reopened = append(reopened, WithResumeFrom(lastSeq))
+				} else {
+					reopened = append(reopened, WithInitialState())
+				}
+				events, err = store.Watch(ctx, kind, namespace, reopened...)
+				if err == nil {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(watchReconnectBackoff):
+				}
+			}
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,122 @@
+package state
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/microrun/microrun/api"
+)
+
+// flakyWatchStore proxies Watch through a channel the test can close,
+// simulating a remote store dropping the stream mid-watch.
+type flakyWatchStore struct {
+	Store
+
+	mu         sync.Mutex
+	watchCalls int
+	dropChan   chan struct{}
+}
+
+func (f *flakyWatchStore) Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	inner, err := f.Store.Watch(ctx, kind, namespace, opts...)
+	if err != nil {
+		return nil, err
+	}
+	drop := make(chan struct{})
+	f.mu.Lock()
+	f.watchCalls++
+	f.dropChan = drop
+	f.mu.Unlock()
+
+	out := make(chan Event, watchBufferSize)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-drop:
+				return
+			case ev, ok := <-inner:
+				if !ok {
+					return
+				}
+				select {
+				case out <- ev:
+				case <-drop:
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// drop closes the current proxied watch stream.
+func (f *flakyWatchStore) drop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	close(f.dropChan)
+}
+
+func (f *flakyWatchStore) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.watchCalls
+}
+
+func TestWatchWithReconnectSurvivesStreamDrop(t *testing.T) {
+	flaky := &flakyWatchStore{Store: NewMemoryStore()}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := WatchWithReconnect(ctx, flaky, api.KindNetworkInterface, DefaultNamespace)
+	if err != nil {
+		t.Fatalf("WatchWithReconnect: %v", err)
+	}
+
+	if err := flaky.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	ev := <-events
+	if got := ev.Resource.GetMetadata().GetName(); got != "eth0" {
+		t.Fatalf("first event for %q, want eth0", got)
+	}
+
+	flaky.drop()
+	if err := flaky.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:02")); err != nil {
+		t.Fatalf("Create after drop: %v", err)
+	}
+
+	select {
+	case ev := <-events:
+		if got := ev.Resource.GetMetadata().GetName(); got != "eth1" {
+			t.Errorf("event after reconnect for %q, want eth1", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event delivered after stream drop")
+	}
+	if calls := flaky.calls(); calls < 2 {
+		t.Errorf("watch opened %d times, want at least 2", calls)
+	}
+}
+
+func TestWatchWithReconnectClosesOnCancel(t *testing.T) {
+	flaky := &flakyWatchStore{Store: NewMemoryStore()}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := WatchWithReconnect(ctx, flaky, api.KindNetworkInterface, DefaultNamespace)
+	if err != nil {
+		t.Fatalf("WatchWithReconnect: %v", err)
+	}
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("event delivered after cancel")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
@@ -0,0 +1,28 @@
+package state
+
+import (
+	"context"
+
+	"github.com/microrun/microrun/api/pb"
+)
+
+// RefGetter is the optional interface of stores that can hand out a
+// resource without the defensive deep copy Get performs. The returned
+// resource is a read-only view into the store's own data: callers must
+// not mutate it, directly or through retained sub-messages. It exists
+// for hot reconcile paths where the per-read clone of a large resource
+// is a measurable cost; everything else should use Get.
+type RefGetter interface {
+	GetRef(ctx context.Context, kind, namespace, name string) (*pb.Resource, error)
+}
+
+// GetRef returns the named resource without cloning when store supports
+// it, falling back to a regular (cloning) Get otherwise. The result must
+// be treated as read-only either way: whether a copy was made depends on
+// the store, so mutating it is never safe.
+func GetRef(ctx context.Context, store Store, kind, namespace, name string) (*pb.Resource, error) {
+	if ref, ok := store.(RefGetter); ok {
+		return ref.GetRef(ctx, kind, namespace, name)
+	}
+	return store.Get(ctx, kind, namespace, name)
+}
@@ -0,0 +1,76 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+)
+
+func TestGetStillReturnsIndependentCopy(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	res.GetNetworkInterface().MacAddress = "ff:ff:ff:ff:ff:ff"
+
+	again, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get again: %v", err)
+	}
+	if got := again.GetNetworkInterface().GetMacAddress(); got != "aa:bb:cc:dd:ee:00" {
+		t.Errorf("mutating a Get result leaked into the store: mac = %q", got)
+	}
+}
+
+func TestGetRefFallsBackForDecoratedStores(t *testing.T) {
+	store := NewReadOnlyStore(NewMemoryStore())
+	ctx := context.Background()
+
+	if _, err := GetRef(ctx, store, api.KindNetworkInterface, DefaultNamespace, "eth0"); !IsNotFound(err) {
+		t.Errorf("GetRef through a decorator = %v, want the fallback Get's NotFoundError", err)
+	}
+}
+
+func benchmarkStore(b *testing.B) Store {
+	store := NewMemoryStore()
+	res := testInterface("eth0", "aa:bb:cc:dd:ee:00")
+	for i := 0; i < 64; i++ {
+		res.GetNetworkInterface().IpAddresses = append(
+			res.GetNetworkInterface().IpAddresses, "192.0.2.1/24")
+	}
+	if err := store.Create(context.Background(), res); err != nil {
+		b.Fatalf("Create: %v", err)
+	}
+	return store
+}
+
+func BenchmarkGet(b *testing.B) {
+	store := benchmarkStore(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetRef(b *testing.B) {
+	store := benchmarkStore(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := GetRef(ctx, store, api.KindNetworkInterface, DefaultNamespace, "eth0"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/microrun/microrun/api/pb"
+)
+
+// replaceAll diffs desired against the current contents of kind through
+// store and issues the creates, updates, and deletes that make them
+// match. Replace implementations run it inside a transaction so the
+// whole swap commits atomically. Per-resource failures do not stop the
+// sweep; they are aggregated into a MultiError so the caller sees every
+// bad item at once.
+func replaceAll(ctx context.Context, store Store, kind string, desired []*pb.Resource) error {
+	for _, res := range desired {
+		if got := res.GetMetadata().GetKind(); got != kind {
+			return fmt.Errorf("replacing kind %s: resource %q has kind %q",
+				kind, res.GetMetadata().GetName(), got)
+		}
+	}
+	existing, err := store.List(ctx, kind, NamespaceAll)
+	if err != nil {
+		return err
+	}
+	current := make(map[string]*pb.Resource, len(existing))
+	for _, res := range existing {
+		current[nsKey(res.GetMetadata().GetNamespace(), res.GetMetadata().GetName())] = res
+	}
+	var multi MultiError
+	keep := make(map[string]bool, len(desired))
+	for _, res := range desired {
+		key := nsKey(res.GetMetadata().GetNamespace(), res.GetMetadata().GetName())
+		keep[key] = true
+		if _, ok := current[key]; ok {
+			if err := store.Update(ctx, res); err != nil {
+				multi.Add(kind, res.GetMetadata().GetName(), err)
+			}
+			continue
+		}
+		if err := store.Create(ctx, res); err != nil {
+			multi.Add(kind, res.GetMetadata().GetName(), err)
+		}
+	}
+	for key, res := range current {
+		if keep[key] {
+			continue
+		}
+		if err := store.Delete(ctx, kind, res.GetMetadata().GetNamespace(), res.GetMetadata().GetName()); err != nil {
+			multi.Add(kind, res.GetMetadata().GetName(), err)
+		}
+	}
+	return multi.ErrOrNil()
+}
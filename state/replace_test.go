@@ -0,0 +1,163 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+)
+
+func TestReplaceAppliesCreatesUpdatesDeletes(t *testing.T) {
+	store := NewMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	events, err := store.Watch(ctx, api.KindNetworkInterface, DefaultNamespace)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	// eth0 vanishes, eth1 changes, eth2 is new.
+	changed := testInterface("eth1", "aa:bb:cc:dd:ee:01")
+	changed.GetNetworkInterface().Mtu = 9000
+	desired := []*pb.Resource{changed, testInterface("eth2", "aa:bb:cc:dd:ee:02")}
+	if err := store.Replace(ctx, api.KindNetworkInterface, desired); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+
+	if _, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); !IsNotFound(err) {
+		t.Errorf("eth0 still present after Replace, Get = %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth1")
+	if err != nil {
+		t.Fatalf("Get eth1: %v", err)
+	}
+	if got := res.GetNetworkInterface().GetMtu(); got != 9000 {
+		t.Errorf("eth1 mtu = %d, want 9000", got)
+	}
+	if got := res.GetMetadata().GetGeneration(); got != 2 {
+		t.Errorf("eth1 generation = %d, want 2", got)
+	}
+	if _, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth2"); err != nil {
+		t.Errorf("Get eth2: %v", err)
+	}
+
+	got := map[EventType]int{}
+	for i := 0; i < 3; i++ {
+		ev := <-events
+		got[ev.Type]++
+	}
+	if got[EventCreated] != 1 || got[EventUpdated] != 1 || got[EventDeleted] != 1 {
+		t.Errorf("events = %v, want one of each type", got)
+	}
+	select {
+	case ev := <-events:
+		t.Errorf("unexpected extra event %v for %s", ev.Type, ev.Resource.GetMetadata().GetName())
+	default:
+	}
+}
+
+func TestReplaceIdenticalSetIsNoOp(t *testing.T) {
+	store := NewMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	events, err := store.Watch(ctx, api.KindNetworkInterface, DefaultNamespace)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	if err := store.Replace(ctx, api.KindNetworkInterface, []*pb.Resource{testInterface("eth0", "aa:bb:cc:dd:ee:00")}); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	res, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := res.GetMetadata().GetGeneration(); got != 1 {
+		t.Errorf("generation after identical Replace = %d, want 1", got)
+	}
+	select {
+	case ev := <-events:
+		t.Errorf("identical Replace emitted a %v event", ev.Type)
+	default:
+	}
+}
+
+func TestReplaceRejectsForeignKind(t *testing.T) {
+	store := NewMemoryStore()
+	route := &pb.Resource{
+		Metadata: &pb.ResourceMetadata{Name: "default", Kind: api.KindRoute},
+		Spec:     &pb.Resource_Route{Route: &pb.Route{Destination: "0.0.0.0/0"}},
+	}
+	err := store.Replace(context.Background(), api.KindNetworkInterface, []*pb.Resource{route})
+	if err == nil {
+		t.Error("Replace accepted a resource of a different kind")
+	}
+}
+
+func TestReplaceRespectsFinalizers(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	res := testInterface("eth0", "aa:bb:cc:dd:ee:00")
+	res.Metadata.Finalizers = []string{"dhcp-release"}
+	if err := store.Create(ctx, res); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := store.Replace(ctx, api.KindNetworkInterface, nil); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	marked, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if marked.GetMetadata().GetDeletionTimestamp() == nil {
+		t.Error("finalized resource removed outright instead of being marked")
+	}
+}
+
+func TestReplaceAggregatesPerResourceErrors(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	store.RegisterAdmission(api.KindNetworkInterface, func(ctx context.Context, op AdmissionOp, old, new *pb.Resource) error {
+		if name := new.GetMetadata().GetName(); name == "bad0" || name == "bad1" {
+			return errors.New("rejected by policy")
+		}
+		return nil
+	})
+
+	desired := []*pb.Resource{
+		testInterface("eth0", "aa:bb:cc:dd:ee:00"),
+		testInterface("bad0", "aa:bb:cc:dd:ee:01"),
+		testInterface("bad1", "aa:bb:cc:dd:ee:02"),
+	}
+	err := store.Replace(ctx, api.KindNetworkInterface, desired)
+	if err == nil {
+		t.Fatal("Replace with two bad items succeeded")
+	}
+	multi, ok := IsMultiError(err)
+	if !ok {
+		t.Fatalf("Replace = %v, want MultiError", err)
+	}
+	if len(multi.Errors) != 2 {
+		t.Fatalf("MultiError holds %d errors, want 2: %v", len(multi.Errors), multi)
+	}
+	for _, key := range []string{api.KindNetworkInterface + "/bad0", api.KindNetworkInterface + "/bad1"} {
+		if multi.Errors[key] == nil {
+			t.Errorf("MultiError is missing key %s: %v", key, multi)
+		}
+	}
+}
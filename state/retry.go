@@ -0,0 +1,189 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/microrun/microrun/api/pb"
+)
+
+// RetryPolicy bounds how a RetryStore retries failed mutations.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries per operation, including
+	// the first.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry; it doubles
+	// after every failed attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy retries a handful of times with a short, doubling
+// backoff, suited to transient contention on a local store.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 50 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+}
+
+// RetryStore wraps a Store and retries Create, Update, and Delete on
+// transient errors with exponential backoff. Which errors count as
+// transient is decided by the retryable predicate; ConflictError,
+// OwnershipError, and TypeRestrictedError are never retried, since
+// retrying them verbatim cannot succeed. Reads pass through.
+type RetryStore struct {
+	store     Store
+	policy    RetryPolicy
+	retryable func(error) bool
+}
+
+// NewRetryStore wraps store with the given retry policy. A nil retryable
+// predicate retries every error except the always-non-retryable ones.
+func NewRetryStore(store Store, policy RetryPolicy, retryable func(error) bool) *RetryStore {
+	return &RetryStore{store: store, policy: policy, retryable: retryable}
+}
+
+func (s *RetryStore) shouldRetry(err error) bool {
+	var conflict *ConflictError
+	var ownership *OwnershipError
+	var restricted *TypeRestrictedError
+	if errors.As(err, &conflict) || errors.As(err, &ownership) || errors.As(err, &restricted) {
+		return false
+	}
+	return s.retryable == nil || s.retryable(err)
+}
+
+// retry runs op until it succeeds, fails with a non-retryable error, or
+// the attempt budget is spent, whichever comes first.
+func (s *RetryStore) retry(ctx context.Context, op func() error) error {
+	backoff := s.policy.InitialBackoff
+	var err error
+	for attempt := 0; attempt < s.policy.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > s.policy.MaxBackoff {
+				backoff = s.policy.MaxBackoff
+			}
+		}
+		if err = op(); err == nil {
+			return nil
+		}
+		if !s.shouldRetry(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (s *RetryStore) Create(ctx context.Context, res *pb.Resource) error {
+	return s.retry(ctx, func() error { return s.store.Create(ctx, res) })
+}
+
+func (s *RetryStore) Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	return s.store.Get(ctx, kind, namespace, name)
+}
+
+func (s *RetryStore) Exists(ctx context.Context, kind, namespace, name string) (bool, error) {
+	return s.store.Exists(ctx, kind, namespace, name)
+}
+
+func (s *RetryStore) Update(ctx context.Context, res *pb.Resource) error {
+	return s.retry(ctx, func() error { return s.store.Update(ctx, res) })
+}
+
+func (s *RetryStore) Delete(ctx context.Context, kind, namespace, name string, opts ...DeleteOption) error {
+	return s.retry(ctx, func() error { return s.store.Delete(ctx, kind, namespace, name, opts...) })
+}
+
+func (s *RetryStore) List(ctx context.Context, kind, namespace string) ([]*pb.Resource, error) {
+	return s.store.List(ctx, kind, namespace)
+}
+
+func (s *RetryStore) Count(ctx context.Context, kind, namespace string) (int, error) {
+	return s.store.Count(ctx, kind, namespace)
+}
+
+func (s *RetryStore) Kinds(ctx context.Context) ([]string, error) {
+	return s.store.Kinds(ctx)
+}
+
+func (s *RetryStore) ListAll(ctx context.Context) (map[string][]*pb.Resource, error) {
+	return s.store.ListAll(ctx)
+}
+
+func (s *RetryStore) Replace(ctx context.Context, kind string, desired []*pb.Resource) error {
+	return s.retry(ctx, func() error { return s.store.Replace(ctx, kind, desired) })
+}
+
+func (s *RetryStore) Close() error {
+	return s.store.Close()
+}
+
+func (s *RetryStore) Patch(ctx context.Context, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error) {
+	var res *pb.Resource
+	err := s.retry(ctx, func() error {
+		var err error
+		res, err = s.store.Patch(ctx, kind, namespace, name, patch, patchType)
+		return err
+	})
+	return res, err
+}
+
+func (s *RetryStore) ListPage(ctx context.Context, kind, namespace string, opts PageOptions) (ListResult, error) {
+	return s.store.ListPage(ctx, kind, namespace, opts)
+}
+
+func (s *RetryStore) Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.Watch(ctx, kind, namespace, opts...)
+}
+
+func (s *RetryStore) WatchResource(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchResource(ctx, kind, namespace, name, opts...)
+}
+
+func (s *RetryStore) WatchKinds(ctx context.Context, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchKinds(ctx, kinds, namespace, opts...)
+}
+
+func (s *RetryStore) WatchAll(ctx context.Context, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchAll(ctx, opts...)
+}
+
+func (s *RetryStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.store.Transaction(ctx, fn)
+}
+
+func (s *RetryStore) AddIndex(kind, indexName string, fn func(*pb.Resource) []string) error {
+	return s.store.AddIndex(kind, indexName, fn)
+}
+
+func (s *RetryStore) RegisterAdmission(kind string, fn AdmissionFunc) {
+	s.store.RegisterAdmission(kind, fn)
+}
+
+func (s *RetryStore) BeginBatch() {
+	s.store.BeginBatch()
+}
+
+func (s *RetryStore) CommitBatch() {
+	s.store.CommitBatch()
+}
+
+func (s *RetryStore) GetByIndex(ctx context.Context, kind, indexName, value string) ([]*pb.Resource, error) {
+	return s.store.GetByIndex(ctx, kind, indexName, value)
+}
+
+func (s *RetryStore) SetObservedGeneration(ctx context.Context, kind, namespace, name string, gen int64) error {
+	return s.retry(ctx, func() error { return s.store.SetObservedGeneration(ctx, kind, namespace, name, gen) })
+}
+
+func (s *RetryStore) TransferOwner(ctx context.Context, kind, namespace, name, owner string) error {
+	return s.retry(ctx, func() error { return s.store.TransferOwner(ctx, kind, namespace, name, owner) })
+}
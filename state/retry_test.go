@@ -0,0 +1,92 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/microrun/microrun/api/pb"
+)
+
+// flakyStore fails mutations with err until failures is exhausted.
+type flakyStore struct {
+	Store
+	failures int
+	err      error
+	attempts int
+}
+
+func (f *flakyStore) Create(ctx context.Context, res *pb.Resource) error {
+	f.attempts++
+	if f.failures > 0 {
+		f.failures--
+		return f.err
+	}
+	return f.Store.Create(ctx, res)
+}
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond}
+}
+
+func TestRetryStoreRetriesTransientErrors(t *testing.T) {
+	ctx := context.Background()
+	flaky := &flakyStore{Store: NewMemoryStore(), failures: 2, err: errors.New("transient")}
+	store := NewRetryStore(flaky, testRetryPolicy(), nil)
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if flaky.attempts != 3 {
+		t.Errorf("attempts = %d, want 3", flaky.attempts)
+	}
+}
+
+func TestRetryStoreGivesUpAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	transient := errors.New("transient")
+	flaky := &flakyStore{Store: NewMemoryStore(), failures: 100, err: transient}
+	store := NewRetryStore(flaky, testRetryPolicy(), nil)
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); !errors.Is(err, transient) {
+		t.Fatalf("Create error = %v, want %v", err, transient)
+	}
+	if flaky.attempts != 5 {
+		t.Errorf("attempts = %d, want 5", flaky.attempts)
+	}
+}
+
+func TestRetryStoreDoesNotRetryOwnershipErrors(t *testing.T) {
+	ctx := context.Background()
+	flaky := &flakyStore{
+		Store:    NewMemoryStore(),
+		failures: 100,
+		err:      &OwnershipError{Kind: "NetworkInterface", Name: "eth0", Owner: "a", Actor: "b"},
+	}
+	store := NewRetryStore(flaky, testRetryPolicy(), nil)
+
+	var ownership *OwnershipError
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); !errors.As(err, &ownership) {
+		t.Fatalf("Create error = %v, want OwnershipError", err)
+	}
+	if flaky.attempts != 1 {
+		t.Errorf("attempts = %d, want 1", flaky.attempts)
+	}
+}
+
+func TestRetryStoreHonorsPredicate(t *testing.T) {
+	ctx := context.Background()
+	permanent := errors.New("permanent")
+	flaky := &flakyStore{Store: NewMemoryStore(), failures: 100, err: permanent}
+	store := NewRetryStore(flaky, testRetryPolicy(), func(err error) bool {
+		return !errors.Is(err, permanent)
+	})
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); !errors.Is(err, permanent) {
+		t.Fatalf("Create error = %v, want %v", err, permanent)
+	}
+	if flaky.attempts != 1 {
+		t.Errorf("attempts = %d, want 1", flaky.attempts)
+	}
+}
@@ -0,0 +1,254 @@
+package state
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/microrun/microrun/api/pb"
+)
+
+// RouterStore dispatches each operation to a backend chosen by kind,
+// falling back to a default for unmapped kinds. It lets ephemeral
+// observations (say, NetworkInterface) live in a memory store while
+// desired config (say, DHCPClient) goes to a persistent one, behind a
+// single Store handle.
+type RouterStore struct {
+	fallback Store
+	byKind   map[string]Store
+}
+
+// NewRouterStore returns a store routing operations on the kinds in
+// byKind to their mapped backend and everything else to fallback.
+func NewRouterStore(fallback Store, byKind map[string]Store) *RouterStore {
+	return &RouterStore{fallback: fallback, byKind: byKind}
+}
+
+// route picks the backend responsible for kind.
+func (s *RouterStore) route(kind string) Store {
+	if backend, ok := s.byKind[kind]; ok {
+		return backend
+	}
+	return s.fallback
+}
+
+// backends returns the fallback and every distinct mapped backend.
+func (s *RouterStore) backends() []Store {
+	out := []Store{s.fallback}
+	for _, backend := range s.byKind {
+		seen := false
+		for _, have := range out {
+			if have == backend {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			out = append(out, backend)
+		}
+	}
+	return out
+}
+
+func (s *RouterStore) Create(ctx context.Context, res *pb.Resource) error {
+	return s.route(res.GetMetadata().GetKind()).Create(ctx, res)
+}
+
+func (s *RouterStore) Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	return s.route(kind).Get(ctx, kind, namespace, name)
+}
+
+func (s *RouterStore) Exists(ctx context.Context, kind, namespace, name string) (bool, error) {
+	return s.route(kind).Exists(ctx, kind, namespace, name)
+}
+
+func (s *RouterStore) Update(ctx context.Context, res *pb.Resource) error {
+	return s.route(res.GetMetadata().GetKind()).Update(ctx, res)
+}
+
+func (s *RouterStore) Delete(ctx context.Context, kind, namespace, name string, opts ...DeleteOption) error {
+	return s.route(kind).Delete(ctx, kind, namespace, name, opts...)
+}
+
+func (s *RouterStore) List(ctx context.Context, kind, namespace string) ([]*pb.Resource, error) {
+	return s.route(kind).List(ctx, kind, namespace)
+}
+
+func (s *RouterStore) ListPage(ctx context.Context, kind, namespace string, opts PageOptions) (ListResult, error) {
+	return s.route(kind).ListPage(ctx, kind, namespace, opts)
+}
+
+func (s *RouterStore) Count(ctx context.Context, kind, namespace string) (int, error) {
+	return s.route(kind).Count(ctx, kind, namespace)
+}
+
+// Kinds merges the non-empty kinds of every backend, sorted.
+func (s *RouterStore) Kinds(ctx context.Context) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, backend := range s.backends() {
+		kinds, err := backend.Kinds(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, kind := range kinds {
+			seen[kind] = true
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for kind := range seen {
+		out = append(out, kind)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// ListAll merges every backend's resources, each kind taken from the
+// backend it routes to.
+func (s *RouterStore) ListAll(ctx context.Context) (map[string][]*pb.Resource, error) {
+	out := make(map[string][]*pb.Resource)
+	for _, backend := range s.backends() {
+		all, err := backend.ListAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for kind, resources := range all {
+			if s.route(kind) != backend {
+				continue
+			}
+			out[kind] = resources
+		}
+	}
+	return out, nil
+}
+
+func (s *RouterStore) Patch(ctx context.Context, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error) {
+	return s.route(kind).Patch(ctx, kind, namespace, name, patch, patchType)
+}
+
+func (s *RouterStore) Replace(ctx context.Context, kind string, desired []*pb.Resource) error {
+	return s.route(kind).Replace(ctx, kind, desired)
+}
+
+func (s *RouterStore) Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.route(kind).Watch(ctx, kind, namespace, opts...)
+}
+
+func (s *RouterStore) WatchResource(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error) {
+	return s.route(kind).WatchResource(ctx, kind, namespace, name, opts...)
+}
+
+// WatchKinds groups the kinds by backend, opens one watch per backend,
+// and merges the channels. The merged channel closes when every
+// underlying channel has.
+func (s *RouterStore) WatchKinds(ctx context.Context, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	grouped := make(map[Store][]string)
+	for _, kind := range kinds {
+		backend := s.route(kind)
+		grouped[backend] = append(grouped[backend], kind)
+	}
+	channels := make([]<-chan Event, 0, len(grouped))
+	for backend, kinds := range grouped {
+		ch, err := backend.WatchKinds(ctx, kinds, namespace, opts...)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, ch)
+	}
+	return mergeEvents(channels), nil
+}
+
+// WatchAll merges the WatchAll channels of every backend. Events for a
+// kind routed elsewhere than the emitting backend are filtered out, so a
+// resource never shows up twice.
+func (s *RouterStore) WatchAll(ctx context.Context, opts ...WatchOption) (<-chan Event, error) {
+	var channels []<-chan Event
+	for _, backend := range s.backends() {
+		ch, err := backend.WatchAll(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+		backend := backend
+		filtered := make(chan Event, watchBufferSize)
+		go func() {
+			defer close(filtered)
+			for ev := range ch {
+				if s.route(ev.Resource.GetMetadata().GetKind()) != backend {
+					continue
+				}
+				filtered <- ev
+			}
+		}()
+		channels = append(channels, filtered)
+	}
+	return mergeEvents(channels), nil
+}
+
+// mergeEvents fans the given channels into one, closing it when all of
+// them have closed.
+func mergeEvents(channels []<-chan Event) <-chan Event {
+	out := make(chan Event, watchBufferSize)
+	var wg sync.WaitGroup
+	wg.Add(len(channels))
+	for _, ch := range channels {
+		go func(ch <-chan Event) {
+			defer wg.Done()
+			for ev := range ch {
+				out <- ev
+			}
+		}(ch)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Transaction cannot span backends; it runs against the fallback store
+// only. Kinds mapped to other backends must be mutated directly.
+func (s *RouterStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.fallback.Transaction(ctx, fn)
+}
+
+func (s *RouterStore) AddIndex(kind, indexName string, fn func(*pb.Resource) []string) error {
+	return s.route(kind).AddIndex(kind, indexName, fn)
+}
+
+func (s *RouterStore) RegisterAdmission(kind string, fn AdmissionFunc) {
+	s.route(kind).RegisterAdmission(kind, fn)
+}
+
+func (s *RouterStore) BeginBatch() {
+	for _, backend := range s.backends() {
+		backend.BeginBatch()
+	}
+}
+
+func (s *RouterStore) CommitBatch() {
+	for _, backend := range s.backends() {
+		backend.CommitBatch()
+	}
+}
+
+func (s *RouterStore) GetByIndex(ctx context.Context, kind, indexName, value string) ([]*pb.Resource, error) {
+	return s.route(kind).GetByIndex(ctx, kind, indexName, value)
+}
+
+func (s *RouterStore) SetObservedGeneration(ctx context.Context, kind, namespace, name string, gen int64) error {
+	return s.route(kind).SetObservedGeneration(ctx, kind, namespace, name, gen)
+}
+
+func (s *RouterStore) TransferOwner(ctx context.Context, kind, namespace, name, owner string) error {
+	return s.route(kind).TransferOwner(ctx, kind, namespace, name, owner)
+}
+
+// Close closes every backend, returning the first error.
+func (s *RouterStore) Close() error {
+	var first error
+	for _, backend := range s.backends() {
+		if err := backend.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
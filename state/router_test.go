@@ -0,0 +1,112 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+)
+
+func testDHCPClient(name string) *pb.Resource {
+	return &pb.Resource{
+		Metadata: &pb.ResourceMetadata{Name: name, Kind: api.KindDHCPClient},
+		Spec: &pb.Resource_DhcpClient{DhcpClient: &pb.DHCPClient{
+			InterfaceRef: name,
+			Enabled:      true,
+		}},
+	}
+}
+
+func TestRouterStoreDispatchesByKind(t *testing.T) {
+	fallback := NewMemoryStore()
+	persistent := NewMemoryStore()
+	router := NewRouterStore(fallback, map[string]Store{api.KindDHCPClient: persistent})
+	ctx := context.Background()
+
+	if err := router.Create(ctx, testDHCPClient("eth0")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := persistent.Get(ctx, api.KindDHCPClient, DefaultNamespace, "eth0"); err != nil {
+		t.Errorf("mapped kind did not land in its backend: %v", err)
+	}
+	if _, err := fallback.Get(ctx, api.KindDHCPClient, DefaultNamespace, "eth0"); !IsNotFound(err) {
+		t.Errorf("mapped kind leaked into the fallback, Get = %v", err)
+	}
+
+	res, err := router.Get(ctx, api.KindDHCPClient, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get through router: %v", err)
+	}
+	res.GetDhcpClient().Enabled = false
+	if err := router.Update(ctx, res); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	stored, err := persistent.Get(ctx, api.KindDHCPClient, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get from backend: %v", err)
+	}
+	if stored.GetDhcpClient().GetEnabled() {
+		t.Error("update did not reach the mapped backend")
+	}
+	if err := router.Delete(ctx, api.KindDHCPClient, DefaultNamespace, "eth0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := persistent.Get(ctx, api.KindDHCPClient, DefaultNamespace, "eth0"); !IsNotFound(err) {
+		t.Errorf("delete did not reach the mapped backend, Get = %v", err)
+	}
+}
+
+func TestRouterStoreUnmappedKindUsesFallback(t *testing.T) {
+	fallback := NewMemoryStore()
+	persistent := NewMemoryStore()
+	router := NewRouterStore(fallback, map[string]Store{api.KindDHCPClient: persistent})
+	ctx := context.Background()
+
+	if err := router.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := fallback.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); err != nil {
+		t.Errorf("unmapped kind did not land in the fallback: %v", err)
+	}
+	if _, err := persistent.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); !IsNotFound(err) {
+		t.Errorf("unmapped kind leaked into a mapped backend, Get = %v", err)
+	}
+
+	resources, err := router.List(ctx, api.KindNetworkInterface, DefaultNamespace)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(resources) != 1 {
+		t.Errorf("List through router returned %d resources, want 1", len(resources))
+	}
+}
+
+func TestRouterStoreMergesKindsAcrossBackends(t *testing.T) {
+	fallback := NewMemoryStore()
+	persistent := NewMemoryStore()
+	router := NewRouterStore(fallback, map[string]Store{api.KindDHCPClient: persistent})
+	ctx := context.Background()
+
+	if err := router.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := router.Create(ctx, testDHCPClient("eth0")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	kinds, err := router.Kinds(ctx)
+	if err != nil {
+		t.Fatalf("Kinds: %v", err)
+	}
+	want := []string{api.KindDHCPClient, api.KindNetworkInterface}
+	if len(kinds) != 2 || kinds[0] != want[0] || kinds[1] != want[1] {
+		t.Errorf("Kinds = %v, want %v", kinds, want)
+	}
+	all, err := router.ListAll(ctx)
+	if err != nil {
+		t.Fatalf("ListAll: %v", err)
+	}
+	if len(all[api.KindDHCPClient]) != 1 || len(all[api.KindNetworkInterface]) != 1 {
+		t.Errorf("ListAll = %v kinds, want one resource of each kind", len(all))
+	}
+}
@@ -0,0 +1,554 @@
+// Package state implements the resource store at the heart of microrun.
+// Generators read and write desired and observed state through the Store
+// interface; the in-memory implementation is the only backend today.
+package state
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/microrun/microrun/api/pb"
+)
+
+// EventType describes what happened to a resource.
+type EventType int
+
+const (
+	// EventCreated is delivered when a resource is created.
+	EventCreated EventType = iota
+	// EventUpdated is delivered when a resource changes.
+	EventUpdated
+	// EventDeleted is delivered when a resource is removed.
+	EventDeleted
+	// EventBatchEnd is a barrier marking the end of a batch opened with
+	// BeginBatch. It carries no resource; watchers that buffered the
+	// batch's events act on them when it arrives.
+	EventBatchEnd
+)
+
+// String returns a human-readable name for the event type.
+func (t EventType) String() string {
+	switch t {
+	case EventCreated:
+		return "created"
+	case EventUpdated:
+		return "updated"
+	case EventDeleted:
+		return "deleted"
+	case EventBatchEnd:
+		return "batch-end"
+	}
+	return "unknown"
+}
+
+// Event is delivered on watch channels when a resource changes. The
+// resource is a deep copy owned by the receiver.
+type Event struct {
+	Type     EventType
+	Resource *pb.Resource
+	// Seq is a store-wide, monotonically increasing sequence number
+	// assigned when the event is committed. Consumers can persist the
+	// last sequence they processed and resume with WithResumeFrom.
+	Seq uint64
+}
+
+// DefaultNamespace is the namespace resources belong to when their
+// metadata carries none.
+const DefaultNamespace = ""
+
+// NamespaceAll selects every namespace in List and Watch calls.
+const NamespaceAll = "*"
+
+// Store is the interface all resource storage backends implement.
+// Resources returned from reads are deep copies; callers may mutate them
+// freely. All methods are safe for concurrent use.
+type Store interface {
+	// Create stores a new resource. It fails if a resource with the same
+	// kind and name already exists.
+	Create(ctx context.Context, res *pb.Resource) error
+	// Get returns the resource with the given kind, namespace, and name.
+	Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error)
+	// Exists reports whether the named resource is stored, without the
+	// defensive clone Get makes. A missing resource is false, not an
+	// error; only real failures surface as errors.
+	Exists(ctx context.Context, kind, namespace, name string) (bool, error)
+	// Update replaces an existing resource. Updates that do not change
+	// the resource are a no-op and do not bump the generation.
+	Update(ctx context.Context, res *pb.Resource) error
+	// Delete removes the resource with the given kind, namespace, and
+	// name. By default a resource with finalizers is only marked for
+	// deletion and removed once its controllers clear them; see
+	// WithDeletePolicy for the other behaviors.
+	Delete(ctx context.Context, kind, namespace, name string, opts ...DeleteOption) error
+	// List returns all resources of the given kind in the given
+	// namespace; NamespaceAll lists across every namespace.
+	List(ctx context.Context, kind, namespace string) ([]*pb.Resource, error)
+	// ListPage returns one page of resources of the given kind and
+	// namespace, ordered by namespace and name. The result carries a
+	// continue token when more remain.
+	ListPage(ctx context.Context, kind, namespace string, opts PageOptions) (ListResult, error)
+	// Count returns the number of resources of the given kind in the
+	// given namespace without copying them; NamespaceAll counts across
+	// every namespace.
+	Count(ctx context.Context, kind, namespace string) (int, error)
+	// Kinds returns the kinds that currently hold at least one resource,
+	// sorted. Kinds that are registered but empty are not listed.
+	Kinds(ctx context.Context) ([]string, error)
+	// ListAll returns every resource in the store grouped by kind, as
+	// deep copies in the same order List uses. It is the read counterpart
+	// to WatchAll.
+	ListAll(ctx context.Context) (map[string][]*pb.Resource, error)
+	// Patch applies a partial update to the named resource and returns
+	// it as stored. PatchTypeMerge applies an RFC 7386 merge patch to
+	// the resource's protojson form. The patch is applied atomically and
+	// the generation only bumps when it actually changes the spec.
+	Patch(ctx context.Context, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error)
+	// Replace makes the contents of kind exactly the desired set,
+	// applying the necessary creates, updates, and deletes in one
+	// transaction. Unchanged resources are left alone: generations bump
+	// only on real spec changes and only actual changes emit events.
+	// Deletes respect finalizers the same way Delete does.
+	Replace(ctx context.Context, kind string, desired []*pb.Resource) error
+	// Watch returns a channel delivering events for the given kind and
+	// namespace until ctx is cancelled; NamespaceAll watches every
+	// namespace. Registration is atomic with respect to mutations: the
+	// watcher receives every event from mutations that commit after
+	// Watch returns, exactly once. With WithInitialState the snapshot
+	// and the registration happen atomically, so each resource shows up
+	// either in the snapshot or as a later event, never both and never
+	// neither. Cancelling ctx deregisters the watcher and closes the
+	// channel exactly once; events queued at that point stay readable,
+	// and a consumer that keeps receiving drains them all before the
+	// close.
+	Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error)
+	// WatchResource is Watch narrowed to a single resource: only events
+	// for the named resource are delivered, its deletion arriving as an
+	// EventDeleted. Events for other resources of the kind never reach
+	// the channel.
+	WatchResource(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error)
+	// WatchKinds returns a single channel merging events for all the
+	// given kinds. The kind of each event is available on the resource's
+	// metadata. Cancelling ctx tears down all underlying watches.
+	WatchKinds(ctx context.Context, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error)
+	// WatchAll returns a channel delivering events for every kind,
+	// including kinds that gain their first resource after the watch is
+	// registered. Cancelling ctx closes the channel.
+	WatchAll(ctx context.Context, opts ...WatchOption) (<-chan Event, error)
+	// BeginBatch holds back watch deliveries until CommitBatch, so a
+	// change spanning many writes lands on watchers as one consistent
+	// batch instead of a stream of intermediate states. Mutations still
+	// commit immediately; only their events wait.
+	BeginBatch()
+	// CommitBatch delivers the events held since BeginBatch, coalesced
+	// to the final event per resource, followed by an EventBatchEnd
+	// barrier to every watcher that received part of the batch. Without
+	// an open batch it is a no-op.
+	CommitBatch()
+	// Transaction runs fn against a transactional view of the store. All
+	// mutations made through tx commit together when fn returns nil and
+	// are discarded when it returns an error; watch events only fire
+	// after commit. fn must use tx, not the store it was called on.
+	Transaction(ctx context.Context, fn func(tx Store) error) error
+	// AddIndex registers a named index for a kind. fn maps a resource to
+	// the index values it should be found under; the store keeps the
+	// index up to date across mutations. Existing resources are indexed
+	// immediately.
+	AddIndex(kind, indexName string, fn func(*pb.Resource) []string) error
+
+	// RegisterAdmission adds an admission hook for a kind, run on every
+	// create, update, and delete of resources of that kind. Hooks run in
+	// registration order; any of them returning an error rejects the
+	// operation, and mutations a hook makes to the new resource persist.
+	RegisterAdmission(kind string, fn AdmissionFunc)
+	// GetByIndex returns the resources of a kind whose index values
+	// include value, ordered by name. The index must have been registered
+	// with AddIndex.
+	GetByIndex(ctx context.Context, kind, indexName, value string) ([]*pb.Resource, error)
+	// SetObservedGeneration records the generation the reconciling
+	// component has acted on. It does not bump the resource's generation;
+	// the resource is converged when the two are equal.
+	SetObservedGeneration(ctx context.Context, kind, namespace, name string, gen int64) error
+	// TransferOwner reassigns the named resource to owner, recording the
+	// previous owner in the PreviousOwnerAnnotation annotation. It is the
+	// primitive behind forced updates; normal callers go through
+	// OwnershipStore, which keeps the owner check intact.
+	TransferOwner(ctx context.Context, kind, namespace, name, owner string) error
+	// Close shuts the store down: every watcher channel is closed and
+	// further mutations and watches fail with a ClosedError. Closing an
+	// already-closed store is a no-op.
+	Close() error
+}
+
+// PageOptions selects a page of resources from ListPage.
+type PageOptions struct {
+	// Limit is the maximum number of resources to return. Zero means no
+	// limit.
+	Limit int
+	// Continue resumes listing after the page identified by a token from
+	// a previous ListResult. An empty token starts from the beginning.
+	Continue string
+}
+
+// ListResult is one page of resources.
+type ListResult struct {
+	Resources []*pb.Resource
+	// Continue is non-empty when more resources remain. Passing it to
+	// the next ListPage call resumes where this page ended.
+	Continue string
+}
+
+// DeletePolicy selects how Delete treats a resource that has finalizers
+// or an owner.
+type DeletePolicy int
+
+const (
+	// DeleteForeground is the default: a resource with finalizers is
+	// soft-deleted — marked with a deletion timestamp and removed once
+	// its controllers clear the finalizers.
+	DeleteForeground DeletePolicy = iota
+	// DeleteBackground removes the resource immediately, ignoring any
+	// finalizers. Controllers relying on finalizers for cleanup never
+	// get their turn; callers take on that cleanup themselves.
+	DeleteBackground
+	// DeleteOrphan does not remove the resource at all: it clears the
+	// owner and writers so the resource survives its managing generator,
+	// available for another owner to adopt.
+	DeleteOrphan
+)
+
+// DeleteOption configures a Delete call.
+type DeleteOption func(*deleteOptions)
+
+type deleteOptions struct {
+	policy DeletePolicy
+}
+
+// WithDeletePolicy overrides the default foreground deletion behavior.
+func WithDeletePolicy(policy DeletePolicy) DeleteOption {
+	return func(o *deleteOptions) {
+		o.policy = policy
+	}
+}
+
+// resolveDeleteOptions applies opts over the default policy.
+func resolveDeleteOptions(opts []DeleteOption) deleteOptions {
+	var options deleteOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// WatchOption configures a Watch call.
+type WatchOption func(*watchOptions)
+
+type watchOptions struct {
+	initialState bool
+	coalesce     bool
+	predicate    func(*pb.Resource) bool
+	bufferSize   int
+	bufferSet    bool
+	rateLimit    float64
+	rateBurst    int
+	resumeFrom   uint64
+	resumeSet    bool
+	resync       time.Duration
+}
+
+// WithInitialState makes the watch first deliver a synthetic created event
+// for every resource of the kind that exists at registration time, so the
+// watcher does not need a separate List to seed its view.
+func WithInitialState() WatchOption {
+	return func(o *watchOptions) {
+		o.initialState = true
+	}
+}
+
+// WithCoalescing makes the watch collapse queued events per resource: when
+// several events for the same resource pile up behind a slow watcher, only
+// the latest is delivered, except that a queued delete is never replaced.
+// Watchers that only care about the current state use this to avoid
+// reconciling every intermediate update.
+func WithCoalescing() WatchOption {
+	return func(o *watchOptions) {
+		o.coalesce = true
+	}
+}
+
+// WithBufferSize sets the capacity of the watch channel. Small buffers
+// suit memory-constrained deployments; high-churn setups can raise it so
+// writers block less often behind slow watchers. Watches without this
+// option keep the standard buffer of 100 events; Watch rejects sizes
+// that are not positive.
+func WithBufferSize(n int) WatchOption {
+	return func(o *watchOptions) {
+		o.bufferSize = n
+		o.bufferSet = true
+	}
+}
+
+// WithResumeFrom replays the events committed after the given sequence
+// number ahead of the live stream, so a consumer that reconnects can
+// catch up on what it missed. Replay is best-effort from a bounded
+// in-memory ring: events that have already been evicted are gone, and a
+// consumer that needs a complete picture should fall back to
+// WithInitialState.
+func WithResumeFrom(seq uint64) WatchOption {
+	return func(o *watchOptions) {
+		o.resumeFrom = seq
+		o.resumeSet = true
+	}
+}
+
+// WithRateLimit paces event delivery with a token bucket refilled at
+// eventsPerSecond and holding at most burst tokens. Events over budget
+// are buffered, not dropped: a sustained burst backs up into the watch
+// buffer and eventually blocks writers, which is the same backpressure
+// an unpaced slow watcher exerts. Watch rejects a rate or burst that is
+// not positive.
+func WithRateLimit(eventsPerSecond float64, burst int) WatchOption {
+	return func(o *watchOptions) {
+		o.rateLimit = eventsPerSecond
+		o.rateBurst = burst
+	}
+}
+
+// WithPredicate restricts the watch to resources for which fn returns
+// true. When an update moves a resource out of the predicate, the watcher
+// receives a synthetic deleted event so its view stays consistent; events
+// for resources that never matched are suppressed entirely.
+func WithPredicate(fn func(*pb.Resource) bool) WatchOption {
+	return func(o *watchOptions) {
+		o.predicate = fn
+	}
+}
+
+// WithResyncInterval makes the watch re-deliver the current state of
+// every matching resource as a synthetic update event each interval,
+// even when nothing changed. Reconcilers use it as a periodic nudge to
+// re-verify external state. Resync events carry the resource as stored
+// at tick time and a zero sequence number, marking them as synthetic.
+func WithResyncInterval(d time.Duration) WatchOption {
+	return func(o *watchOptions) {
+		o.resync = d
+	}
+}
+
+// watchDrainTimeout bounds how long a cancelled watch keeps waiting for
+// its consumer to accept a queued event before dropping the remainder.
+const watchDrainTimeout = time.Second
+
+// sendEvent delivers v to out, blocking while ctx is live. After
+// cancellation a consumer that keeps receiving still gets every queued
+// event: each one waits up to watchDrainTimeout for the consumer before
+// the rest are dropped. It reports whether v was delivered.
+func sendEvent[E any](ctx context.Context, out chan<- E, v E) bool {
+	select {
+	case out <- v:
+		return true
+	case <-ctx.Done():
+	}
+	select {
+	case out <- v:
+		return true
+	case <-time.After(watchDrainTimeout):
+		return false
+	}
+}
+
+// filterEvents applies a watch predicate to the event stream. It tracks
+// which resources the watcher currently sees so that a resource leaving
+// the predicate produces a synthetic deleted event, and events for
+// resources the watcher has never seen are dropped. The returned channel
+// closes when in closes or ctx is cancelled.
+func filterEvents(ctx context.Context, in <-chan Event, pred func(*pb.Resource) bool) <-chan Event {
+	out := make(chan Event)
+	go func() {
+		defer close(out)
+		seen := make(map[string]bool)
+		for {
+			var ev Event
+			var ok bool
+			select {
+			case ev, ok = <-in:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+			key := eventKey(ev)
+			matches := pred(ev.Resource)
+			switch {
+			case ev.Type == EventDeleted:
+				if !seen[key] && !matches {
+					continue
+				}
+				delete(seen, key)
+			case matches:
+				seen[key] = true
+			case seen[key]:
+				// The resource left the predicate: deliver it as a
+				// delete so the watcher drops it from its view.
+				delete(seen, key)
+				ev = Event{Type: EventDeleted, Resource: ev.Resource}
+			default:
+				continue
+			}
+			select {
+			case out <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// paceEvents forwards events from in at no more than eventsPerSecond,
+// allowing bursts up to burst events, using a token bucket. Events over
+// budget wait rather than being dropped. The returned channel closes
+// when in closes or ctx is cancelled.
+func paceEvents(ctx context.Context, in <-chan Event, eventsPerSecond float64, burst int) <-chan Event {
+	out := make(chan Event)
+	go func() {
+		defer close(out)
+		tokens := float64(burst)
+		last := time.Now()
+		for ev := range in {
+			now := time.Now()
+			tokens += now.Sub(last).Seconds() * eventsPerSecond
+			if tokens > float64(burst) {
+				tokens = float64(burst)
+			}
+			last = now
+			if tokens < 1 {
+				wait := time.Duration((1 - tokens) / eventsPerSecond * float64(time.Second))
+				select {
+				case <-time.After(wait):
+					tokens = 1
+					last = time.Now()
+				case <-ctx.Done():
+					return
+				}
+			}
+			tokens--
+			select {
+			case out <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// eventKey identifies the resource an event is about, for coalescing and
+// predicate bookkeeping.
+func eventKey(ev Event) string {
+	md := ev.Resource.GetMetadata()
+	return md.GetKind() + "/" + md.GetNamespace() + "/" + md.GetName()
+}
+
+// coalesceEvents forwards events from in, collapsing queued events per
+// resource so a slow receiver only sees the latest state. Events are
+// delivered in the order their resources first became pending; a queued
+// delete is never replaced by a later event. The returned channel closes
+// when in closes or ctx is cancelled.
+func coalesceEvents(ctx context.Context, in <-chan Event, coalesced *atomic.Uint64) <-chan Event {
+	out := make(chan Event)
+	go func() {
+		defer close(out)
+		queued := make(map[string]Event)
+		var order []string
+		add := func(ev Event) {
+			key := eventKey(ev)
+			if old, ok := queued[key]; ok {
+				if old.Type != EventDeleted {
+					queued[key] = ev
+				}
+				coalesced.Add(1)
+				return
+			}
+			queued[key] = ev
+			order = append(order, key)
+		}
+		for {
+			if len(order) == 0 {
+				select {
+				case ev, ok := <-in:
+					if !ok {
+						return
+					}
+					add(ev)
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			select {
+			case ev, ok := <-in:
+				if !ok {
+					// Flush what is still queued before closing.
+					for _, key := range order {
+						select {
+						case out <- queued[key]:
+						case <-ctx.Done():
+							return
+						}
+					}
+					return
+				}
+				add(ev)
+			case out <- queued[order[0]]:
+				delete(queued, order[0])
+				order = order[1:]
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// watchKinds implements WatchKinds on top of per-kind Watch calls: it
+// opens one watch per kind and fans the events into a single channel,
+// which closes once every underlying watch has closed.
+func watchKinds(ctx context.Context, store Store, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	var chans []<-chan Event
+	for _, kind := range kinds {
+		ch, err := store.Watch(ctx, kind, namespace, opts...)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		chans = append(chans, ch)
+	}
+
+	merged := make(chan Event)
+	var wg sync.WaitGroup
+	for _, ch := range chans {
+		ch := ch
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ev := range ch {
+				select {
+				case merged <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		cancel()
+		close(merged)
+	}()
+	return merged, nil
+}
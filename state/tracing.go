@@ -0,0 +1,181 @@
+package state
+
+import (
+	"context"
+
+	"github.com/microrun/microrun/api/pb"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingStore wraps a Store and starts an OpenTelemetry span around each
+// Get, List, Create, Update, Delete, and Watch, tagging the kind, name,
+// and action and recording errors. Watch opens one span per subscription,
+// ended when the event channel closes: a span per delivered event would
+// drown a trace in noise, and the subscription lifetime is what matters
+// when chasing reconcile latency.
+type TracingStore struct {
+	store  Store
+	tracer trace.Tracer
+}
+
+// NewTracingStore wraps store, emitting spans through tracer.
+func NewTracingStore(store Store, tracer trace.Tracer) *TracingStore {
+	return &TracingStore{store: store, tracer: tracer}
+}
+
+// span starts a span for one store operation with the common attributes.
+func (s *TracingStore) span(ctx context.Context, action, kind, name string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.String("microrun.store.action", action),
+		attribute.String("microrun.resource.kind", kind),
+	}
+	if name != "" {
+		attrs = append(attrs, attribute.String("microrun.resource.name", name))
+	}
+	return s.tracer.Start(ctx, "store."+action, trace.WithAttributes(attrs...))
+}
+
+// end records err on the span, if any, and ends it.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (s *TracingStore) Create(ctx context.Context, res *pb.Resource) error {
+	ctx, span := s.span(ctx, "create", res.GetMetadata().GetKind(), res.GetMetadata().GetName())
+	err := s.store.Create(ctx, res)
+	end(span, err)
+	return err
+}
+
+func (s *TracingStore) Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	ctx, span := s.span(ctx, "get", kind, name)
+	res, err := s.store.Get(ctx, kind, namespace, name)
+	end(span, err)
+	return res, err
+}
+
+func (s *TracingStore) Exists(ctx context.Context, kind, namespace, name string) (bool, error) {
+	return s.store.Exists(ctx, kind, namespace, name)
+}
+
+func (s *TracingStore) Update(ctx context.Context, res *pb.Resource) error {
+	ctx, span := s.span(ctx, "update", res.GetMetadata().GetKind(), res.GetMetadata().GetName())
+	err := s.store.Update(ctx, res)
+	end(span, err)
+	return err
+}
+
+func (s *TracingStore) Delete(ctx context.Context, kind, namespace, name string, opts ...DeleteOption) error {
+	ctx, span := s.span(ctx, "delete", kind, name)
+	err := s.store.Delete(ctx, kind, namespace, name, opts...)
+	end(span, err)
+	return err
+}
+
+func (s *TracingStore) List(ctx context.Context, kind, namespace string) ([]*pb.Resource, error) {
+	ctx, span := s.span(ctx, "list", kind, "")
+	resources, err := s.store.List(ctx, kind, namespace)
+	end(span, err)
+	return resources, err
+}
+
+// Watch opens the subscription span and hands it to a goroutine that ends
+// it when the event channel closes.
+func (s *TracingStore) Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	ctx, span := s.span(ctx, "watch", kind, "")
+	ch, err := s.store.Watch(ctx, kind, namespace, opts...)
+	if err != nil {
+		end(span, err)
+		return nil, err
+	}
+	out := make(chan Event, watchBufferSize)
+	go func() {
+		defer close(out)
+		defer span.End()
+		delivered := 0
+		for ev := range ch {
+			out <- ev
+			delivered++
+		}
+		span.SetAttributes(attribute.Int("microrun.watch.events", delivered))
+	}()
+	return out, nil
+}
+
+func (s *TracingStore) WatchResource(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchResource(ctx, kind, namespace, name, opts...)
+}
+
+func (s *TracingStore) Count(ctx context.Context, kind, namespace string) (int, error) {
+	return s.store.Count(ctx, kind, namespace)
+}
+
+func (s *TracingStore) Kinds(ctx context.Context) ([]string, error) {
+	return s.store.Kinds(ctx)
+}
+
+func (s *TracingStore) ListAll(ctx context.Context) (map[string][]*pb.Resource, error) {
+	return s.store.ListAll(ctx)
+}
+
+func (s *TracingStore) Patch(ctx context.Context, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error) {
+	return s.store.Patch(ctx, kind, namespace, name, patch, patchType)
+}
+
+func (s *TracingStore) Replace(ctx context.Context, kind string, desired []*pb.Resource) error {
+	return s.store.Replace(ctx, kind, desired)
+}
+
+func (s *TracingStore) ListPage(ctx context.Context, kind, namespace string, opts PageOptions) (ListResult, error) {
+	return s.store.ListPage(ctx, kind, namespace, opts)
+}
+
+func (s *TracingStore) WatchKinds(ctx context.Context, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchKinds(ctx, kinds, namespace, opts...)
+}
+
+func (s *TracingStore) WatchAll(ctx context.Context, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchAll(ctx, opts...)
+}
+
+func (s *TracingStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.store.Transaction(ctx, fn)
+}
+
+func (s *TracingStore) AddIndex(kind, indexName string, fn func(*pb.Resource) []string) error {
+	return s.store.AddIndex(kind, indexName, fn)
+}
+
+func (s *TracingStore) RegisterAdmission(kind string, fn AdmissionFunc) {
+	s.store.RegisterAdmission(kind, fn)
+}
+
+func (s *TracingStore) BeginBatch() {
+	s.store.BeginBatch()
+}
+
+func (s *TracingStore) CommitBatch() {
+	s.store.CommitBatch()
+}
+
+func (s *TracingStore) GetByIndex(ctx context.Context, kind, indexName, value string) ([]*pb.Resource, error) {
+	return s.store.GetByIndex(ctx, kind, indexName, value)
+}
+
+func (s *TracingStore) SetObservedGeneration(ctx context.Context, kind, namespace, name string, gen int64) error {
+	return s.store.SetObservedGeneration(ctx, kind, namespace, name, gen)
+}
+
+func (s *TracingStore) TransferOwner(ctx context.Context, kind, namespace, name, owner string) error {
+	return s.store.TransferOwner(ctx, kind, namespace, name, owner)
+}
+
+func (s *TracingStore) Close() error {
+	return s.store.Close()
+}
@@ -0,0 +1,103 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func tracingTestStore() (*TracingStore, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return NewTracingStore(NewMemoryStore(), provider.Tracer("store")), exporter
+}
+
+func spanAttr(span tracetest.SpanStub, key attribute.Key) string {
+	for _, attr := range span.Attributes {
+		if attr.Key == key {
+			return attr.Value.AsString()
+		}
+	}
+	return ""
+}
+
+func TestTracingStoreSpansOperations(t *testing.T) {
+	store, exporter := tracingTestStore()
+	ctx := context.Background()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2: %v", len(spans), spans.Snapshots())
+	}
+	create, get := spans[0], spans[1]
+	if create.Name != "store.create" || get.Name != "store.get" {
+		t.Errorf("span names = %q, %q", create.Name, get.Name)
+	}
+	if got := spanAttr(get, "microrun.resource.kind"); got != api.KindNetworkInterface {
+		t.Errorf("get span kind = %q, want %s", got, api.KindNetworkInterface)
+	}
+	if got := spanAttr(get, "microrun.resource.name"); got != "eth0" {
+		t.Errorf("get span name attribute = %q, want eth0", got)
+	}
+}
+
+func TestTracingStoreRecordsErrors(t *testing.T) {
+	store, exporter := tracingTestStore()
+
+	if _, err := store.Get(context.Background(), api.KindNetworkInterface, DefaultNamespace, "ghost"); !IsNotFound(err) {
+		t.Fatalf("Get = %v, want NotFoundError", err)
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("span status = %v, want error", spans[0].Status.Code)
+	}
+	if len(spans[0].Events) == 0 {
+		t.Error("failed operation recorded no error event")
+	}
+}
+
+func TestTracingStoreSpansWatchSubscription(t *testing.T) {
+	store, exporter := tracingTestStore()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := store.Watch(ctx, api.KindNetworkInterface, DefaultNamespace)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:00")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	<-events
+	cancel()
+	for range events {
+	}
+
+	var watch *tracetest.SpanStub
+	for _, span := range exporter.GetSpans() {
+		if span.Name == "store.watch" {
+			span := span
+			watch = &span
+		}
+	}
+	if watch == nil {
+		t.Fatal("no span for the watch subscription")
+	}
+	if got := spanAttr(*watch, "microrun.store.action"); got != "watch" {
+		t.Errorf("watch span action = %q, want watch", got)
+	}
+}
@@ -0,0 +1,290 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// TypedResource pairs a resource's metadata with its spec as a concrete
+// message type, sparing callers the oneof handling.
+type TypedResource[T proto.Message] struct {
+	Metadata *pb.ResourceMetadata
+	Spec     T
+
+	status proto.Message
+}
+
+// Status returns the resource's status message, or nil when no status
+// has been recorded. The concrete type depends on the kind; callers
+// assert it to the matching pb status type.
+func (r *TypedResource[T]) Status() proto.Message {
+	return r.status
+}
+
+// TypedEvent is the typed counterpart of Event.
+type TypedEvent[T proto.Message] struct {
+	Type     EventType
+	Resource *TypedResource[T]
+}
+
+// TypedStore wraps a Store with a kind-specific API for a single spec
+// type, derived from the kind registry in the api package.
+type TypedStore[T proto.Message] struct {
+	store Store
+	kind  string
+}
+
+// NewTypedStore returns a typed view over store for the kind registered
+// for the spec type T.
+func NewTypedStore[T proto.Message](store Store) (*TypedStore[T], error) {
+	var zero T
+	kind, err := api.KindFor(zero)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedStore[T]{store: store, kind: kind}, nil
+}
+
+// Kind returns the kind this store is restricted to.
+func (s *TypedStore[T]) Kind() string {
+	return s.kind
+}
+
+// Create stores a new resource with the given name and spec.
+func (s *TypedStore[T]) Create(ctx context.Context, name string, spec T) error {
+	res := &pb.Resource{Metadata: &pb.ResourceMetadata{Name: name, Kind: s.kind}}
+	if err := api.SetSpec(res, spec); err != nil {
+		return err
+	}
+	return s.store.Create(ctx, res)
+}
+
+// Get returns the resource with the given name.
+func (s *TypedStore[T]) Get(ctx context.Context, name string) (*TypedResource[T], error) {
+	res, err := s.store.Get(ctx, s.kind, DefaultNamespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.typed(res)
+}
+
+// Update replaces the spec of the named resource, preserving its
+// metadata. Unchanged specs are a no-op.
+func (s *TypedStore[T]) Update(ctx context.Context, name string, spec T) error {
+	res, err := s.store.Get(ctx, s.kind, DefaultNamespace, name)
+	if err != nil {
+		return err
+	}
+	if err := api.SetSpec(res, spec); err != nil {
+		return err
+	}
+	return s.store.Update(ctx, res)
+}
+
+// CreateOrUpdate stores spec under name, creating the resource if it does
+// not exist and updating it otherwise. An update that does not change the
+// spec is a no-op and leaves the generation alone.
+func (s *TypedStore[T]) CreateOrUpdate(ctx context.Context, name string, spec T) error {
+	res, err := s.store.Get(ctx, s.kind, DefaultNamespace, name)
+	if err != nil {
+		return s.Create(ctx, name, spec)
+	}
+	if err := api.SetSpec(res, spec); err != nil {
+		return err
+	}
+	return s.store.Update(ctx, res)
+}
+
+// CreateMany creates every name-to-spec pair in items inside one
+// transaction: either the whole batch commits or none of it does.
+// Failures are aggregated into a MultiError keyed by kind/name, so a bad
+// batch reports every offending item, not just the first. Names are
+// processed in sorted order.
+func (s *TypedStore[T]) CreateMany(ctx context.Context, items map[string]T) error {
+	return s.store.Transaction(ctx, func(tx Store) error {
+		names := make([]string, 0, len(items))
+		for name := range items {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var multi MultiError
+		for _, name := range names {
+			res := &pb.Resource{Metadata: &pb.ResourceMetadata{Name: name, Kind: s.kind}}
+			if err := api.SetSpec(res, items[name]); err != nil {
+				multi.Add(s.kind, name, err)
+				continue
+			}
+			if err := tx.Create(ctx, res); err != nil {
+				multi.Add(s.kind, name, err)
+			}
+		}
+		return multi.ErrOrNil()
+	})
+}
+
+// GetOrCreate returns the named resource, creating it from defaultSpec
+// when it does not exist yet. A concurrent writer creating the same name
+// between the miss and the Create is handled by re-reading: the caller
+// gets whichever spec won, never an AlreadyExists error.
+func (s *TypedStore[T]) GetOrCreate(ctx context.Context, name string, defaultSpec T) (*TypedResource[T], error) {
+	for {
+		typed, err := s.Get(ctx, name)
+		if err == nil {
+			return typed, nil
+		}
+		if !IsNotFound(err) {
+			return nil, err
+		}
+		err = s.Create(ctx, name, defaultSpec)
+		if err == nil {
+			return s.Get(ctx, name)
+		}
+		if !IsAlreadyExists(err) {
+			return nil, err
+		}
+	}
+}
+
+// Delete removes the named resource.
+func (s *TypedStore[T]) Delete(ctx context.Context, name string) error {
+	return s.store.Delete(ctx, s.kind, DefaultNamespace, name)
+}
+
+// List returns all resources of the store's kind.
+func (s *TypedStore[T]) List(ctx context.Context) ([]*TypedResource[T], error) {
+	resources, err := s.store.List(ctx, s.kind, DefaultNamespace)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*TypedResource[T], 0, len(resources))
+	for _, res := range resources {
+		typed, err := s.typed(res)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, typed)
+	}
+	return out, nil
+}
+
+// ListFiltered returns the resources of the store's kind whose spec
+// satisfies keep, sparing callers the allocation of the full list when
+// only a subset is needed.
+func (s *TypedStore[T]) ListFiltered(ctx context.Context, keep func(T) bool) ([]*TypedResource[T], error) {
+	resources, err := s.store.List(ctx, s.kind, DefaultNamespace)
+	if err != nil {
+		return nil, err
+	}
+	var out []*TypedResource[T]
+	for _, res := range resources {
+		typed, err := s.typed(res)
+		if err != nil {
+			return nil, err
+		}
+		if !keep(typed.Spec) {
+			continue
+		}
+		out = append(out, typed)
+	}
+	return out, nil
+}
+
+// Diff compares desired against the stored spec of the named resource
+// without writing anything, returning a line diff of the prototext forms
+// and whether they differ at all. Controllers use it to see what an
+// update would change before committing it.
+func (s *TypedStore[T]) Diff(ctx context.Context, name string, desired T) (string, bool, error) {
+	res, err := s.store.Get(ctx, s.kind, DefaultNamespace, name)
+	if err != nil {
+		return "", false, err
+	}
+	updated := proto.Clone(res).(*pb.Resource)
+	if err := api.SetSpec(updated, desired); err != nil {
+		return "", false, err
+	}
+	if proto.Equal(res, updated) {
+		return "", false, nil
+	}
+	return DiffResources(res, updated), true, nil
+}
+
+// Watch returns a channel of typed events for the store's kind. The
+// channel closes exactly once, after the underlying watch channel does.
+// Cancelling ctx stops new events at the store, but events already
+// queued still reach a consumer that keeps receiving; only when the
+// consumer stops taking them are the leftovers dropped, so the bridging
+// goroutine cannot leak.
+func (s *TypedStore[T]) Watch(ctx context.Context, opts ...WatchOption) (<-chan TypedEvent[T], error) {
+	ch, err := s.store.Watch(ctx, s.kind, DefaultNamespace, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan TypedEvent[T], watchBufferSize)
+	go func() {
+		defer close(out)
+		// ch closes once the watch is cancelled or the store shuts
+		// down; events queued before the close are still forwarded.
+		for ev := range ch {
+			typed, err := s.typed(ev.Resource)
+			if err != nil {
+				continue
+			}
+			if !sendEvent(ctx, out, TypedEvent[T]{Type: ev.Type, Resource: typed}) {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// UpdateStatus writes only the status of the named resource, leaving
+// the spec untouched. Status-only updates do not bump the spec
+// generation, so generators can report observed state without making
+// their own writes look like config changes.
+func (s *TypedStore[T]) UpdateStatus(ctx context.Context, name string, status proto.Message) error {
+	res, err := s.store.Get(ctx, s.kind, DefaultNamespace, name)
+	if err != nil {
+		return err
+	}
+	if err := api.SetStatus(res, status); err != nil {
+		return err
+	}
+	return s.store.Update(ctx, res)
+}
+
+// WatchFunc is Watch restricted to resources whose typed spec satisfies
+// keep. It rides on the untyped predicate machinery, so an update that
+// moves a resource out of the filter produces a synthetic deleted event
+// and resources that never matched are suppressed entirely.
+func (s *TypedStore[T]) WatchFunc(ctx context.Context, keep func(T) bool, opts ...WatchOption) (<-chan TypedEvent[T], error) {
+	pred := func(res *pb.Resource) bool {
+		spec, err := api.ExtractSpec(res)
+		if err != nil {
+			return false
+		}
+		typed, ok := spec.(T)
+		if !ok {
+			return false
+		}
+		return keep(typed)
+	}
+	return s.Watch(ctx, append(opts, WithPredicate(pred))...)
+}
+
+func (s *TypedStore[T]) typed(res *pb.Resource) (*TypedResource[T], error) {
+	spec, err := api.ExtractSpec(res)
+	if err != nil {
+		return nil, err
+	}
+	typed, ok := spec.(T)
+	if !ok {
+		return nil, fmt.Errorf("resource %s/%s has spec type %T, not %s",
+			res.GetMetadata().GetKind(), res.GetMetadata().GetName(), spec, s.kind)
+	}
+	return &TypedResource[T]{Metadata: res.GetMetadata(), Spec: typed, status: api.ExtractStatus(res)}, nil
+}
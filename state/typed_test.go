@@ -0,0 +1,463 @@
+package state
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+)
+
+func TestTypedStoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewTypedStore[*pb.NetworkInterface](NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+
+	spec := &pb.NetworkInterface{InterfaceName: "eth0", MacAddress: "aa:bb:cc:dd:ee:ff"}
+	if err := store.Create(ctx, "eth0", spec); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := store.Get(ctx, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Spec.GetMacAddress() != "aa:bb:cc:dd:ee:ff" {
+		t.Errorf("mac = %q", got.Spec.GetMacAddress())
+	}
+
+	spec.IpAddresses = []string{"192.0.2.1/24"}
+	if err := store.Update(ctx, "eth0", spec); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err = store.Get(ctx, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Metadata.GetGeneration() != 2 {
+		t.Errorf("generation = %d, want 2", got.Metadata.GetGeneration())
+	}
+
+	list, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("List returned %d resources, want 1", len(list))
+	}
+
+	if err := store.Delete(ctx, "eth0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+}
+
+func TestTypedStoreCreateOrUpdate(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewTypedStore[*pb.NetworkInterface](NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+
+	// Create path.
+	spec := &pb.NetworkInterface{InterfaceName: "eth0", MacAddress: "aa:bb:cc:dd:ee:ff"}
+	if err := store.CreateOrUpdate(ctx, "eth0", spec); err != nil {
+		t.Fatalf("CreateOrUpdate (create): %v", err)
+	}
+	got, err := store.Get(ctx, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Metadata.GetGeneration() != 1 {
+		t.Errorf("generation after create = %d, want 1", got.Metadata.GetGeneration())
+	}
+
+	// Update path.
+	spec.IpAddresses = []string{"192.0.2.1/24"}
+	if err := store.CreateOrUpdate(ctx, "eth0", spec); err != nil {
+		t.Fatalf("CreateOrUpdate (update): %v", err)
+	}
+	got, err = store.Get(ctx, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Metadata.GetGeneration() != 2 {
+		t.Errorf("generation after update = %d, want 2", got.Metadata.GetGeneration())
+	}
+
+	// No-op path: the same spec again must not bump the generation.
+	if err := store.CreateOrUpdate(ctx, "eth0", spec); err != nil {
+		t.Fatalf("CreateOrUpdate (no-op): %v", err)
+	}
+	got, err = store.Get(ctx, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Metadata.GetGeneration() != 2 {
+		t.Errorf("generation after no-op = %d, want 2", got.Metadata.GetGeneration())
+	}
+}
+
+func TestTypedStoreWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	base := NewMemoryStore()
+	store, err := NewTypedStore[*pb.NetworkInterface](base)
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+
+	ch, err := store.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if err := store.Create(ctx, "eth0", &pb.NetworkInterface{InterfaceName: "eth0"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	ev := <-ch
+	if ev.Type != EventCreated || ev.Resource.Spec.GetInterfaceName() != "eth0" {
+		t.Errorf("got event %v %q", ev.Type, ev.Resource.Spec.GetInterfaceName())
+	}
+}
+
+func TestTypedStoreListFiltered(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewTypedStore[*pb.NetworkInterface](NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+
+	macs := map[string]string{
+		"eth0": "aa:bb:cc:00:00:01",
+		"eth1": "aa:bb:cc:00:00:02",
+		"wg0":  "de:ad:be:ef:00:01",
+	}
+	for name, mac := range macs {
+		spec := &pb.NetworkInterface{InterfaceName: name, MacAddress: mac}
+		if err := store.Create(ctx, name, spec); err != nil {
+			t.Fatalf("Create %s: %v", name, err)
+		}
+	}
+
+	list, err := store.ListFiltered(ctx, func(spec *pb.NetworkInterface) bool {
+		return strings.HasPrefix(spec.GetMacAddress(), "aa:bb:cc")
+	})
+	if err != nil {
+		t.Fatalf("ListFiltered: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("ListFiltered returned %d resources, want 2", len(list))
+	}
+	for _, res := range list {
+		if !strings.HasPrefix(res.Spec.GetMacAddress(), "aa:bb:cc") {
+			t.Errorf("unexpected resource %s in filtered list", res.Metadata.GetName())
+		}
+	}
+}
+
+func TestTypedStoreDiff(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewTypedStore[*pb.NetworkInterface](NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+	if err := store.Create(ctx, "eth0", &pb.NetworkInterface{InterfaceName: "eth0", Mtu: 1500}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// An identical spec has no diff.
+	diff, changed, err := store.Diff(ctx, "eth0", &pb.NetworkInterface{InterfaceName: "eth0", Mtu: 1500})
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if changed || diff != "" {
+		t.Errorf("Diff of identical spec = (%q, %v), want empty and unchanged", diff, changed)
+	}
+
+	// A changed field shows up in the diff, and nothing was written.
+	diff, changed, err = store.Diff(ctx, "eth0", &pb.NetworkInterface{InterfaceName: "eth0", Mtu: 9000})
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if !changed {
+		t.Error("Diff of changed spec reports no change")
+	}
+	if !strings.Contains(diff, "mtu") {
+		t.Errorf("diff does not mention mtu:\n%s", diff)
+	}
+	res, err := store.Get(ctx, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if res.Spec.GetMtu() != 1500 {
+		t.Errorf("Diff wrote the desired spec: mtu = %d, want 1500", res.Spec.GetMtu())
+	}
+
+	if _, _, err := store.Diff(ctx, "missing", &pb.NetworkInterface{}); !IsNotFound(err) {
+		t.Errorf("Diff of missing resource = %v, want NotFoundError", err)
+	}
+}
+
+func TestTypedStoreWatchClosesOnCancel(t *testing.T) {
+	store, err := NewTypedStore[*pb.NetworkInterface](NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := store.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("typed channel delivered an event after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("typed channel not closed after cancel")
+	}
+}
+
+func TestTypedStoreWatchDoesNotLeakGoroutines(t *testing.T) {
+	store, err := NewTypedStore[*pb.NetworkInterface](NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 50; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch, err := store.Watch(ctx)
+		if err != nil {
+			t.Fatalf("Watch: %v", err)
+		}
+		cancel()
+		// Wait for the bridge goroutine to close the channel.
+		for range ch {
+		}
+	}
+	// Allow exited goroutines to be reaped before counting.
+	deadline := time.After(time.Second)
+	for runtime.NumGoroutine() > before+2 {
+		select {
+		case <-deadline:
+			t.Fatalf("goroutines grew from %d to %d after 50 watch cycles",
+				before, runtime.NumGoroutine())
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestTypedStoreGetOrCreate(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewTypedStore[*pb.NetworkInterface](NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+
+	created, err := store.GetOrCreate(ctx, "eth0",
+		&pb.NetworkInterface{InterfaceName: "eth0", Mtu: 1500})
+	if err != nil {
+		t.Fatalf("GetOrCreate: %v", err)
+	}
+	if got := created.Spec.GetMtu(); got != 1500 {
+		t.Errorf("created mtu = %d, want the default 1500", got)
+	}
+
+	// A second call returns the stored resource, not the new default.
+	existing, err := store.GetOrCreate(ctx, "eth0",
+		&pb.NetworkInterface{InterfaceName: "eth0", Mtu: 9000})
+	if err != nil {
+		t.Fatalf("GetOrCreate existing: %v", err)
+	}
+	if got := existing.Spec.GetMtu(); got != 1500 {
+		t.Errorf("existing mtu = %d, want 1500", got)
+	}
+}
+
+// racingCreateStore sneaks a competing Create in ahead of the first
+// Create it sees, so the caller's own Create hits AlreadyExists.
+type racingCreateStore struct {
+	Store
+	once sync.Once
+}
+
+func (s *racingCreateStore) Create(ctx context.Context, res *pb.Resource) error {
+	s.once.Do(func() {
+		competitor := testInterface(res.GetMetadata().GetName(), "aa:bb:cc:dd:ee:99")
+		if err := s.Store.Create(ctx, competitor); err != nil {
+			panic(err)
+		}
+	})
+	return s.Store.Create(ctx, res)
+}
+
+func TestTypedStoreGetOrCreateLosesRace(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewTypedStore[*pb.NetworkInterface](&racingCreateStore{Store: NewMemoryStore()})
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+
+	got, err := store.GetOrCreate(ctx, "eth0",
+		&pb.NetworkInterface{InterfaceName: "eth0", MacAddress: "aa:bb:cc:dd:ee:00"})
+	if err != nil {
+		t.Fatalf("GetOrCreate: %v", err)
+	}
+	if mac := got.Spec.GetMacAddress(); mac != "aa:bb:cc:dd:ee:99" {
+		t.Errorf("mac = %q, want the concurrent winner's aa:bb:cc:dd:ee:99", mac)
+	}
+}
+
+func TestTypedStoreCreateManyCommitsAll(t *testing.T) {
+	store := NewMemoryStore()
+	typed, err := NewTypedStore[*pb.NetworkInterface](store)
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+	ctx := context.Background()
+
+	items := map[string]*pb.NetworkInterface{
+		"eth0": {InterfaceName: "eth0"},
+		"eth1": {InterfaceName: "eth1"},
+		"eth2": {InterfaceName: "eth2"},
+	}
+	if err := typed.CreateMany(ctx, items); err != nil {
+		t.Fatalf("CreateMany: %v", err)
+	}
+	for name := range items {
+		if _, err := typed.Get(ctx, name); err != nil {
+			t.Errorf("Get(%s) after CreateMany: %v", name, err)
+		}
+	}
+}
+
+func TestTypedStoreCreateManyDuplicateAbortsBatch(t *testing.T) {
+	store := NewMemoryStore()
+	typed, err := NewTypedStore[*pb.NetworkInterface](store)
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+	ctx := context.Background()
+	if err := typed.Create(ctx, "eth1", &pb.NetworkInterface{InterfaceName: "eth1"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	err = typed.CreateMany(ctx, map[string]*pb.NetworkInterface{
+		"eth0": {InterfaceName: "eth0"},
+		"eth1": {InterfaceName: "eth1"},
+	})
+	if err == nil {
+		t.Fatal("CreateMany with a duplicate name succeeded")
+	}
+	multi, ok := IsMultiError(err)
+	if !ok {
+		t.Fatalf("CreateMany = %v, want MultiError", err)
+	}
+	keyed, ok := multi.Errors[api.KindNetworkInterface+"/eth1"]
+	if !ok || !IsAlreadyExists(keyed) {
+		t.Errorf("MultiError[eth1] = %v, want AlreadyExistsError", keyed)
+	}
+	// The transaction aborted, so the good item must not have committed.
+	if _, err := typed.Get(ctx, "eth0"); !IsNotFound(err) {
+		t.Errorf("eth0 committed despite the failed batch, Get = %v", err)
+	}
+}
+
+func TestTypedStoreWatchFuncGatesEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store, err := NewTypedStore[*pb.NetworkInterface](NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+
+	ch, err := store.WatchFunc(ctx, func(spec *pb.NetworkInterface) bool {
+		return spec.GetMtu() >= 1500
+	})
+	if err != nil {
+		t.Fatalf("WatchFunc: %v", err)
+	}
+
+	// Never matches: suppressed entirely.
+	if err := store.Create(ctx, "eth0", &pb.NetworkInterface{InterfaceName: "eth0", Mtu: 1000}); err != nil {
+		t.Fatalf("Create eth0: %v", err)
+	}
+	// Matches: delivered as created.
+	if err := store.Create(ctx, "eth1", &pb.NetworkInterface{InterfaceName: "eth1", Mtu: 9000}); err != nil {
+		t.Fatalf("Create eth1: %v", err)
+	}
+	ev := <-ch
+	if ev.Type != EventCreated || ev.Resource.Metadata.GetName() != "eth1" {
+		t.Fatalf("got event %v for %q, want created eth1", ev.Type, ev.Resource.Metadata.GetName())
+	}
+
+	// Leaving the filter arrives as a delete-style event.
+	if err := store.Update(ctx, "eth1", &pb.NetworkInterface{InterfaceName: "eth1", Mtu: 1000}); err != nil {
+		t.Fatalf("Update eth1: %v", err)
+	}
+	ev = <-ch
+	if ev.Type != EventDeleted || ev.Resource.Metadata.GetName() != "eth1" {
+		t.Fatalf("got event %v for %q, want synthetic delete for eth1", ev.Type, ev.Resource.Metadata.GetName())
+	}
+
+	// The suppressed resource stays invisible even on delete.
+	if err := store.Delete(ctx, "eth0"); err != nil {
+		t.Fatalf("Delete eth0: %v", err)
+	}
+	select {
+	case ev := <-ch:
+		t.Fatalf("unexpected event %v for %q", ev.Type, ev.Resource.Metadata.GetName())
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestTypedStoreUpdateStatus(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewTypedStore[*pb.NetworkInterface](NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+
+	if err := store.Create(ctx, "eth0", &pb.NetworkInterface{InterfaceName: "eth0"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	got, err := store.Get(ctx, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Status() != nil {
+		t.Errorf("fresh resource has status %v, want nil", got.Status())
+	}
+
+	status := &pb.NetworkInterfaceStatus{OperState: "up", Carrier: true}
+	if err := store.UpdateStatus(ctx, "eth0", status); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	got, err = store.Get(ctx, "eth0")
+	if err != nil {
+		t.Fatalf("Get after UpdateStatus: %v", err)
+	}
+	gotStatus, ok := got.Status().(*pb.NetworkInterfaceStatus)
+	if !ok {
+		t.Fatalf("Status() = %T, want *pb.NetworkInterfaceStatus", got.Status())
+	}
+	if !gotStatus.GetCarrier() || gotStatus.GetOperState() != "up" {
+		t.Errorf("status = %v, want carrier up", gotStatus)
+	}
+	if got.Spec.GetInterfaceName() != "eth0" {
+		t.Errorf("spec lost by status update: %v", got.Spec)
+	}
+	// A status-only update is not a spec change.
+	if gen := got.Metadata.GetGeneration(); gen != 1 {
+		t.Errorf("generation = %d after status update, want 1", gen)
+	}
+}
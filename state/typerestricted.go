@@ -0,0 +1,271 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/microrun/microrun/api/pb"
+)
+
+// TypeRestrictedError is returned when an operation names a kind outside
+// the set a type-restricted store allows.
+type TypeRestrictedError struct {
+	Kind    string
+	Allowed []string
+}
+
+func (e *TypeRestrictedError) Error() string {
+	return fmt.Sprintf("kind %s is not allowed by this store (allowed: %s)",
+		e.Kind, strings.Join(e.Allowed, ", "))
+}
+
+// IsTypeRestricted reports whether err is (or wraps) a
+// TypeRestrictedError, returning the typed error when it does.
+func IsTypeRestricted(err error) (*TypeRestrictedError, bool) {
+	var restricted *TypeRestrictedError
+	if errors.As(err, &restricted) {
+		return restricted, true
+	}
+	return nil, false
+}
+
+// typeRestrictedStore wraps a Store and rejects operations on kinds
+// outside its allow lists, with separate lists for reads and writes. The
+// runtime uses it to scope each generator to its managed and watched
+// kinds.
+type typeRestrictedStore struct {
+	store    Store
+	readable map[string]bool
+	writable map[string]bool
+	// readKinds and writeKinds preserve the registration order for error
+	// messages and WatchAll.
+	readKinds  []string
+	writeKinds []string
+}
+
+// NewTypeRestrictedStore returns a store that only permits operations on
+// the given kinds.
+func NewTypeRestrictedStore(store Store, kinds []string) Store {
+	return NewKindScopedStore(store, kinds, nil)
+}
+
+// NewKindScopedStore returns a store that permits reads on both the
+// managed and watched kinds but writes only on the managed kinds.
+func NewKindScopedStore(store Store, managed, watched []string) Store {
+	s := &typeRestrictedStore{
+		store:      store,
+		readable:   make(map[string]bool, len(managed)+len(watched)),
+		writable:   make(map[string]bool, len(managed)),
+		writeKinds: managed,
+	}
+	for _, k := range managed {
+		s.readable[k] = true
+		s.writable[k] = true
+		s.readKinds = append(s.readKinds, k)
+	}
+	for _, k := range watched {
+		if !s.readable[k] {
+			s.readable[k] = true
+			s.readKinds = append(s.readKinds, k)
+		}
+	}
+	return s
+}
+
+func (s *typeRestrictedStore) check(kind string) error {
+	if !s.readable[kind] {
+		return &TypeRestrictedError{Kind: kind, Allowed: s.readKinds}
+	}
+	return nil
+}
+
+func (s *typeRestrictedStore) checkWrite(kind string) error {
+	if !s.writable[kind] {
+		return &TypeRestrictedError{Kind: kind, Allowed: s.writeKinds}
+	}
+	return nil
+}
+
+func (s *typeRestrictedStore) Create(ctx context.Context, res *pb.Resource) error {
+	if err := s.checkWrite(res.GetMetadata().GetKind()); err != nil {
+		return err
+	}
+	return s.store.Create(ctx, res)
+}
+
+func (s *typeRestrictedStore) Get(ctx context.Context, kind, namespace, name string) (*pb.Resource, error) {
+	if err := s.check(kind); err != nil {
+		return nil, err
+	}
+	return s.store.Get(ctx, kind, namespace, name)
+}
+
+func (s *typeRestrictedStore) Exists(ctx context.Context, kind, namespace, name string) (bool, error) {
+	if err := s.check(kind); err != nil {
+		return false, err
+	}
+	return s.store.Exists(ctx, kind, namespace, name)
+}
+
+func (s *typeRestrictedStore) Update(ctx context.Context, res *pb.Resource) error {
+	if err := s.checkWrite(res.GetMetadata().GetKind()); err != nil {
+		return err
+	}
+	return s.store.Update(ctx, res)
+}
+
+func (s *typeRestrictedStore) Delete(ctx context.Context, kind, namespace, name string, opts ...DeleteOption) error {
+	if err := s.checkWrite(kind); err != nil {
+		return err
+	}
+	return s.store.Delete(ctx, kind, namespace, name, opts...)
+}
+
+func (s *typeRestrictedStore) List(ctx context.Context, kind, namespace string) ([]*pb.Resource, error) {
+	if err := s.check(kind); err != nil {
+		return nil, err
+	}
+	return s.store.List(ctx, kind, namespace)
+}
+
+func (s *typeRestrictedStore) Count(ctx context.Context, kind, namespace string) (int, error) {
+	if err := s.check(kind); err != nil {
+		return 0, err
+	}
+	return s.store.Count(ctx, kind, namespace)
+}
+
+// Kinds on a type-restricted store only reports the allowed kinds.
+func (s *typeRestrictedStore) Kinds(ctx context.Context) ([]string, error) {
+	kinds, err := s.store.Kinds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		if s.check(kind) == nil {
+			allowed = append(allowed, kind)
+		}
+	}
+	return allowed, nil
+}
+
+// ListAll on a type-restricted store only returns the allowed kinds.
+func (s *typeRestrictedStore) ListAll(ctx context.Context) (map[string][]*pb.Resource, error) {
+	all, err := s.store.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for kind := range all {
+		if s.check(kind) != nil {
+			delete(all, kind)
+		}
+	}
+	return all, nil
+}
+
+func (s *typeRestrictedStore) Replace(ctx context.Context, kind string, desired []*pb.Resource) error {
+	return s.Transaction(ctx, func(tx Store) error {
+		return replaceAll(ctx, tx, kind, desired)
+	})
+}
+
+func (s *typeRestrictedStore) Close() error {
+	return s.store.Close()
+}
+
+func (s *typeRestrictedStore) Patch(ctx context.Context, kind, namespace, name string, patch []byte, patchType PatchType) (*pb.Resource, error) {
+	if err := s.checkWrite(kind); err != nil {
+		return nil, err
+	}
+	return s.store.Patch(ctx, kind, namespace, name, patch, patchType)
+}
+
+func (s *typeRestrictedStore) ListPage(ctx context.Context, kind, namespace string, opts PageOptions) (ListResult, error) {
+	if err := s.check(kind); err != nil {
+		return ListResult{}, err
+	}
+	return s.store.ListPage(ctx, kind, namespace, opts)
+}
+
+func (s *typeRestrictedStore) Watch(ctx context.Context, kind, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	if err := s.check(kind); err != nil {
+		return nil, err
+	}
+	return s.store.Watch(ctx, kind, namespace, opts...)
+}
+
+func (s *typeRestrictedStore) WatchResource(ctx context.Context, kind, namespace, name string, opts ...WatchOption) (<-chan Event, error) {
+	if err := s.check(kind); err != nil {
+		return nil, err
+	}
+	return s.store.WatchResource(ctx, kind, namespace, name, opts...)
+}
+
+func (s *typeRestrictedStore) WatchKinds(ctx context.Context, kinds []string, namespace string, opts ...WatchOption) (<-chan Event, error) {
+	for _, kind := range kinds {
+		if err := s.check(kind); err != nil {
+			return nil, err
+		}
+	}
+	return s.store.WatchKinds(ctx, kinds, namespace, opts...)
+}
+
+// WatchAll on a type-restricted store only streams the allowed kinds.
+func (s *typeRestrictedStore) WatchAll(ctx context.Context, opts ...WatchOption) (<-chan Event, error) {
+	return s.store.WatchKinds(ctx, s.readKinds, NamespaceAll, opts...)
+}
+
+func (s *typeRestrictedStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.store.Transaction(ctx, func(tx Store) error {
+		return fn(&typeRestrictedStore{
+			store:      tx,
+			readable:   s.readable,
+			writable:   s.writable,
+			readKinds:  s.readKinds,
+			writeKinds: s.writeKinds,
+		})
+	})
+}
+
+func (s *typeRestrictedStore) AddIndex(kind, indexName string, fn func(*pb.Resource) []string) error {
+	if err := s.check(kind); err != nil {
+		return err
+	}
+	return s.store.AddIndex(kind, indexName, fn)
+}
+
+func (s *typeRestrictedStore) RegisterAdmission(kind string, fn AdmissionFunc) {
+	s.store.RegisterAdmission(kind, fn)
+}
+
+func (s *typeRestrictedStore) BeginBatch() {
+	s.store.BeginBatch()
+}
+
+func (s *typeRestrictedStore) CommitBatch() {
+	s.store.CommitBatch()
+}
+
+func (s *typeRestrictedStore) GetByIndex(ctx context.Context, kind, indexName, value string) ([]*pb.Resource, error) {
+	if err := s.check(kind); err != nil {
+		return nil, err
+	}
+	return s.store.GetByIndex(ctx, kind, indexName, value)
+}
+
+func (s *typeRestrictedStore) SetObservedGeneration(ctx context.Context, kind, namespace, name string, gen int64) error {
+	if err := s.checkWrite(kind); err != nil {
+		return err
+	}
+	return s.store.SetObservedGeneration(ctx, kind, namespace, name, gen)
+}
+
+func (s *typeRestrictedStore) TransferOwner(ctx context.Context, kind, namespace, name, owner string) error {
+	if err := s.checkWrite(kind); err != nil {
+		return err
+	}
+	return s.store.TransferOwner(ctx, kind, namespace, name, owner)
+}
@@ -0,0 +1,73 @@
+package state
+
+import (
+	"context"
+
+	"github.com/microrun/microrun/api/pb"
+)
+
+// WaitFor blocks until the resource with the given kind and name satisfies
+// cond or ctx expires. cond is called with nil when the resource does not
+// exist, so callers can wait for a deletion. The watch is registered
+// before the current state is checked, so a change between the two cannot
+// be missed. It returns the resource that satisfied the condition, or nil
+// when the condition was satisfied by the resource's absence.
+func WaitFor(ctx context.Context, store Store, kind, name string, cond func(*pb.Resource) bool) (*pb.Resource, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events, err := store.Watch(ctx, kind, DefaultNamespace)
+	if err != nil {
+		return nil, err
+	}
+	res, err := store.Get(ctx, kind, DefaultNamespace, name)
+	switch {
+	case IsNotFound(err):
+		res = nil
+	case err != nil:
+		return nil, err
+	}
+	if cond(res) {
+		return res, nil
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case ev, ok := <-events:
+			if !ok {
+				return nil, ctx.Err()
+			}
+			if ev.Resource.GetMetadata().GetName() != name {
+				continue
+			}
+			res := ev.Resource
+			if ev.Type == EventDeleted {
+				res = nil
+			}
+			if cond(res) {
+				return res, nil
+			}
+		}
+	}
+}
+
+// WaitFor is the typed counterpart of the package-level WaitFor: cond is
+// called with nil when the resource does not exist, and the typed resource
+// that satisfied the condition is returned.
+func (s *TypedStore[T]) WaitFor(ctx context.Context, name string, cond func(*TypedResource[T]) bool) (*TypedResource[T], error) {
+	res, err := WaitFor(ctx, s.store, s.kind, name, func(res *pb.Resource) bool {
+		if res == nil {
+			return cond(nil)
+		}
+		typed, err := s.typed(res)
+		if err != nil {
+			return false
+		}
+		return cond(typed)
+	})
+	if err != nil || res == nil {
+		return nil, err
+	}
+	return s.typed(res)
+}
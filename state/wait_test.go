@@ -0,0 +1,125 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/microrun/microrun/api"
+	"github.com/microrun/microrun/api/pb"
+)
+
+func TestWaitForSpecChange(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		res, err := store.Get(ctx, api.KindNetworkInterface, "", "eth0")
+		if err != nil {
+			return
+		}
+		res.GetNetworkInterface().AdminState = "up"
+		store.Update(ctx, res)
+	}()
+
+	res, err := WaitFor(ctx, store, api.KindNetworkInterface, "eth0", func(res *pb.Resource) bool {
+		return res.GetNetworkInterface().GetAdminState() == "up"
+	})
+	if err != nil {
+		t.Fatalf("WaitFor: %v", err)
+	}
+	if res.GetNetworkInterface().GetAdminState() != "up" {
+		t.Errorf("admin state = %q, want up", res.GetNetworkInterface().GetAdminState())
+	}
+}
+
+func TestWaitForDeletion(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		store.Delete(ctx, api.KindNetworkInterface, "", "eth0")
+	}()
+
+	res, err := WaitFor(ctx, store, api.KindNetworkInterface, "eth0", func(res *pb.Resource) bool {
+		return res == nil
+	})
+	if err != nil {
+		t.Fatalf("WaitFor: %v", err)
+	}
+	if res != nil {
+		t.Errorf("WaitFor returned %v, want nil for deletion", res)
+	}
+}
+
+func TestWaitForAlreadySatisfied(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:ff")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	res, err := WaitFor(ctx, store, api.KindNetworkInterface, "eth0", func(res *pb.Resource) bool {
+		return res != nil
+	})
+	if err != nil {
+		t.Fatalf("WaitFor: %v", err)
+	}
+	if res.GetMetadata().GetName() != "eth0" {
+		t.Errorf("resource = %s, want eth0", res.GetMetadata().GetName())
+	}
+}
+
+func TestWaitForTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	store := NewMemoryStore()
+
+	_, err := WaitFor(ctx, store, api.KindNetworkInterface, "eth0", func(res *pb.Resource) bool {
+		return res != nil
+	})
+	if err == nil {
+		t.Fatal("WaitFor succeeded, want context error")
+	}
+}
+
+func TestTypedStoreWaitFor(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	store, err := NewTypedStore[*pb.NetworkInterface](NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewTypedStore: %v", err)
+	}
+
+	if err := store.Create(ctx, "eth0", &pb.NetworkInterface{InterfaceName: "eth0"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		store.Update(ctx, "eth0", &pb.NetworkInterface{InterfaceName: "eth0", Mtu: 9000})
+	}()
+
+	res, err := store.WaitFor(ctx, "eth0", func(res *TypedResource[*pb.NetworkInterface]) bool {
+		return res != nil && res.Spec.GetMtu() == 9000
+	})
+	if err != nil {
+		t.Fatalf("WaitFor: %v", err)
+	}
+	if res.Spec.GetMtu() != 9000 {
+		t.Errorf("mtu = %d, want 9000", res.Spec.GetMtu())
+	}
+}
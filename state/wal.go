@@ -0,0 +1,203 @@
+package state
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/microrun/microrun/api/pb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// walCompactThreshold is the number of appended entries after which the
+// log is rewritten down to one entry per live resource.
+const walCompactThreshold = 1024
+
+const (
+	walOpPut    = "put"
+	walOpDelete = "delete"
+)
+
+// walEntry is one committed mutation in the write-ahead log. A put
+// records the resource exactly as stored, metadata included, so replay
+// reconstructs generations, owners, and UIDs without rerunning store
+// logic; a delete records just the resource's identity.
+type walEntry struct {
+	Op        string          `json:"op"`
+	Kind      string          `json:"kind,omitempty"`
+	Namespace string          `json:"namespace,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Resource  json.RawMessage `json:"resource,omitempty"`
+}
+
+// walLog appends committed mutations to a JSON-lines file. It is only
+// touched under the owning store's write lock, so it needs no locking of
+// its own.
+type walLog struct {
+	path    string
+	file    *os.File
+	appends int // entries written since the last compaction
+}
+
+// NewMemoryStoreWithWAL returns an in-memory store that logs every
+// committed mutation to a JSON-lines file at path and rebuilds its state
+// from that file on startup, giving crash recovery without an embedded
+// database. The log is compacted on open and again whenever it
+// accumulates walCompactThreshold entries beyond the live state.
+func NewMemoryStoreWithWAL(path string) (Store, error) {
+	s := NewMemoryStore().(*memoryStore)
+	if err := replayWAL(path, s.data); err != nil {
+		return nil, err
+	}
+	wal, err := openWAL(path)
+	if err != nil {
+		return nil, err
+	}
+	// Start from a log that mirrors the live state exactly, dropping
+	// mutations that replay just undid or overwrote.
+	if err := wal.compact(s.data); err != nil {
+		return nil, err
+	}
+	s.wal = wal
+	return s, nil
+}
+
+// replayWAL applies the log at path to data in order. A missing file is
+// an empty log, not an error.
+func replayWAL(path string, data map[string]map[string]*pb.Resource) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var entry walEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return fmt.Errorf("write-ahead log %s line %d: %w", path, line, err)
+		}
+		switch entry.Op {
+		case walOpPut:
+			res := &pb.Resource{}
+			if err := protojson.Unmarshal(entry.Resource, res); err != nil {
+				return fmt.Errorf("write-ahead log %s line %d: %w", path, line, err)
+			}
+			kind := res.GetMetadata().GetKind()
+			if data[kind] == nil {
+				data[kind] = make(map[string]*pb.Resource)
+			}
+			data[kind][nsKey(res.GetMetadata().GetNamespace(), res.GetMetadata().GetName())] = res
+		case walOpDelete:
+			delete(data[entry.Kind], nsKey(entry.Namespace, entry.Name))
+		default:
+			return fmt.Errorf("write-ahead log %s line %d: unknown op %q", path, line, entry.Op)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading write-ahead log %s: %w", path, err)
+	}
+	return nil
+}
+
+func openWAL(path string) (*walLog, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening write-ahead log: %w", err)
+	}
+	return &walLog{path: path, file: f}, nil
+}
+
+// record appends the mutation behind ev to the log and syncs it, so the
+// entry survives a crash as soon as the mutation is visible to readers.
+func (w *walLog) record(ev Event) error {
+	md := ev.Resource.GetMetadata()
+	entry := walEntry{Op: walOpPut}
+	if ev.Type == EventDeleted {
+		entry = walEntry{
+			Op:        walOpDelete,
+			Kind:      md.GetKind(),
+			Namespace: md.GetNamespace(),
+			Name:      md.GetName(),
+		}
+	} else {
+		raw, err := protojson.Marshal(ev.Resource)
+		if err != nil {
+			return err
+		}
+		entry.Resource = raw
+	}
+	if err := w.append(entry); err != nil {
+		return err
+	}
+	w.appends++
+	return nil
+}
+
+func (w *walLog) append(entry walEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := w.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return w.file.Sync()
+}
+
+// compact rewrites the log to one put per live resource, replacing the
+// mutation history with a snapshot. The write goes to a temporary file
+// that is renamed into place, so a crash mid-compaction leaves the old
+// log intact.
+func (w *walLog) compact(data map[string]map[string]*pb.Resource) error {
+	tmp, err := os.CreateTemp(filepath.Dir(w.path), ".wal-*")
+	if err != nil {
+		return fmt.Errorf("compacting write-ahead log: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	for _, byName := range data {
+		for _, res := range byName {
+			raw, err := protojson.Marshal(res)
+			if err != nil {
+				tmp.Close()
+				return err
+			}
+			line, err := json.Marshal(walEntry{Op: walOpPut, Resource: raw})
+			if err != nil {
+				tmp.Close()
+				return err
+			}
+			if _, err := tmp.Write(append(line, '\n')); err != nil {
+				tmp.Close()
+				return err
+			}
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), w.path); err != nil {
+		return fmt.Errorf("compacting write-ahead log: %w", err)
+	}
+	w.file.Close()
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("reopening write-ahead log: %w", err)
+	}
+	w.file = f
+	w.appends = 0
+	return nil
+}
@@ -0,0 +1,150 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/microrun/microrun/api"
+	"google.golang.org/protobuf/proto"
+)
+
+func walStore(t *testing.T, path string) Store {
+	t.Helper()
+	store, err := NewMemoryStoreWithWAL(path)
+	if err != nil {
+		t.Fatalf("NewMemoryStoreWithWAL: %v", err)
+	}
+	return store
+}
+
+func TestMemoryStoreWALReplayRebuildsState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.wal")
+	ctx := context.Background()
+
+	store := walStore(t, path)
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	updated := testInterface("eth0", "aa:bb:cc:dd:ee:01")
+	updated.GetNetworkInterface().Mtu = 9000
+	if err := store.Update(ctx, updated); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	original, err := store.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	rebuilt := walStore(t, path)
+	replayed, err := rebuilt.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get after replay: %v", err)
+	}
+	if !proto.Equal(original, replayed) {
+		t.Errorf("replayed resource differs from original:\n%s", DiffResources(original, replayed))
+	}
+	if replayed.GetMetadata().GetGeneration() != 2 {
+		t.Errorf("replayed generation = %d, want 2", replayed.GetMetadata().GetGeneration())
+	}
+	if replayed.GetMetadata().GetUid() != original.GetMetadata().GetUid() {
+		t.Error("replay changed the resource UID")
+	}
+}
+
+func TestMemoryStoreWALReplayAppliesDeletes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.wal")
+	ctx := context.Background()
+
+	store := walStore(t, path)
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Create(ctx, testInterface("eth1", "aa:bb:cc:dd:ee:02")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Delete(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	rebuilt := walStore(t, path)
+	if _, err := rebuilt.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0"); !IsNotFound(err) {
+		t.Errorf("Get of deleted resource after replay = %v, want NotFoundError", err)
+	}
+	if _, err := rebuilt.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth1"); err != nil {
+		t.Errorf("Get of surviving resource after replay: %v", err)
+	}
+}
+
+func TestMemoryStoreWALReplayRestoresOwner(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.wal")
+	ctx := context.Background()
+
+	owned := NewOwnershipStore(walStore(t, path), "network-interfaces")
+	if err := owned.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	rebuilt := walStore(t, path)
+	res, err := rebuilt.Get(ctx, api.KindNetworkInterface, DefaultNamespace, "eth0")
+	if err != nil {
+		t.Fatalf("Get after replay: %v", err)
+	}
+	if got := res.GetMetadata().GetOwner(); got != "network-interfaces" {
+		t.Errorf("replayed owner = %q, want %q", got, "network-interfaces")
+	}
+}
+
+func TestMemoryStoreWALCompactsOnOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.wal")
+	ctx := context.Background()
+
+	store := walStore(t, path)
+	if err := store.Create(ctx, testInterface("eth0", "aa:bb:cc:dd:ee:01")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	for mtu := uint32(1500); mtu < 1510; mtu++ {
+		res := testInterface("eth0", "aa:bb:cc:dd:ee:01")
+		res.GetNetworkInterface().Mtu = mtu
+		if err := store.Update(ctx, res); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+	}
+
+	walStore(t, path)
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if lines := bytes.Count(raw, []byte("\n")); lines != 1 {
+		t.Errorf("compacted log holds %d entries, want 1", lines)
+	}
+}
+
+func TestMemoryStoreWALPeriodicCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.wal")
+	ctx := context.Background()
+
+	store := walStore(t, path)
+	// Churn one resource past the compaction threshold; the log must not
+	// retain the full mutation history.
+	for i := 0; i < walCompactThreshold; i++ {
+		name := fmt.Sprintf("eth%d", i%2)
+		if err := store.Create(ctx, testInterface(name, "aa:bb:cc:dd:ee:ff")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := store.Delete(ctx, api.KindNetworkInterface, DefaultNamespace, name); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if lines := bytes.Count(raw, []byte("\n")); lines >= walCompactThreshold {
+		t.Errorf("log holds %d entries after churn, want fewer than %d", lines, walCompactThreshold)
+	}
+}
@@ -0,0 +1,192 @@
+// gen-registry generates the kind registry for the api package from the
+// spec oneof of pb.Resource. Run it via go generate in the api package
+// whenever a new kind is added to the Resource proto.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/microrun/microrun/api/pb"
+)
+
+type kind struct {
+	// Name is the kind string, taken from the spec message name.
+	Name string
+	// GoType is the Go type name of the spec message.
+	GoType string
+	// Wrapper is the Go type name of the oneof wrapper struct.
+	Wrapper string
+}
+
+func main() {
+	out := flag.String("out", "registry.gen.go", "output file")
+	flag.Parse()
+
+	oneof := (&pb.Resource{}).ProtoReflect().Descriptor().Oneofs().ByName("spec")
+	if oneof == nil {
+		log.Fatal("pb.Resource has no spec oneof")
+	}
+	var kinds []kind
+	for i := 0; i < oneof.Fields().Len(); i++ {
+		field := oneof.Fields().Get(i)
+		name := string(field.Message().Name())
+		kinds = append(kinds, kind{
+			Name:    name,
+			GoType:  name,
+			Wrapper: "Resource_" + goCamelCase(string(field.Name())),
+		})
+	}
+
+	var statuses []kind
+	if statusOneof := (&pb.Resource{}).ProtoReflect().Descriptor().Oneofs().ByName("status"); statusOneof != nil {
+		for i := 0; i < statusOneof.Fields().Len(); i++ {
+			field := statusOneof.Fields().Get(i)
+			name := string(field.Message().Name())
+			statuses = append(statuses, kind{
+				Name:    name,
+				GoType:  name,
+				Wrapper: "Resource_" + goCamelCase(string(field.Name())),
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	p := func(format string, args ...any) { fmt.Fprintf(&buf, format+"\n", args...) }
+
+	p("// Code generated by gen-registry. DO NOT EDIT.")
+	p("")
+	p("package api")
+	p("")
+	p("import (")
+	p("\t\"fmt\"")
+	p("")
+	p("\t\"github.com/microrun/microrun/api/pb\"")
+	p("\t\"google.golang.org/protobuf/proto\"")
+	p(")")
+	p("")
+	p("// Kind names for every resource type in the spec oneof of pb.Resource.")
+	p("const (")
+	for _, k := range kinds {
+		p("\tKind%s = %q", k.Name, k.Name)
+	}
+	p(")")
+	p("")
+	p("// KindFor returns the kind string for a spec message type.")
+	p("func KindFor(spec proto.Message) (string, error) {")
+	p("\tswitch spec.(type) {")
+	for _, k := range kinds {
+		p("\tcase *pb.%s:", k.GoType)
+		p("\t\treturn Kind%s, nil", k.Name)
+	}
+	p("\t}")
+	p("\treturn \"\", fmt.Errorf(\"no kind registered for spec type %%T\", spec)")
+	p("}")
+	p("")
+	p("// ExtractSpec returns the populated spec message of r.")
+	p("func ExtractSpec(r *pb.Resource) (proto.Message, error) {")
+	p("\tswitch s := r.GetSpec().(type) {")
+	for _, k := range kinds {
+		p("\tcase *pb.%s:", k.Wrapper)
+		p("\t\treturn s.%s, nil", strings.TrimPrefix(k.Wrapper, "Resource_"))
+	}
+	p("\t}")
+	p("\treturn nil, fmt.Errorf(\"resource %%q has no spec set\", r.GetMetadata().GetName())")
+	p("}")
+	p("")
+	p("// AllKinds returns every registered kind, in the order the spec oneof")
+	p("// declares them.")
+	p("func AllKinds() []string {")
+	p("\treturn []string{")
+	for _, k := range kinds {
+		p("\t\tKind%s,", k.Name)
+	}
+	p("\t}")
+	p("}")
+	p("")
+	p("// NewResourceForKind returns an empty resource of the given kind with")
+	p("// metadata.kind and a zero spec of the matching type set.")
+	p("func NewResourceForKind(kind string) (*pb.Resource, error) {")
+	p("\tr := &pb.Resource{Metadata: &pb.ResourceMetadata{Kind: kind}}")
+	p("\tswitch kind {")
+	for _, k := range kinds {
+		p("\tcase Kind%s:", k.Name)
+		p("\t\tr.Spec = &pb.%s{%s: &pb.%s{}}", k.Wrapper, strings.TrimPrefix(k.Wrapper, "Resource_"), k.GoType)
+	}
+	p("\tdefault:")
+	p("\t\treturn nil, fmt.Errorf(\"no kind registered for %%q\", kind)")
+	p("\t}")
+	p("\treturn r, nil")
+	p("}")
+	p("")
+	p("// SetSpec stores spec into the spec oneof of r.")
+	p("func SetSpec(r *pb.Resource, spec proto.Message) error {")
+	p("\tswitch s := spec.(type) {")
+	for _, k := range kinds {
+		p("\tcase *pb.%s:", k.GoType)
+		p("\t\tr.Spec = &pb.%s{%s: s}", k.Wrapper, strings.TrimPrefix(k.Wrapper, "Resource_"))
+	}
+	p("\tdefault:")
+	p("\t\treturn fmt.Errorf(\"no kind registered for spec type %%T\", spec)")
+	p("\t}")
+	p("\treturn nil")
+	p("}")
+	p("")
+	p("// ExtractStatus returns the populated status message of r, or nil when")
+	p("// the resource has no status set.")
+	p("func ExtractStatus(r *pb.Resource) proto.Message {")
+	p("\tswitch s := r.GetStatus().(type) {")
+	for _, k := range statuses {
+		p("\tcase *pb.%s:", k.Wrapper)
+		p("\t\treturn s.%s", strings.TrimPrefix(k.Wrapper, "Resource_"))
+	}
+	p("\t}")
+	p("\treturn nil")
+	p("}")
+	p("")
+	p("// SetStatus stores status into the status oneof of r.")
+	p("func SetStatus(r *pb.Resource, status proto.Message) error {")
+	p("\tswitch s := status.(type) {")
+	for _, k := range statuses {
+		p("\tcase *pb.%s:", k.GoType)
+		p("\t\tr.Status = &pb.%s{%s: s}", k.Wrapper, strings.TrimPrefix(k.Wrapper, "Resource_"))
+	}
+	p("\tdefault:")
+	p("\t\treturn fmt.Errorf(\"no status type registered for %%T\", status)")
+	p("\t}")
+	p("\treturn nil")
+	p("}")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("formatting generated code: %v", err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// goCamelCase mirrors protoc-gen-go's conversion of a proto field name to
+// its Go name, so the generated oneof wrapper names line up.
+func goCamelCase(s string) string {
+	var b strings.Builder
+	up := true
+	for _, r := range s {
+		switch {
+		case r == '_':
+			up = true
+		case up:
+			b.WriteRune(unicode.ToUpper(r))
+			up = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}